@@ -0,0 +1,238 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"imagio/manipulations"
+	"imagio/orthogonal_transforms"
+	"math/cmplx"
+	"strings"
+)
+
+// ErrSizeMismatch is returned when two compared images do not share dimensions, every comparison metric
+// and difference image checks for it before reading pixels.
+var ErrSizeMismatch = errors.New("images have different sizes")
+
+// AlignmentMode decides how two images are brought to a common size before they are compared.
+type AlignmentMode int
+
+const (
+	// AlignNone requires both images to have the same size.
+	AlignNone AlignmentMode = iota
+	// AlignOverlap compares only the area both images cover, anchored at the top left corner.
+	AlignOverlap
+	// AlignResize scales the second image to the size of the first one.
+	AlignResize
+	// AlignTranslation estimates the shift between the images with phase correlation
+	// and compares the area they share after the shift.
+	AlignTranslation
+)
+
+func (m AlignmentMode) String() string {
+	switch m {
+	case AlignNone:
+		return "none"
+	case AlignOverlap:
+		return "overlap"
+	case AlignResize:
+		return "resize"
+	case AlignTranslation:
+		return "translate"
+	default:
+		return fmt.Sprintf("AlignmentMode(%d)", int(m))
+	}
+}
+
+// AvailableAlignmentModes lists the names accepted by ParseAlignmentMode.
+func AvailableAlignmentModes() []string {
+	return []string{AlignNone.String(), AlignOverlap.String(), AlignResize.String(), AlignTranslation.String()}
+}
+
+// ParseAlignmentMode parses an alignment mode name, an empty string selects AlignNone.
+func ParseAlignmentMode(value string) (AlignmentMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "none":
+		return AlignNone, nil
+	case "overlap", "crop":
+		return AlignOverlap, nil
+	case "resize":
+		return AlignResize, nil
+	case "translate", "translation", "phase":
+		return AlignTranslation, nil
+	default:
+		return AlignNone, fmt.Errorf("unknown alignment mode %q, expected one of: %s", value, strings.Join(AvailableAlignmentModes(), ", "))
+	}
+}
+
+// CheckSameSize returns ErrSizeMismatch when the images do not share dimensions.
+func CheckSameSize(img1, img2 image.Image) error {
+	b1, b2 := img1.Bounds(), img2.Bounds()
+	if b1.Dx() != b2.Dx() || b1.Dy() != b2.Dy() {
+		return fmt.Errorf("%w: %dx%d and %dx%d", ErrSizeMismatch, b1.Dx(), b1.Dy(), b2.Dx(), b2.Dy())
+	}
+	return nil
+}
+
+// matchOrigin checks the sizes like CheckSameSize and returns img2 moved onto the bounds of img1,
+// the metrics read both images with the coordinates of img1, e.g. of a sub-image placed elsewhere.
+func matchOrigin(img1, img2 image.Image) (image.Image, error) {
+	if err := CheckSameSize(img1, img2); err != nil {
+		return nil, err
+	}
+
+	offset := img2.Bounds().Min.Sub(img1.Bounds().Min)
+	if offset == (image.Point{}) {
+		return img2, nil
+	}
+	return translatedImage{Image: img2, offset: offset}, nil
+}
+
+// translatedImage shows the image with its bounds moved by -offset.
+type translatedImage struct {
+	image.Image
+	offset image.Point
+}
+
+func (t translatedImage) Bounds() image.Rectangle {
+	return t.Image.Bounds().Sub(t.offset)
+}
+
+func (t translatedImage) At(x, y int) color.Color {
+	return t.Image.At(x+t.offset.X, y+t.offset.Y)
+}
+
+// cropImage copies the rectangle of the image into a new image anchored at (0, 0).
+func cropImage(img image.Image, rect image.Rectangle) *image.RGBA {
+	output := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(output, output.Bounds(), img, rect.Min, draw.Src)
+	return output
+}
+
+// AlignImages brings both images to a common size according to the mode.
+// With AlignNone the images are returned untouched, or ErrSizeMismatch if their sizes differ.
+func AlignImages(img1, img2 image.Image, mode AlignmentMode) (image.Image, image.Image, error) {
+	b1, b2 := img1.Bounds(), img2.Bounds()
+
+	switch mode {
+	case AlignNone:
+		matched, err := matchOrigin(img1, img2)
+		if err != nil {
+			return nil, nil, err
+		}
+		return img1, matched, nil
+
+	case AlignOverlap:
+		width, height := min(b1.Dx(), b2.Dx()), min(b1.Dy(), b2.Dy())
+		return cropImage(img1, image.Rect(0, 0, width, height).Add(b1.Min)),
+			cropImage(img2, image.Rect(0, 0, width, height).Add(b2.Min)), nil
+
+	case AlignResize:
+		if matched, err := matchOrigin(img1, img2); err == nil {
+			return img1, matched, nil
+		}
+		resized, err := manipulations.ResizeImage(img2, b1.Dx(), b1.Dy())
+		if err != nil {
+			return nil, nil, err
+		}
+		matched, err := matchOrigin(img1, resized)
+		return img1, matched, err
+
+	case AlignTranslation:
+		dx, dy := EstimateTranslation(img1, img2)
+
+		// img1(x, y) corresponds to img2(x+dx, y+dy)
+		minX, minY := max(0, -dx), max(0, -dy)
+		maxX, maxY := min(b1.Dx(), b2.Dx()-dx), min(b1.Dy(), b2.Dy()-dy)
+		if maxX <= minX || maxY <= minY {
+			return nil, nil, fmt.Errorf("images do not overlap after shifting by (%d, %d)", dx, dy)
+		}
+
+		overlap := image.Rect(minX, minY, maxX, maxY)
+		return cropImage(img1, overlap.Add(b1.Min)), cropImage(img2, overlap.Add(b2.Min).Add(image.Pt(dx, dy))), nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown alignment mode %s", mode)
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	power := 1
+	for power < n {
+		power <<= 1
+	}
+	return power
+}
+
+// zeroMeanPaddedLuma returns the zero-mean luma of the image padded with zeros to the given size.
+func zeroMeanPaddedLuma(img image.Image, width, height int) [][]complex128 {
+	luma := luminanceMatrix(img)
+
+	var mean float64
+	for _, row := range luma {
+		for _, v := range row {
+			mean += v
+		}
+	}
+	mean /= float64(len(luma) * len(luma[0]))
+
+	output := make([][]complex128, height)
+	for y := range output {
+		output[y] = make([]complex128, width)
+		if y >= len(luma) {
+			continue
+		}
+		for x := range luma[y] {
+			output[y][x] = complex(luma[y][x]-mean, 0)
+		}
+	}
+
+	return output
+}
+
+// EstimateTranslation finds the shift (dx, dy) for which img1(x, y) best matches img2(x+dx, y+dy)
+// using phase correlation. Both images are padded to a common power of two size for the FFT.
+// Reference: https://en.wikipedia.org/wiki/Phase_correlation
+func EstimateTranslation(img1, img2 image.Image) (dx, dy int) {
+	b1, b2 := img1.Bounds(), img2.Bounds()
+	width := nextPowerOfTwo(max(b1.Dx(), b2.Dx()))
+	height := nextPowerOfTwo(max(b1.Dy(), b2.Dy()))
+
+	spectrum1 := orthogonal_transforms.FFT2D(zeroMeanPaddedLuma(img1, width, height), false)
+	spectrum2 := orthogonal_transforms.FFT2D(zeroMeanPaddedLuma(img2, width, height), false)
+
+	crossPower := make([][]complex128, height)
+	for y := range crossPower {
+		crossPower[y] = make([]complex128, width)
+		for x := range crossPower[y] {
+			product := spectrum2[y][x] * cmplx.Conj(spectrum1[y][x])
+			if magnitude := cmplx.Abs(product); magnitude > 1e-12 {
+				crossPower[y][x] = product / complex(magnitude, 0)
+			}
+		}
+	}
+
+	correlation := orthogonal_transforms.FFT2D(crossPower, true)
+
+	peak := -1.0
+	for y := range correlation {
+		for x := range correlation[y] {
+			if value := real(correlation[y][x]); value > peak {
+				peak = value
+				dx, dy = x, y
+			}
+		}
+	}
+
+	// the correlation is circular, shifts past the middle are negative
+	if dx > width/2 {
+		dx -= width
+	}
+	if dy > height/2 {
+		dy -= height
+	}
+
+	return dx, dy
+}
@@ -0,0 +1,62 @@
+package analysis
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+)
+
+func TestEstimateTranslation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := image.NewRGBA(image.Rect(0, 0, 80, 70))
+	for y := 0; y < 70; y++ {
+		for x := 0; x < 80; x++ {
+			v := uint8(rng.Intn(256))
+			base.Set(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+
+	const dx, dy = 5, -3
+
+	// shifted(x+dx, y+dy) = base(x, y)
+	shifted := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			shifted.Set(x, y, base.At(x-dx, y-dy))
+		}
+	}
+
+	gotX, gotY := EstimateTranslation(base, shifted)
+	if gotX != dx || gotY != dy {
+		t.Fatalf("EstimateTranslation = (%d, %d), expected (%d, %d)", gotX, gotY, dx, dy)
+	}
+
+	aligned1, aligned2, err := AlignImages(base, shifted, AlignTranslation)
+	if err != nil {
+		t.Fatalf("AlignImages returned error: %v", err)
+	}
+	if mse, err := MeanSquareError(aligned1, aligned2); err != nil || mse != 0 {
+		t.Errorf("MSE after translation alignment = %f, expected 0", mse)
+	}
+}
+
+func TestAlignImagesSizeMismatch(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	img2 := image.NewRGBA(image.Rect(0, 0, 6, 10))
+
+	if _, _, err := AlignImages(img1, img2, AlignNone); !errors.Is(err, ErrSizeMismatch) {
+		t.Errorf("AlignImages without alignment error = %v, expected ErrSizeMismatch", err)
+	}
+
+	a, b, err := AlignImages(img1, img2, AlignOverlap)
+	if err != nil || a.Bounds() != image.Rect(0, 0, 6, 8) || b.Bounds() != image.Rect(0, 0, 6, 8) {
+		t.Errorf("AlignImages overlap = %v, %v, %v, expected two 6x8 images", a.Bounds(), b.Bounds(), err)
+	}
+
+	a, b, err = AlignImages(img1, img2, AlignResize)
+	if err != nil || a.Bounds() != b.Bounds() {
+		t.Errorf("AlignImages resize = %v, %v, %v, expected matching bounds", a.Bounds(), b.Bounds(), err)
+	}
+}
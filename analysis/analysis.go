@@ -27,7 +27,15 @@ func pixelSquaredDifference(img1, img2 image.Image) (totalR, totalG, totalB floa
 	return totalR, totalG, totalB
 }
 
-func MeanSquareError(img1, img2 image.Image) float64 {
+func MeanSquareError(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return meanSquareError(img1, img2), nil
+}
+
+func meanSquareError(img1, img2 image.Image) float64 {
 	totalR, totalG, totalB := pixelSquaredDifference(img1, img2)
 
 	pixelsInTotal := float64(img1.Bounds().Dx() * img1.Bounds().Dy())
@@ -53,7 +61,15 @@ func maxPixelValue(img image.Image) int {
 	return maxVal
 }
 
-func PeakMeanSquareError(img1, img2 image.Image) float64 {
+func PeakMeanSquareError(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return peakMeanSquareError(img1, img2), nil
+}
+
+func peakMeanSquareError(img1, img2 image.Image) float64 {
 	bounds := img1.Bounds()
 	totalError := 0.0
 
@@ -82,7 +98,15 @@ func PeakMeanSquareError(img1, img2 image.Image) float64 {
 	return totalError / (3 * pixelsInTotal)
 }
 
-func SignalToNoiseRatio(img1, img2 image.Image) float64 {
+func SignalToNoiseRatio(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return signalToNoiseRatio(img1, img2), nil
+}
+
+func signalToNoiseRatio(img1, img2 image.Image) float64 {
 	bounds := img1.Bounds()
 
 	var signalSum, noiseSum float64
@@ -101,23 +125,32 @@ func SignalToNoiseRatio(img1, img2 image.Image) float64 {
 		}
 	}
 
-	if signalSum == 0 {
-		return math.Inf(1)
-	}
-
 	// (no noise) ergo images are identical
 	if noiseSum == 0 {
 		return math.Inf(1)
 	}
 
+	// a black reference image carries no signal at all
+	if signalSum == 0 {
+		return math.Inf(-1)
+	}
+
 	return 10 * math.Log10(signalSum/noiseSum)
 }
 
-func PeakSignalToNoiseRatio(img1, img2 image.Image) float64 {
+func PeakSignalToNoiseRatio(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return peakSignalToNoiseRatio(img1, img2), nil
+}
+
+func peakSignalToNoiseRatio(img1, img2 image.Image) float64 {
 	// calculate the max pixel value in the original image rather than hardcoding it to 255
 	maxValue := float64(maxPixelValue(img1))
 
-	mseValue := MeanSquareError(img1, img2)
+	mseValue := meanSquareError(img1, img2)
 
 	if mseValue == 0 {
 		return math.Inf(1)
@@ -140,7 +173,15 @@ func max(a, b int) int {
 	return b
 }
 
-func MaxDifference(img1, img2 image.Image) int {
+func MaxDifference(img1, img2 image.Image) (int, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return maxDifference(img1, img2), nil
+}
+
+func maxDifference(img1, img2 image.Image) int {
 	bounds := img1.Bounds()
 
 	var maxDiff int
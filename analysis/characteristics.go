@@ -0,0 +1,123 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"imagio/manipulations"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownMetric is returned when a characteristic is requested under a name that is not supported.
+var ErrUnknownMetric = errors.New("unknown metric")
+
+// Unit describes what a characteristic value is measured in.
+type Unit string
+
+const (
+	UnitNone    Unit = ""
+	UnitDecibel Unit = "dB"
+	UnitBits    Unit = "bits"
+	UnitPercent Unit = "%"
+)
+
+// Channel names the color channel a value was calculated for.
+type Channel string
+
+const (
+	// ChannelCombined marks values calculated over all channels, or over luma for single channel metrics.
+	ChannelCombined Channel = ""
+	ChannelRed      Channel = "R"
+	ChannelGreen    Channel = "G"
+	ChannelBlue     Channel = "B"
+	ChannelValue    Channel = "V"
+	ChannelLuma     Channel = "Luma"
+)
+
+// HistogramChannelOf returns the channel label of values calculated from a histogram of the given channel.
+func HistogramChannelOf(channel manipulations.HistogramChannel) Channel {
+	switch channel {
+	case manipulations.HistogramRed:
+		return ChannelRed
+	case manipulations.HistogramGreen:
+		return ChannelGreen
+	case manipulations.HistogramBlue:
+		return ChannelBlue
+	case manipulations.HistogramLuma:
+		return ChannelLuma
+	default:
+		return ChannelValue
+	}
+}
+
+// MetricValue is a single numeric result of a characteristic.
+type MetricValue struct {
+	Value   float64
+	Unit    Unit
+	Channel Channel
+}
+
+// FormatNumber renders the value without its unit, integral values are printed without decimals.
+func (v MetricValue) FormatNumber() string {
+	if !math.IsInf(v.Value, 0) && v.Value == math.Trunc(v.Value) {
+		return strconv.FormatFloat(v.Value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(v.Value, 'f', 6, 64)
+}
+
+// String renders the value followed by its unit.
+func (v MetricValue) String() string {
+	if v.Unit == UnitNone {
+		return v.FormatNumber()
+	}
+	return v.FormatNumber() + " " + string(v.Unit)
+}
+
+type CharacteristicsEntry struct {
+	MetricMethod string
+	Description  string
+	// Label is the human readable name of the value, e.g. "MSE".
+	Label string
+	MetricValue
+	// ChannelValues holds the red, green and blue values when they were requested.
+	ChannelValues []MetricValue
+	Img1Name      string
+	Img2Name      string
+}
+
+// FormatValue renders the value with its unit and the per channel values, e.g. "38.297803 dB (R=38.1 G=38.4 B=38.3)".
+func (e CharacteristicsEntry) FormatValue() string {
+	value := e.MetricValue.String()
+	if len(e.ChannelValues) == 0 {
+		return value
+	}
+
+	channels := make([]string, 0, len(e.ChannelValues))
+	for _, channelValue := range e.ChannelValues {
+		channels = append(channels, fmt.Sprintf("%s=%s", channelValue.Channel, channelValue.FormatNumber()))
+	}
+
+	return fmt.Sprintf("%s (%s)", value, strings.Join(channels, " "))
+}
+
+// FormatLabel renders the label followed by the channel when the value belongs to a single channel, e.g. "Mean [R]".
+func (e CharacteristicsEntry) FormatLabel() string {
+	if e.Channel == ChannelCombined {
+		return e.Label
+	}
+	return fmt.Sprintf("%s [%s]", e.Label, e.Channel)
+}
+
+// FormatResult renders the labeled value, e.g. "MSE: 8.242210".
+func (e CharacteristicsEntry) FormatResult() string {
+	return fmt.Sprintf("%s: %s", e.FormatLabel(), e.FormatValue())
+}
+
+func channelValues(r, g, b float64) []MetricValue {
+	return []MetricValue{
+		{Value: r, Channel: ChannelRed},
+		{Value: g, Channel: ChannelGreen},
+		{Value: b, Channel: ChannelBlue},
+	}
+}
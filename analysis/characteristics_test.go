@@ -0,0 +1,52 @@
+package analysis
+
+import (
+	"errors"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestCharacteristicsEntryFormatting(t *testing.T) {
+	tests := []struct {
+		entry    CharacteristicsEntry
+		expected string
+	}{
+		{CharacteristicsEntry{Label: "MSE", MetricValue: MetricValue{Value: 8.24221}}, "MSE: 8.242210"},
+		{CharacteristicsEntry{Label: "Max Difference", MetricValue: MetricValue{Value: 203}}, "Max Difference: 203"},
+		{CharacteristicsEntry{Label: "PSNR", MetricValue: MetricValue{Value: math.Inf(1), Unit: UnitDecibel}}, "PSNR: +Inf dB"},
+		{
+			CharacteristicsEntry{
+				Label:         "SNR",
+				MetricValue:   MetricValue{Value: 33.5, Unit: UnitDecibel},
+				ChannelValues: []MetricValue{{Value: 33, Channel: ChannelRed}, {Value: 34.25, Channel: ChannelGreen}, {Value: 33.5, Channel: ChannelBlue}},
+			},
+			"SNR: 33.500000 dB (R=33 G=34.250000 B=33.500000)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.entry.FormatResult(); got != tt.expected {
+			t.Errorf("FormatResult() = %q, expected %q", got, tt.expected)
+		}
+	}
+}
+
+func TestUnknownMetric(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	if _, err := CalculateComparisonCharacteristic("nope", img, img, ComparisonOptions{}); !errors.Is(err, ErrUnknownMetric) {
+		t.Errorf("CalculateComparisonCharacteristic error = %v, expected ErrUnknownMetric", err)
+	}
+	if _, err := CalculateHistogramCharacteristic("nope", [256]int{}, "img"); !errors.Is(err, ErrUnknownMetric) {
+		t.Errorf("CalculateHistogramCharacteristic error = %v, expected ErrUnknownMetric", err)
+	}
+
+	entry, err := CalculateComparisonCharacteristic("PSNR", img, img, ComparisonOptions{PerChannel: true})
+	if err != nil {
+		t.Fatalf("CalculateComparisonCharacteristic returned error: %v", err)
+	}
+	if !math.IsInf(entry.Value, 1) || entry.Unit != UnitDecibel || len(entry.ChannelValues) != 3 || entry.ChannelValues[0].Unit != UnitDecibel {
+		t.Errorf("PSNR of identical images = %+v, expected +Inf dB with 3 channel values", entry)
+	}
+}
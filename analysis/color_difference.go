@@ -0,0 +1,173 @@
+package analysis
+
+import (
+	"image"
+	"math"
+)
+
+// Lab is a color in the CIE L*a*b* space.
+type Lab struct {
+	L, A, B float64
+}
+
+// D65 reference white used for the sRGB -> XYZ conversion.
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+func linearizeSRGB(c uint8) float64 {
+	v := float64(c) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29.0
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29.0
+}
+
+// RGBToLab converts an 8-bit sRGB color to CIE L*a*b* under the D65 illuminant.
+func RGBToLab(r, g, b uint8) Lab {
+	lr, lg, lb := linearizeSRGB(r), linearizeSRGB(g), linearizeSRGB(b)
+
+	x := 0.4124564*lr + 0.3575761*lg + 0.1804375*lb
+	y := 0.2126729*lr + 0.7151522*lg + 0.0721750*lb
+	z := 0.0193339*lr + 0.1191920*lg + 0.9503041*lb
+
+	fx, fy, fz := labF(x/whiteX), labF(y/whiteY), labF(z/whiteZ)
+
+	return Lab{
+		L: 116*fy - 16,
+		A: 500 * (fx - fy),
+		B: 200 * (fy - fz),
+	}
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// DeltaE2000 calculates the CIEDE2000 color difference between two L*a*b* colors.
+// Reference: https://hajim.rochester.edu/ece/sites/gsharma/ciede2000/ciede2000noteCRNA.pdf
+func DeltaE2000(c1, c2 Lab) float64 {
+	const pow25To7 = 6103515625.0 // 25^7
+
+	cab1 := math.Hypot(c1.A, c1.B)
+	cab2 := math.Hypot(c2.A, c2.B)
+	cabMean7 := math.Pow((cab1+cab2)/2, 7)
+
+	g := 0.5 * (1 - math.Sqrt(cabMean7/(cabMean7+pow25To7)))
+
+	a1 := (1 + g) * c1.A
+	a2 := (1 + g) * c2.A
+
+	chroma1 := math.Hypot(a1, c1.B)
+	chroma2 := math.Hypot(a2, c2.B)
+
+	hue := func(b, a float64) float64 {
+		if a == 0 && b == 0 {
+			return 0
+		}
+		h := degrees(math.Atan2(b, a))
+		if h < 0 {
+			h += 360
+		}
+		return h
+	}
+	h1 := hue(c1.B, a1)
+	h2 := hue(c2.B, a2)
+
+	deltaL := c2.L - c1.L
+	deltaC := chroma2 - chroma1
+
+	chromaProduct := chroma1 * chroma2
+
+	var deltaHue float64
+	if chromaProduct != 0 {
+		deltaHue = h2 - h1
+		if deltaHue > 180 {
+			deltaHue -= 360
+		} else if deltaHue < -180 {
+			deltaHue += 360
+		}
+	}
+	deltaH := 2 * math.Sqrt(chromaProduct) * math.Sin(radians(deltaHue)/2)
+
+	meanL := (c1.L + c2.L) / 2
+	meanC := (chroma1 + chroma2) / 2
+
+	meanH := h1 + h2
+	if chromaProduct != 0 {
+		switch {
+		case math.Abs(h1-h2) <= 180:
+			meanH /= 2
+		case h1+h2 < 360:
+			meanH = (meanH + 360) / 2
+		default:
+			meanH = (meanH - 360) / 2
+		}
+	}
+
+	t := 1 -
+		0.17*math.Cos(radians(meanH-30)) +
+		0.24*math.Cos(radians(2*meanH)) +
+		0.32*math.Cos(radians(3*meanH+6)) -
+		0.20*math.Cos(radians(4*meanH-63))
+
+	deltaTheta := 30 * math.Exp(-math.Pow((meanH-275)/25, 2))
+	meanC7 := math.Pow(meanC, 7)
+	rc := 2 * math.Sqrt(meanC7/(meanC7+pow25To7))
+
+	meanLShift := (meanL - 50) * (meanL - 50)
+	sl := 1 + 0.015*meanLShift/math.Sqrt(20+meanLShift)
+	sc := 1 + 0.045*meanC
+	sh := 1 + 0.015*meanC*t
+	rt := -math.Sin(radians(2*deltaTheta)) * rc
+
+	termL := deltaL / sl
+	termC := deltaC / sc
+	termH := deltaH / sh
+
+	return math.Sqrt(termL*termL + termC*termC + termH*termH + rt*termC*termH)
+}
+
+// MeanColorDifference calculates the average CIEDE2000 difference between corresponding pixels of two images.
+func MeanColorDifference(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return meanColorDifference(img1, img2), nil
+}
+
+func meanColorDifference(img1, img2 image.Image) float64 {
+	bounds := img1.Bounds()
+	var total float64
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := img1.At(x, y).RGBA()
+			r2, g2, b2, _ := img2.At(x, y).RGBA()
+
+			lab1 := RGBToLab(uint8(r1>>8), uint8(g1>>8), uint8(b1>>8))
+			lab2 := RGBToLab(uint8(r2>>8), uint8(g2>>8), uint8(b2>>8))
+
+			total += DeltaE2000(lab1, lab2)
+		}
+	}
+
+	pixelsInTotal := float64(bounds.Dx() * bounds.Dy())
+
+	return total / pixelsInTotal
+}
@@ -6,54 +6,117 @@ import (
 	"strings"
 )
 
-type CharacteristicsEntry struct {
-	MetricMethod string
-	Description  string
-	Result       string
-	Img1Name     string
-	Img2Name     string
+// ComparisonOptions holds parameters of comparison metrics that are configurable.
+// The zero value selects the defaults of every metric.
+type ComparisonOptions struct {
+	SSIM SSIMOptions
+	// PerChannel adds the red, green and blue values to metrics that are computed over color channels.
+	PerChannel bool
+	// Alignment decides how images of different sizes or shifted content are matched before comparing.
+	Alignment AlignmentMode
 }
 
-func CalculateComparisonCharacteristic(metricMethod string, img1, img2 image.Image) CharacteristicsEntry {
-	var result string
-	var description string
+type channelMetric func(img1, img2 image.Image) (r, g, b float64)
+
+func floatMaxDifferencePerChannel(img1, img2 image.Image) (r, g, b float64) {
+	mdR, mdG, mdB := maxDifferencePerChannel(img1, img2)
+	return float64(mdR), float64(mdG), float64(mdB)
+}
+
+func CalculateComparisonCharacteristic(metricMethod string, img1, img2 image.Image, opts ComparisonOptions) (CharacteristicsEntry, error) {
+	img1, img2, err := AlignImages(img1, img2, opts.Alignment)
+	if err != nil {
+		return CharacteristicsEntry{}, err
+	}
+
+	entry := CharacteristicsEntry{MetricMethod: strings.ToUpper(metricMethod)}
+
+	// perChannel is set for metrics that can also be reported for every color channel
+	var perChannel channelMetric
 
 	lowerMetricMethod := strings.ToLower(strings.Trim(metricMethod, " "))
 
 	switch lowerMetricMethod {
 	case "mse":
-		mse := MeanSquareError(img1, img2)
-		description = "Mean Square Error calculated"
-		result = fmt.Sprintf("MSE: %f", mse)
+		entry.Value = meanSquareError(img1, img2)
+		entry.Label = "MSE"
+		entry.Description = "Mean Square Error calculated"
+		perChannel = meanSquareErrorPerChannel
 
 	case "pmse":
-		pmse := PeakMeanSquareError(img1, img2)
-		description = "Peak Mean Square Error calculated"
-		result = fmt.Sprintf("PMSE: %f", pmse)
+		entry.Value = peakMeanSquareError(img1, img2)
+		entry.Label = "PMSE"
+		entry.Description = "Peak Mean Square Error calculated"
+		perChannel = peakMeanSquareErrorPerChannel
 
 	case "snr":
-		snr := SignalToNoiseRatio(img1, img2)
-		description = "Signal to Noise Ratio calculated"
-		result = fmt.Sprintf("SNR: %f", snr)
+		entry.Value = signalToNoiseRatio(img1, img2)
+		entry.Unit = UnitDecibel
+		entry.Label = "SNR"
+		entry.Description = "Signal to Noise Ratio calculated"
+		perChannel = signalToNoiseRatioPerChannel
 
 	case "psnr":
-		psnr := PeakSignalToNoiseRatio(img1, img2)
-		description = "Peak Signal to Noise Ratio calculated"
-		result = fmt.Sprintf("PSNR: %f", psnr)
+		entry.Value = peakSignalToNoiseRatio(img1, img2)
+		entry.Unit = UnitDecibel
+		entry.Label = "PSNR"
+		entry.Description = "Peak Signal to Noise Ratio calculated"
+		perChannel = peakSignalToNoiseRatioPerChannel
 
 	case "md":
-		md := MaxDifference(img1, img2)
-		description = "Max Difference calculated"
-		result = fmt.Sprintf("Max Difference: %d", md)
+		entry.Value = float64(maxDifference(img1, img2))
+		entry.Label = "Max Difference"
+		entry.Description = "Max Difference calculated"
+		perChannel = floatMaxDifferencePerChannel
+
+	case "ssim":
+		entry.Value = structuralSimilarity(img1, img2, opts.SSIM)
+		entry.Label = "SSIM"
+		entry.Description = "Structural Similarity Index calculated"
+
+	case "ms-ssim", "msssim":
+		entry.Value = multiScaleStructuralSimilarity(img1, img2, opts.SSIM)
+		entry.Label = "MS-SSIM"
+		entry.Description = "Multi-Scale Structural Similarity Index calculated"
+
+	case "psnr-rgb":
+		entry.Value = peakSignalToNoiseRatio(img1, img2)
+		entry.Unit = UnitDecibel
+		entry.Label = "PSNR"
+		entry.Description = "Peak Signal to Noise Ratio per channel calculated"
+		entry.ChannelValues = channelValues(peakSignalToNoiseRatioPerChannel(img1, img2))
+
+	case "mae":
+		entry.Value = meanAbsoluteError(img1, img2)
+		entry.Label = "MAE"
+		entry.Description = "Mean Absolute Error calculated"
+		perChannel = meanAbsoluteErrorPerChannel
+
+	case "ncc":
+		entry.Value = normalizedCrossCorrelation(img1, img2)
+		entry.Label = "NCC"
+		entry.Description = "Normalized Cross-Correlation calculated"
+
+	case "ciede2000":
+		entry.Value = meanColorDifference(img1, img2)
+		entry.Label = "Mean Delta E 2000"
+		entry.Description = "Mean CIEDE2000 color difference calculated"
 
 	default:
-		description = "Unknown metric"
-		result = "N/A"
+		return CharacteristicsEntry{}, fmt.Errorf("%w: %q", ErrUnknownMetric, metricMethod)
+	}
+
+	if opts.PerChannel && perChannel != nil {
+		entry.ChannelValues = channelValues(perChannel(img1, img2))
+	}
+
+	for i := range entry.ChannelValues {
+		entry.ChannelValues[i].Unit = entry.Unit
 	}
 
-	return CharacteristicsEntry{
-		MetricMethod: strings.ToUpper(metricMethod),
-		Description:  description,
-		Result:       result,
+	if opts.Alignment != AlignNone {
+		entry.Description += fmt.Sprintf(" (%s alignment)", opts.Alignment)
 	}
+
+	return entry, nil
 }
@@ -0,0 +1,217 @@
+package analysis
+
+import (
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"math"
+)
+
+// MeanSquareErrorPerChannel calculates the mean square error separately for the red, green and blue channels.
+func MeanSquareErrorPerChannel(img1, img2 image.Image) (r, g, b float64, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = meanSquareErrorPerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func meanSquareErrorPerChannel(img1, img2 image.Image) (r, g, b float64) {
+	totalR, totalG, totalB := pixelSquaredDifference(img1, img2)
+
+	pixelsInTotal := float64(img1.Bounds().Dx() * img1.Bounds().Dy())
+
+	return totalR / pixelsInTotal, totalG / pixelsInTotal, totalB / pixelsInTotal
+}
+
+// PeakMeanSquareErrorPerChannel calculates the mean square error of every channel
+// normalized by the squared max pixel value of the first image.
+func PeakMeanSquareErrorPerChannel(img1, img2 image.Image) (r, g, b float64, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = peakMeanSquareErrorPerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func peakMeanSquareErrorPerChannel(img1, img2 image.Image) (r, g, b float64) {
+	maxVal := float64(maxPixelValue(img1))
+	maxValSquared := maxVal * maxVal
+
+	r, g, b = meanSquareErrorPerChannel(img1, img2)
+
+	return r / maxValSquared, g / maxValSquared, b / maxValSquared
+}
+
+// SignalToNoiseRatioPerChannel calculates the signal to noise ratio separately for the red, green and blue channels.
+func SignalToNoiseRatioPerChannel(img1, img2 image.Image) (r, g, b float64, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = signalToNoiseRatioPerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func signalToNoiseRatioPerChannel(img1, img2 image.Image) (r, g, b float64) {
+	bounds := img1.Bounds()
+
+	var signal, noise [3]float64
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := img1.At(x, y).RGBA()
+			r2, g2, b2, _ := img2.At(x, y).RGBA()
+
+			channels1 := [3]float64{float64(r1 >> 8), float64(g1 >> 8), float64(b1 >> 8)}
+			channels2 := [3]float64{float64(r2 >> 8), float64(g2 >> 8), float64(b2 >> 8)}
+
+			for c := range channels1 {
+				signal[c] += channels1[c] * channels1[c]
+				noise[c] += (channels1[c] - channels2[c]) * (channels1[c] - channels2[c])
+			}
+		}
+	}
+
+	snr := func(c int) float64 {
+		// (no noise) ergo channels are identical
+		if noise[c] == 0 {
+			return math.Inf(1)
+		}
+		// a black reference channel carries no signal at all
+		if signal[c] == 0 {
+			return math.Inf(-1)
+		}
+		return 10 * math.Log10(signal[c]/noise[c])
+	}
+
+	return snr(0), snr(1), snr(2)
+}
+
+// MeanAbsoluteErrorPerChannel calculates the mean absolute difference separately for the red, green and blue channels.
+func MeanAbsoluteErrorPerChannel(img1, img2 image.Image) (r, g, b float64, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = meanAbsoluteErrorPerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func meanAbsoluteErrorPerChannel(img1, img2 image.Image) (r, g, b float64) {
+	bounds := img1.Bounds()
+	var totalR, totalG, totalB float64
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := img1.At(x, y).RGBA()
+			r2, g2, b2, _ := img2.At(x, y).RGBA()
+
+			totalR += float64(abs(int(r1>>8) - int(r2>>8)))
+			totalG += float64(abs(int(g1>>8) - int(g2>>8)))
+			totalB += float64(abs(int(b1>>8) - int(b2>>8)))
+		}
+	}
+
+	pixelsInTotal := float64(bounds.Dx() * bounds.Dy())
+
+	return totalR / pixelsInTotal, totalG / pixelsInTotal, totalB / pixelsInTotal
+}
+
+// MaxDifferencePerChannel returns the largest absolute difference found in every channel.
+func MaxDifferencePerChannel(img1, img2 image.Image) (r, g, b int, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = maxDifferencePerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func maxDifferencePerChannel(img1, img2 image.Image) (r, g, b int) {
+	bounds := img1.Bounds()
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := img1.At(x, y).RGBA()
+			r2, g2, b2, _ := img2.At(x, y).RGBA()
+
+			r = max(r, abs(int(r1>>8)-int(r2>>8)))
+			g = max(g, abs(int(g1>>8)-int(g2>>8)))
+			b = max(b, abs(int(b1>>8)-int(b2>>8)))
+		}
+	}
+
+	return r, g, b
+}
+
+// AbsoluteDifferenceImage returns an image where every channel holds the absolute difference of the input channels.
+func AbsoluteDifferenceImage(img1, img2 image.Image) (*image.RGBA, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return nil, err
+	}
+	return absoluteDifferenceImage(img1, img2), nil
+}
+
+func absoluteDifferenceImage(img1, img2 image.Image) *image.RGBA {
+	bounds := img1.Bounds()
+	output := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r1, g1, b1, _ := img1.At(x, y).RGBA()
+			r2, g2, b2, _ := img2.At(x, y).RGBA()
+
+			output.SetRGBA(x-bounds.Min.X, y-bounds.Min.Y, color.RGBA{
+				R: uint8(abs(int(r1>>8) - int(r2>>8))),
+				G: uint8(abs(int(g1>>8) - int(g2>>8))),
+				B: uint8(abs(int(b1>>8) - int(b2>>8))),
+				A: 255,
+			})
+		}
+	}
+
+	return output
+}
+
+// DifferenceHeatMap renders the largest channel difference of every pixel on a heat-map color ramp.
+// Values are normalized by the largest difference in the image, so identical pixels are blue
+// and the pixels that differ the most are red.
+func DifferenceHeatMap(img1, img2 image.Image) (*image.RGBA, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return nil, err
+	}
+	return differenceHeatMap(img1, img2), nil
+}
+
+func differenceHeatMap(img1, img2 image.Image) *image.RGBA {
+	difference := absoluteDifferenceImage(img1, img2)
+	bounds := difference.Bounds()
+
+	peak := 0
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			c := difference.RGBAAt(x, y)
+			peak = max(peak, max(int(c.R), max(int(c.G), int(c.B))))
+		}
+	}
+
+	output := image.NewRGBA(bounds)
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			value := 0.0
+			if peak > 0 {
+				c := difference.RGBAAt(x, y)
+				value = float64(max(int(c.R), max(int(c.G), int(c.B)))) / float64(peak)
+			}
+			output.SetRGBA(x, y, manipulations.HeatMapColor(value))
+		}
+	}
+
+	return output
+}
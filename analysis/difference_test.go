@@ -0,0 +1,165 @@
+package analysis
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestPerChannelDifferences(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img2 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img1.Set(0, 0, color.RGBA{10, 20, 30, 255})
+	img2.Set(0, 0, color.RGBA{14, 20, 28, 255})
+
+	if r, g, b, err := MeanSquareErrorPerChannel(img1, img2); err != nil || r != 8 || g != 0 || b != 2 {
+		t.Errorf("MeanSquareErrorPerChannel = (%f, %f, %f), expected (8, 0, 2)", r, g, b)
+	}
+	if r, g, b, err := MaxDifferencePerChannel(img1, img2); err != nil || r != 4 || g != 0 || b != 2 {
+		t.Errorf("MaxDifferencePerChannel = (%d, %d, %d), expected (4, 0, 2)", r, g, b)
+	}
+
+	difference, err := AbsoluteDifferenceImage(img1, img2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := difference.RGBAAt(0, 0); got != (color.RGBA{4, 0, 2, 255}) {
+		t.Errorf("AbsoluteDifferenceImage(0, 0) = %v, expected {4 0 2 255}", got)
+	}
+
+	heatMap, err := DifferenceHeatMap(img1, img2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := heatMap.RGBAAt(0, 0); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("DifferenceHeatMap at the largest difference = %v, expected red", got)
+	}
+	if got := heatMap.RGBAAt(1, 0); got != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("DifferenceHeatMap at identical pixels = %v, expected blue", got)
+	}
+}
+
+func TestSignalToNoiseRatioPerChannel(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img2 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	// red is identical, green is black in the reference only, blue differs
+	img1.Set(0, 0, color.RGBA{10, 0, 30, 255})
+	img2.Set(0, 0, color.RGBA{10, 5, 20, 255})
+
+	r, g, b, err := SignalToNoiseRatioPerChannel(img1, img2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(r, 1) {
+		t.Errorf("SNR of an identical channel = %f, expected +Inf", r)
+	}
+	if !math.IsInf(g, -1) {
+		t.Errorf("SNR of a noisy channel without signal = %f, expected -Inf", g)
+	}
+	if want := 10 * math.Log10(9); math.Abs(b-want) > 1e-9 {
+		t.Errorf("SNR of the blue channel = %f, expected %f", b, want)
+	}
+}
+
+func TestSignalToNoiseRatioMatchesPerChannel(t *testing.T) {
+	black := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	noisy := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	for x := 0; x < 2; x++ {
+		black.Set(x, 0, color.RGBA{0, 0, 0, 255})
+		noisy.Set(x, 0, color.RGBA{8, 8, 8, 255})
+	}
+
+	tests := []struct {
+		name       string
+		img1, img2 image.Image
+		expected   float64
+	}{
+		{"identical black images", black, black, math.Inf(1)},
+		{"noisy black reference", black, noisy, math.Inf(-1)},
+	}
+
+	for _, tt := range tests {
+		snr, err := SignalToNoiseRatio(tt.img1, tt.img2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, g, b, err := SignalToNoiseRatioPerChannel(tt.img1, tt.img2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, value := range []float64{snr, r, g, b} {
+			if value != tt.expected {
+				t.Errorf("%s: SNR = %f, per channel (%f, %f, %f), expected %f everywhere", tt.name, snr, r, g, b, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestComparisonSizeMismatch(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	img2 := image.NewRGBA(image.Rect(0, 0, 6, 10))
+
+	checks := map[string]func() error{
+		"MeanSquareError": func() error {
+			_, err := MeanSquareError(img1, img2)
+			return err
+		},
+		"StructuralSimilarity": func() error {
+			_, err := StructuralSimilarity(img1, img2, SSIMOptions{})
+			return err
+		},
+		"NormalizedCrossCorrelation": func() error {
+			_, err := NormalizedCrossCorrelation(img1, img2)
+			return err
+		},
+		"PeakSignalToNoiseRatioPerChannel": func() error {
+			_, _, _, err := PeakSignalToNoiseRatioPerChannel(img1, img2)
+			return err
+		},
+		"MeanSquareErrorPerChannel": func() error {
+			_, _, _, err := MeanSquareErrorPerChannel(img1, img2)
+			return err
+		},
+		"AbsoluteDifferenceImage": func() error {
+			_, err := AbsoluteDifferenceImage(img1, img2)
+			return err
+		},
+	}
+	for name, check := range checks {
+		if err := check(); !errors.Is(err, ErrSizeMismatch) {
+			t.Errorf("%s error = %v, expected ErrSizeMismatch", name, err)
+		}
+	}
+}
+
+func TestComparisonSubImageOrigin(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	canvas := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			c := color.RGBA{uint8(40 * x), uint8(30 * y), uint8(10 * (x + y)), 255}
+			img1.Set(x, y, c)
+			canvas.Set(x+3, y+5, c)
+		}
+	}
+	// the same pixels with bounds starting at (3, 5)
+	img2 := canvas.SubImage(image.Rect(3, 5, 7, 9))
+
+	if mse, err := MeanSquareError(img1, img2); err != nil || mse != 0 {
+		t.Errorf("MeanSquareError of a sub-image = %f, %v, expected 0", mse, err)
+	}
+	if r, g, b, err := MaxDifferencePerChannel(img2, img1); err != nil || r != 0 || g != 0 || b != 0 {
+		t.Errorf("MaxDifferencePerChannel of a sub-image = (%d, %d, %d), %v, expected 0", r, g, b, err)
+	}
+	if ssim, err := StructuralSimilarity(img1, img2, SSIMOptions{WindowSize: 3, Sigma: -1}); err != nil || math.Abs(ssim-1) > 1e-9 {
+		t.Errorf("StructuralSimilarity of a sub-image = %f, %v, expected 1", ssim, err)
+	}
+
+	result, err := CalculateComparisonCharacteristic("mse", img1, img2, ComparisonOptions{})
+	if err != nil || result.Value != 0 {
+		t.Errorf("CalculateComparisonCharacteristic(mse) of a sub-image = %v, %v, expected 0", result.Value, err)
+	}
+}
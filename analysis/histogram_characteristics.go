@@ -41,6 +41,11 @@ func calculateVariationCoefficientOne(histogram [256]int) float64 {
 	meanIntensity := calculateMean(histogram)
 	standardDeviation := calculateStandardDeviation(histogram)
 
+	// an all-black channel has no relative variation
+	if meanIntensity == 0 {
+		return 0
+	}
+
 	return standardDeviation / meanIntensity
 }
 
@@ -134,59 +139,54 @@ func calculateInformationSourceEntropy(histogram [256]int) float64 {
 	return -entropy / N
 }
 
-func CalculateHistogramCharacteristic(metricMethod string, providedHistogram [256]int, filenameWithoutExt string) CharacteristicsEntry {
-	var result string
-	var description string
+func CalculateHistogramCharacteristic(metricMethod string, providedHistogram [256]int, filenameWithoutExt string) (CharacteristicsEntry, error) {
+	entry := CharacteristicsEntry{MetricMethod: strings.ToUpper(metricMethod)}
 
 	switch metricMethod {
 	case "cmean":
-		mean := calculateMean(providedHistogram)
-		description = fmt.Sprintf("Calculated Mean intensity for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Mean: %f", mean)
+		entry.Value = calculateMean(providedHistogram)
+		entry.Label = "Mean"
+		entry.Description = fmt.Sprintf("Calculated Mean intensity for %s", filenameWithoutExt)
 
 	case "cvariance":
-		variance := calculateVariance(providedHistogram)
-		description = fmt.Sprintf("Calculated Variance intensity for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Variance: %f", variance)
+		entry.Value = calculateVariance(providedHistogram)
+		entry.Label = "Variance"
+		entry.Description = fmt.Sprintf("Calculated Variance intensity for %s", filenameWithoutExt)
 
 	case "cstdev":
-		stdev := calculateStandardDeviation(providedHistogram)
-		description = fmt.Sprintf("Calculated Standard Deviation for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Standard Deviation: %f", stdev)
+		entry.Value = calculateStandardDeviation(providedHistogram)
+		entry.Label = "Standard Deviation"
+		entry.Description = fmt.Sprintf("Calculated Standard Deviation for %s", filenameWithoutExt)
 
 	case "cvarcoi":
-		varCoefI := calculateVariationCoefficientOne(providedHistogram)
-		description = fmt.Sprintf("Calculated Variation Coefficient I for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Variation Coefficient I: %f", varCoefI)
+		entry.Value = calculateVariationCoefficientOne(providedHistogram)
+		entry.Label = "Variation Coefficient I"
+		entry.Description = fmt.Sprintf("Calculated Variation Coefficient I for %s", filenameWithoutExt)
 
 	case "casyco":
-		asymCoef := calculateAsymmetryCoefficient(providedHistogram)
-		description = fmt.Sprintf("Calculated Asymmetry Coefficient for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Asymmetry Coefficient: %f", asymCoef)
+		entry.Value = calculateAsymmetryCoefficient(providedHistogram)
+		entry.Label = "Asymmetry Coefficient"
+		entry.Description = fmt.Sprintf("Calculated Asymmetry Coefficient for %s", filenameWithoutExt)
 
 	case "cflatco":
-		flatCoef := calculateFlatteningCoefficient(providedHistogram)
-		description = fmt.Sprintf("Calculated Flattening Coefficient for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Flattening Coefficient: %f", flatCoef)
+		entry.Value = calculateFlatteningCoefficient(providedHistogram)
+		entry.Label = "Flattening Coefficient"
+		entry.Description = fmt.Sprintf("Calculated Flattening Coefficient for %s", filenameWithoutExt)
 
 	case "cvarcoii":
-		varCoefII := calculateVariationCoefficientTwo(providedHistogram)
-		description = fmt.Sprintf("Calculated Variation Coefficient II for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Variation Coefficient II: %f", varCoefII)
+		entry.Value = calculateVariationCoefficientTwo(providedHistogram)
+		entry.Label = "Variation Coefficient II"
+		entry.Description = fmt.Sprintf("Calculated Variation Coefficient II for %s", filenameWithoutExt)
 
 	case "centropy":
-		entropy := calculateInformationSourceEntropy(providedHistogram)
-		description = fmt.Sprintf("Calculated Information Source Entropy for %s", filenameWithoutExt)
-		result = fmt.Sprintf("Information Source Entropy: %f", entropy)
+		entry.Value = calculateInformationSourceEntropy(providedHistogram)
+		entry.Unit = UnitBits
+		entry.Label = "Information Source Entropy"
+		entry.Description = fmt.Sprintf("Calculated Information Source Entropy for %s", filenameWithoutExt)
 
 	default:
-		description = "Unknown metric"
-		result = "N/A"
+		return CharacteristicsEntry{}, fmt.Errorf("%w: %q", ErrUnknownMetric, metricMethod)
 	}
 
-	return CharacteristicsEntry{
-		MetricMethod: strings.ToUpper(metricMethod),
-		Description:  description,
-		Result:       result,
-	}
+	return entry, nil
 }
@@ -0,0 +1,184 @@
+package analysis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	"imagio/manipulations"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HistogramExportFormat is the data format raw histogram counts are written in.
+type HistogramExportFormat string
+
+const (
+	HistogramExportCSV  HistogramExportFormat = "csv"
+	HistogramExportJSON HistogramExportFormat = "json"
+)
+
+// ParseHistogramExportFormat parses "csv" or "json".
+func ParseHistogramExportFormat(value string) (HistogramExportFormat, error) {
+	switch format := HistogramExportFormat(strings.ToLower(strings.TrimSpace(value))); format {
+	case HistogramExportCSV, HistogramExportJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown histogram export format %q, expected csv or json", value)
+	}
+}
+
+// HistogramSummary holds the characteristics of a single histogram, the same values reported by the c* commands.
+type HistogramSummary struct {
+	Pixels                   int     `json:"pixels"`
+	Mean                     float64 `json:"mean"`
+	Variance                 float64 `json:"variance"`
+	StandardDeviation        float64 `json:"standard_deviation"`
+	VariationCoefficientI    float64 `json:"variation_coefficient_i"`
+	AsymmetryCoefficient     float64 `json:"asymmetry_coefficient"`
+	FlatteningCoefficient    float64 `json:"flattening_coefficient"`
+	VariationCoefficientII   float64 `json:"variation_coefficient_ii"`
+	InformationSourceEntropy float64 `json:"information_source_entropy_bits"`
+}
+
+// SummarizeHistogram calculates every histogram characteristic at once.
+func SummarizeHistogram(histogram [256]int) HistogramSummary {
+	pixels := 0
+	for _, count := range histogram {
+		pixels += count
+	}
+
+	if pixels == 0 {
+		return HistogramSummary{}
+	}
+
+	return HistogramSummary{
+		Pixels:                   pixels,
+		Mean:                     calculateMean(histogram),
+		Variance:                 calculateVariance(histogram),
+		StandardDeviation:        calculateStandardDeviation(histogram),
+		VariationCoefficientI:    calculateVariationCoefficientOne(histogram),
+		AsymmetryCoefficient:     calculateAsymmetryCoefficient(histogram),
+		FlatteningCoefficient:    calculateFlatteningCoefficient(histogram),
+		VariationCoefficientII:   calculateVariationCoefficientTwo(histogram),
+		InformationSourceEntropy: calculateInformationSourceEntropy(histogram),
+	}
+}
+
+type summaryField struct {
+	name  string
+	value float64
+}
+
+// fields lists the summary values in the order they are written to CSV.
+func (s HistogramSummary) fields() []summaryField {
+	return []summaryField{
+		{"pixels", float64(s.Pixels)},
+		{"mean", s.Mean},
+		{"variance", s.Variance},
+		{"standard_deviation", s.StandardDeviation},
+		{"variation_coefficient_i", s.VariationCoefficientI},
+		{"asymmetry_coefficient", s.AsymmetryCoefficient},
+		{"flattening_coefficient", s.FlatteningCoefficient},
+		{"variation_coefficient_ii", s.VariationCoefficientII},
+		{"information_source_entropy_bits", s.InformationSourceEntropy},
+	}
+}
+
+// ChannelHistogram is the raw histogram of one channel together with its summary.
+type ChannelHistogram struct {
+	Channel string           `json:"channel"`
+	Bins    [256]int         `json:"bins"`
+	Summary HistogramSummary `json:"summary"`
+}
+
+// HistogramExport is the content of a histogram data file.
+type HistogramExport struct {
+	Image    string             `json:"image"`
+	Channels []ChannelHistogram `json:"channels"`
+}
+
+// NewHistogramExport calculates the histograms and summaries of the given channels of img.
+func NewHistogramExport(img image.Image, imageName string, channels []manipulations.HistogramChannel) HistogramExport {
+	export := HistogramExport{Image: imageName}
+
+	for _, channel := range channels {
+		histogram := manipulations.CalculateChannelHistogram(img, channel)
+		export.Channels = append(export.Channels, ChannelHistogram{
+			Channel: channel.String(),
+			Bins:    histogram,
+			Summary: SummarizeHistogram(histogram),
+		})
+	}
+
+	return export
+}
+
+// Write encodes the export in the given format.
+func (e HistogramExport) Write(w io.Writer, format HistogramExportFormat) error {
+	switch format {
+	case HistogramExportJSON:
+		return e.writeJSON(w)
+	case HistogramExportCSV:
+		return e.writeCSV(w)
+	default:
+		return fmt.Errorf("unknown histogram export format %q", format)
+	}
+}
+
+func (e HistogramExport) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(e)
+}
+
+// writeCSV writes one row per intensity with a column per channel, followed by an empty line
+// and one row per summary value with the same columns.
+func (e HistogramExport) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"intensity"}
+	for _, channel := range e.Channels {
+		header = append(header, channel.Channel)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for intensity := 0; intensity < 256; intensity++ {
+		record := []string{strconv.Itoa(intensity)}
+		for _, channel := range e.Channels {
+			record = append(record, strconv.Itoa(channel.Bins[intensity]))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return err
+	}
+
+	header[0] = "statistic"
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	if len(e.Channels) > 0 {
+		for i, field := range e.Channels[0].Summary.fields() {
+			record := []string{field.name}
+			for _, channel := range e.Channels {
+				value := channel.Summary.fields()[i].value
+				record = append(record, strconv.FormatFloat(value, 'f', -1, 64))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,96 @@
+package analysis
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"strings"
+	"testing"
+)
+
+func exportTestImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{10, 20, 30, 255})
+	img.Set(1, 0, color.RGBA{10, 40, 30, 255})
+	return img
+}
+
+func TestHistogramExportCSV(t *testing.T) {
+	export := NewHistogramExport(exportTestImage(), "test.bmp", []manipulations.HistogramChannel{manipulations.HistogramRed, manipulations.HistogramGreen})
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, HistogramExportCSV); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	lines := strings.Split(buf.String(), "\n")
+	if lines[0] != "intensity,r,g" {
+		t.Errorf("header = %q, expected intensity,r,g", lines[0])
+	}
+	if lines[11] != "10,2,0" || lines[21] != "20,0,1" {
+		t.Errorf("unexpected bin rows %q and %q", lines[11], lines[21])
+	}
+	if lines[257] != "" || lines[258] != "statistic,r,g" {
+		t.Errorf("expected an empty line and the summary header, got %q and %q", lines[257], lines[258])
+	}
+	if lines[260] != "mean,10,30" {
+		t.Errorf("mean row = %q, expected mean,10,30", lines[260])
+	}
+}
+
+func TestHistogramExportJSON(t *testing.T) {
+	export := NewHistogramExport(exportTestImage(), "test.bmp", []manipulations.HistogramChannel{manipulations.HistogramBlue})
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, HistogramExportJSON); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+
+	var decoded HistogramExport
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("exported JSON does not decode: %v", err)
+	}
+	if decoded.Image != "test.bmp" || len(decoded.Channels) != 1 || decoded.Channels[0].Channel != "b" {
+		t.Fatalf("unexpected export %+v", decoded)
+	}
+	if decoded.Channels[0].Bins[30] != 2 || decoded.Channels[0].Summary.Mean != 30 || decoded.Channels[0].Summary.Variance != 0 {
+		t.Errorf("unexpected blue channel %+v", decoded.Channels[0].Summary)
+	}
+}
+
+func TestParseHistogramExportFormat(t *testing.T) {
+	if format, err := ParseHistogramExportFormat("JSON"); err != nil || format != HistogramExportJSON {
+		t.Errorf("ParseHistogramExportFormat(\"JSON\") = %q, %v", format, err)
+	}
+	if _, err := ParseHistogramExportFormat("xml"); err == nil {
+		t.Error("ParseHistogramExportFormat(\"xml\") expected error")
+	}
+}
+
+func TestHistogramExportBlackChannel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			img.Set(x, y, color.RGBA{200, 0, 0, 255})
+		}
+	}
+	export := NewHistogramExport(img, "red.bmp", []manipulations.HistogramChannel{manipulations.HistogramBlue})
+
+	var buf bytes.Buffer
+	if err := export.Write(&buf, HistogramExportJSON); err != nil {
+		t.Fatalf("exporting an all-black channel as JSON returned error: %v", err)
+	}
+	if summary := export.Channels[0].Summary; summary.VariationCoefficientI != 0 {
+		t.Errorf("variation coefficient of an all-black channel = %v, expected 0", summary.VariationCoefficientI)
+	}
+
+	buf.Reset()
+	if err := export.Write(&buf, HistogramExportCSV); err != nil {
+		t.Fatalf("exporting an all-black channel as CSV returned error: %v", err)
+	}
+	if strings.Contains(buf.String(), "NaN") || strings.Contains(buf.String(), "Inf") {
+		t.Error("CSV export of an all-black channel contains NaN or Inf")
+	}
+}
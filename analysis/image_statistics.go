@@ -0,0 +1,272 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"imagio/manipulations"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// StatisticsOptions configures CalculateImageStatistics.
+type StatisticsOptions struct {
+	// Percentiles lists the percentiles, from 0 to 100, reported for every channel.
+	Percentiles []float64
+	// GrayscaleTolerance is the largest difference between the color channels of a pixel that still counts as gray.
+	GrayscaleTolerance int
+}
+
+var DefaultStatisticsOptions = StatisticsOptions{Percentiles: []float64{1, 5, 25, 75, 95, 99}}
+
+// ParsePercentiles parses a comma separated list of percentiles, e.g. "5,95", an empty string selects the defaults.
+func ParsePercentiles(value string) ([]float64, error) {
+	if strings.TrimSpace(value) == "" {
+		return DefaultStatisticsOptions.Percentiles, nil
+	}
+
+	var percentiles []float64
+	for _, part := range strings.Split(value, ",") {
+		percentile, err := strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(part), "p"), 64)
+		if err != nil || percentile < 0 || percentile > 100 {
+			return nil, fmt.Errorf("invalid percentile %q, expected a number from 0 to 100", part)
+		}
+		percentiles = append(percentiles, percentile)
+	}
+
+	return percentiles, nil
+}
+
+// PercentileValue is the intensity below or at which the given percent of pixels lie.
+type PercentileValue struct {
+	Percentile float64 `json:"percentile"`
+	Value      int     `json:"value"`
+}
+
+// ChannelStatistics describes the intensity distribution of a single channel.
+type ChannelStatistics struct {
+	Channel           string            `json:"channel"`
+	Min               int               `json:"min"`
+	Max               int               `json:"max"`
+	Mean              float64           `json:"mean"`
+	Median            int               `json:"median"`
+	StandardDeviation float64           `json:"standard_deviation"`
+	Percentiles       []PercentileValue `json:"percentiles"`
+
+	histogramChannel manipulations.HistogramChannel
+}
+
+// ImageStatistics is a description of an image that does not depend on any other image.
+type ImageStatistics struct {
+	Image        string              `json:"image"`
+	Width        int                 `json:"width"`
+	Height       int                 `json:"height"`
+	ColorModel   string              `json:"color_model"`
+	Channels     []ChannelStatistics `json:"channels"`
+	UniqueColors int                 `json:"unique_colors"`
+	// Grayscale is set when every pixel has equal color channels, within StatisticsOptions.GrayscaleTolerance.
+	Grayscale bool `json:"grayscale"`
+	// Binary is set for grayscale images that use at most two gray levels.
+	Binary bool `json:"binary"`
+	// SaturatedFraction is the fraction of pixels with at least one color channel at the maximum intensity.
+	SaturatedFraction float64 `json:"saturated_fraction"`
+}
+
+// statisticsChannels are the channels described by CalculateImageStatistics.
+var statisticsChannels = []manipulations.HistogramChannel{
+	manipulations.HistogramRed,
+	manipulations.HistogramGreen,
+	manipulations.HistogramBlue,
+	manipulations.HistogramLuma,
+}
+
+// ColorModelName returns a readable name of the pixel storage of img, e.g. "RGBA" or "Paletted (256 colors)".
+func ColorModelName(img image.Image) string {
+	switch typed := img.(type) {
+	case *image.RGBA:
+		return "RGBA"
+	case *image.RGBA64:
+		return "RGBA64"
+	case *image.NRGBA:
+		return "NRGBA"
+	case *image.NRGBA64:
+		return "NRGBA64"
+	case *image.Gray:
+		return "Gray"
+	case *image.Gray16:
+		return "Gray16"
+	case *image.Paletted:
+		return fmt.Sprintf("Paletted (%d colors)", len(typed.Palette))
+	case *image.YCbCr:
+		return fmt.Sprintf("YCbCr %s", typed.SubsampleRatio)
+	case *image.CMYK:
+		return "CMYK"
+	default:
+		return fmt.Sprintf("%T", img)
+	}
+}
+
+// CalculateImageStatistics describes the dimensions, color model, channel distributions and colors of img.
+func CalculateImageStatistics(img image.Image, imageName string, opts StatisticsOptions) ImageStatistics {
+	bounds := img.Bounds()
+
+	stats := ImageStatistics{
+		Image:      imageName,
+		Width:      bounds.Dx(),
+		Height:     bounds.Dy(),
+		ColorModel: ColorModelName(img),
+		Grayscale:  true,
+	}
+
+	pixels := bounds.Dx() * bounds.Dy()
+	if pixels == 0 {
+		return stats
+	}
+
+	colors := make(map[uint32]struct{})
+	grayLevels := make(map[uint8]struct{})
+	saturated := 0
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r32, g32, b32, _ := img.At(x, y).RGBA()
+			r, g, b := uint8(r32>>8), uint8(g32>>8), uint8(b32>>8)
+
+			colors[uint32(r)<<16|uint32(g)<<8|uint32(b)] = struct{}{}
+
+			if r == 255 || g == 255 || b == 255 {
+				saturated++
+			}
+
+			if stats.Grayscale {
+				spread := max(abs(int(r)-int(g)), max(abs(int(g)-int(b)), abs(int(r)-int(b))))
+				if spread > opts.GrayscaleTolerance {
+					stats.Grayscale = false
+				} else if len(grayLevels) <= 2 {
+					grayLevels[r] = struct{}{}
+				}
+			}
+		}
+	}
+
+	stats.UniqueColors = len(colors)
+	stats.Binary = stats.Grayscale && len(grayLevels) <= 2
+	stats.SaturatedFraction = float64(saturated) / float64(pixels)
+
+	for _, channel := range statisticsChannels {
+		histogram := manipulations.CalculateChannelHistogram(img, channel)
+
+		channelStats := ChannelStatistics{
+			Channel:           channel.String(),
+			Min:               manipulations.HistogramPercentile(histogram, 0),
+			Max:               manipulations.HistogramPercentile(histogram, 100),
+			Mean:              calculateMean(histogram),
+			Median:            manipulations.HistogramPercentile(histogram, 50),
+			StandardDeviation: calculateStandardDeviation(histogram),
+			histogramChannel:  channel,
+		}
+
+		for _, percentile := range opts.Percentiles {
+			channelStats.Percentiles = append(channelStats.Percentiles, PercentileValue{
+				Percentile: percentile,
+				Value:      manipulations.HistogramPercentile(histogram, percentile),
+			})
+		}
+
+		stats.Channels = append(stats.Channels, channelStats)
+	}
+
+	return stats
+}
+
+// Summary renders the most important values on one line, e.g. "512x512 RGBA, 148279 unique colors".
+func (s ImageStatistics) Summary() string {
+	summary := fmt.Sprintf("%dx%d %s, %d unique colors", s.Width, s.Height, s.ColorModel, s.UniqueColors)
+	switch {
+	case s.Binary:
+		summary += ", binary"
+	case s.Grayscale:
+		summary += ", grayscale"
+	}
+	return summary
+}
+
+// Entries lists the statistics as characteristic entries, booleans are reported as 1 or 0.
+func (s ImageStatistics) Entries() []CharacteristicsEntry {
+	entry := func(label string, value MetricValue) CharacteristicsEntry {
+		return CharacteristicsEntry{
+			MetricMethod: "STATS",
+			Description:  fmt.Sprintf("Calculated image statistics for %s", s.Image),
+			Label:        label,
+			MetricValue:  value,
+			Img1Name:     s.Image,
+		}
+	}
+
+	flag := func(set bool) float64 {
+		if set {
+			return 1
+		}
+		return 0
+	}
+
+	entries := []CharacteristicsEntry{
+		entry("Width", MetricValue{Value: float64(s.Width)}),
+		entry("Height", MetricValue{Value: float64(s.Height)}),
+		entry("Unique Colors", MetricValue{Value: float64(s.UniqueColors)}),
+		entry("Grayscale", MetricValue{Value: flag(s.Grayscale)}),
+		entry("Binary", MetricValue{Value: flag(s.Binary)}),
+		entry("Saturated Pixels", MetricValue{Value: s.SaturatedFraction * 100, Unit: UnitPercent}),
+	}
+
+	for _, channel := range s.Channels {
+		ch := HistogramChannelOf(channel.histogramChannel)
+
+		entries = append(entries,
+			entry("Min", MetricValue{Value: float64(channel.Min), Channel: ch}),
+			entry("Max", MetricValue{Value: float64(channel.Max), Channel: ch}),
+			entry("Mean", MetricValue{Value: channel.Mean, Channel: ch}),
+			entry("Median", MetricValue{Value: float64(channel.Median), Channel: ch}),
+			entry("Standard Deviation", MetricValue{Value: channel.StandardDeviation, Channel: ch}),
+		)
+		for _, percentile := range channel.Percentiles {
+			label := "P" + strconv.FormatFloat(percentile.Percentile, 'f', -1, 64)
+			entries = append(entries, entry(label, MetricValue{Value: float64(percentile.Value), Channel: ch}))
+		}
+	}
+
+	return entries
+}
+
+// WriteText writes the statistics as an indented, human readable listing.
+func (s ImageStatistics) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "  Image: %s\n", s.Image)
+	fmt.Fprintf(&b, "  Dimensions: %dx%d (%d pixels)\n", s.Width, s.Height, s.Width*s.Height)
+	fmt.Fprintf(&b, "  Color model: %s\n", s.ColorModel)
+	fmt.Fprintf(&b, "  Unique colors: %d\n", s.UniqueColors)
+	fmt.Fprintf(&b, "  Grayscale: %t\n", s.Grayscale)
+	fmt.Fprintf(&b, "  Binary: %t\n", s.Binary)
+	fmt.Fprintf(&b, "  Saturated pixels: %.4f%%\n", s.SaturatedFraction*100)
+
+	for _, channel := range s.Channels {
+		fmt.Fprintf(&b, "  Channel %s: min=%d max=%d mean=%.4f median=%d std=%.4f",
+			channel.Channel, channel.Min, channel.Max, channel.Mean, channel.Median, channel.StandardDeviation)
+		for _, percentile := range channel.Percentiles {
+			fmt.Fprintf(&b, " p%s=%d", strconv.FormatFloat(percentile.Percentile, 'f', -1, 64), percentile.Value)
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteJSON writes the statistics as an indented JSON document.
+func (s ImageStatistics) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(s)
+}
@@ -0,0 +1,71 @@
+package analysis
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCalculateImageStatisticsColor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 1))
+	img.Set(0, 0, color.RGBA{0, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{255, 10, 10, 255})
+	img.Set(2, 0, color.RGBA{100, 100, 100, 255})
+	img.Set(3, 0, color.RGBA{100, 100, 100, 255})
+
+	stats := CalculateImageStatistics(img, "test.bmp", DefaultStatisticsOptions)
+
+	if stats.Width != 4 || stats.Height != 1 || stats.ColorModel != "RGBA" {
+		t.Errorf("unexpected dimensions or color model: %dx%d %s", stats.Width, stats.Height, stats.ColorModel)
+	}
+	if stats.UniqueColors != 3 {
+		t.Errorf("UniqueColors = %d, expected 3", stats.UniqueColors)
+	}
+	if stats.Grayscale || stats.Binary {
+		t.Error("a red pixel must make the image neither grayscale nor binary")
+	}
+	if stats.SaturatedFraction != 0.25 {
+		t.Errorf("SaturatedFraction = %v, expected 0.25", stats.SaturatedFraction)
+	}
+
+	red := stats.Channels[0]
+	if red.Channel != "r" || red.Min != 0 || red.Max != 255 || red.Median != 100 || red.Mean != 113.75 {
+		t.Errorf("unexpected red channel statistics %+v", red)
+	}
+	if len(red.Percentiles) != len(DefaultStatisticsOptions.Percentiles) {
+		t.Errorf("expected %d percentiles, got %d", len(DefaultStatisticsOptions.Percentiles), len(red.Percentiles))
+	}
+}
+
+func TestCalculateImageStatisticsGrayscale(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.Set(0, 0, color.RGBA{0, 0, 0, 255})
+	img.Set(1, 0, color.RGBA{255, 255, 255, 255})
+	img.Set(2, 0, color.RGBA{254, 255, 255, 255})
+
+	stats := CalculateImageStatistics(img, "test.bmp", DefaultStatisticsOptions)
+	if stats.Grayscale {
+		t.Error("expected a pixel with a channel spread of 1 not to be gray without tolerance")
+	}
+
+	stats = CalculateImageStatistics(img, "test.bmp", StatisticsOptions{GrayscaleTolerance: 1})
+	if !stats.Grayscale || stats.Binary {
+		t.Errorf("expected grayscale but not binary with three gray levels, got grayscale=%t binary=%t", stats.Grayscale, stats.Binary)
+	}
+
+	img.Set(2, 0, color.RGBA{0, 0, 0, 255})
+	stats = CalculateImageStatistics(img, "test.bmp", DefaultStatisticsOptions)
+	if !stats.Grayscale || !stats.Binary {
+		t.Errorf("expected a black and white image to be binary, got grayscale=%t binary=%t", stats.Grayscale, stats.Binary)
+	}
+}
+
+func TestParsePercentiles(t *testing.T) {
+	percentiles, err := ParsePercentiles("5, p95,99.9")
+	if err != nil || len(percentiles) != 3 || percentiles[1] != 95 || percentiles[2] != 99.9 {
+		t.Errorf("ParsePercentiles = %v, %v", percentiles, err)
+	}
+	if _, err := ParsePercentiles("120"); err == nil {
+		t.Error("ParsePercentiles(\"120\") expected error")
+	}
+}
@@ -0,0 +1,336 @@
+package analysis
+
+import (
+	"image"
+	"math"
+)
+
+// SSIMOptions configures the structural similarity computation.
+// Zero values fall back to the defaults proposed by Wang et al.
+type SSIMOptions struct {
+	// WindowSize is the side length of the sliding window, defaults to 11.
+	WindowSize int
+	// Sigma of the gaussian window, defaults to 1.5. A negative value selects a uniform window.
+	Sigma float64
+	// K1 and K2 are the stabilizing constants, default to 0.01 and 0.03.
+	K1, K2 float64
+	// DynamicRange of the pixel values, defaults to 255.
+	DynamicRange float64
+}
+
+// DefaultSSIMOptions are the parameters used in the original SSIM paper.
+// Reference: https://www.cns.nyu.edu/pub/eero/wang03-reprint.pdf
+var DefaultSSIMOptions = SSIMOptions{
+	WindowSize:   11,
+	Sigma:        1.5,
+	K1:           0.01,
+	K2:           0.03,
+	DynamicRange: 255,
+}
+
+func (o SSIMOptions) withDefaults() SSIMOptions {
+	if o.WindowSize <= 0 {
+		o.WindowSize = DefaultSSIMOptions.WindowSize
+	}
+	if o.Sigma == 0 {
+		o.Sigma = DefaultSSIMOptions.Sigma
+	}
+	if o.K1 <= 0 {
+		o.K1 = DefaultSSIMOptions.K1
+	}
+	if o.K2 <= 0 {
+		o.K2 = DefaultSSIMOptions.K2
+	}
+	if o.DynamicRange <= 0 {
+		o.DynamicRange = DefaultSSIMOptions.DynamicRange
+	}
+	return o
+}
+
+// msssimWeights are the per scale exponents from the MS-SSIM paper.
+// Reference: https://www.cns.nyu.edu/pub/eero/wang03b.pdf
+var msssimWeights = []float64{0.0448, 0.2856, 0.3001, 0.2363, 0.1333}
+
+// luminanceMatrix converts the image into a matrix of luma values (ITU-R BT.601), indexed as [y][x].
+func luminanceMatrix(img image.Image) [][]float64 {
+	bounds := img.Bounds()
+	matrix := make([][]float64, bounds.Dy())
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := make([]float64, bounds.Dx())
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			row[x-bounds.Min.X] = 0.299*float64(r>>8) + 0.587*float64(g>>8) + 0.114*float64(b>>8)
+		}
+		matrix[y-bounds.Min.Y] = row
+	}
+
+	return matrix
+}
+
+// windowKernel builds a normalized separable 1D kernel, gaussian for positive sigma and uniform otherwise.
+func windowKernel(size int, sigma float64) []float64 {
+	kernel := make([]float64, size)
+	center := float64(size-1) / 2
+
+	var sum float64
+	for i := range kernel {
+		if sigma > 0 {
+			d := float64(i) - center
+			kernel[i] = math.Exp(-(d * d) / (2 * sigma * sigma))
+		} else {
+			kernel[i] = 1
+		}
+		sum += kernel[i]
+	}
+
+	for i := range kernel {
+		kernel[i] /= sum
+	}
+
+	return kernel
+}
+
+// filterValid applies the separable kernel in both directions keeping only positions
+// where the window fits entirely inside of the matrix.
+func filterValid(matrix [][]float64, kernel []float64) [][]float64 {
+	height, width := len(matrix), len(matrix[0])
+	size := len(kernel)
+	outHeight, outWidth := height-size+1, width-size+1
+
+	horizontal := make([][]float64, height)
+	for y := 0; y < height; y++ {
+		horizontal[y] = make([]float64, outWidth)
+		for x := 0; x < outWidth; x++ {
+			var sum float64
+			for k := 0; k < size; k++ {
+				sum += matrix[y][x+k] * kernel[k]
+			}
+			horizontal[y][x] = sum
+		}
+	}
+
+	output := make([][]float64, outHeight)
+	for y := 0; y < outHeight; y++ {
+		output[y] = make([]float64, outWidth)
+		for x := 0; x < outWidth; x++ {
+			var sum float64
+			for k := 0; k < size; k++ {
+				sum += horizontal[y+k][x] * kernel[k]
+			}
+			output[y][x] = sum
+		}
+	}
+
+	return output
+}
+
+func multiplyMatrices(a, b [][]float64) [][]float64 {
+	output := make([][]float64, len(a))
+	for y := range a {
+		output[y] = make([]float64, len(a[y]))
+		for x := range a[y] {
+			output[y][x] = a[y][x] * b[y][x]
+		}
+	}
+	return output
+}
+
+// ssimComponents returns the mean SSIM and the mean contrast-structure term of two luma matrices.
+func ssimComponents(x, y [][]float64, opts SSIMOptions) (ssim, contrastStructure float64) {
+	windowSize := min(opts.WindowSize, len(x), len(x[0]))
+	kernel := windowKernel(windowSize, opts.Sigma)
+
+	c1 := math.Pow(opts.K1*opts.DynamicRange, 2)
+	c2 := math.Pow(opts.K2*opts.DynamicRange, 2)
+
+	muX := filterValid(x, kernel)
+	muY := filterValid(y, kernel)
+	xx := filterValid(multiplyMatrices(x, x), kernel)
+	yy := filterValid(multiplyMatrices(y, y), kernel)
+	xy := filterValid(multiplyMatrices(x, y), kernel)
+
+	var ssimSum, csSum float64
+	var count int
+
+	for i := range muX {
+		for j := range muX[i] {
+			mx, my := muX[i][j], muY[i][j]
+			varX := xx[i][j] - mx*mx
+			varY := yy[i][j] - my*my
+			covXY := xy[i][j] - mx*my
+
+			luminance := (2*mx*my + c1) / (mx*mx + my*my + c1)
+			cs := (2*covXY + c2) / (varX + varY + c2)
+
+			ssimSum += luminance * cs
+			csSum += cs
+			count++
+		}
+	}
+
+	return ssimSum / float64(count), csSum / float64(count)
+}
+
+// StructuralSimilarity calculates the mean structural similarity index (SSIM) of the luma of two images.
+// The result is 1 for identical images and decreases as they diverge.
+func StructuralSimilarity(img1, img2 image.Image, opts SSIMOptions) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return structuralSimilarity(img1, img2, opts), nil
+}
+
+func structuralSimilarity(img1, img2 image.Image, opts SSIMOptions) float64 {
+	ssim, _ := ssimComponents(luminanceMatrix(img1), luminanceMatrix(img2), opts.withDefaults())
+	return ssim
+}
+
+// downsampleByTwo averages 2x2 blocks of the matrix.
+func downsampleByTwo(matrix [][]float64) [][]float64 {
+	height, width := len(matrix)/2, len(matrix[0])/2
+	output := make([][]float64, height)
+
+	for y := 0; y < height; y++ {
+		output[y] = make([]float64, width)
+		for x := 0; x < width; x++ {
+			output[y][x] = (matrix[2*y][2*x] + matrix[2*y][2*x+1] + matrix[2*y+1][2*x] + matrix[2*y+1][2*x+1]) / 4
+		}
+	}
+
+	return output
+}
+
+// MultiScaleStructuralSimilarity calculates MS-SSIM over up to five dyadic scales.
+// Scales at which the image would become smaller than the SSIM window are skipped
+// and the remaining weights are renormalized.
+func MultiScaleStructuralSimilarity(img1, img2 image.Image, opts SSIMOptions) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return multiScaleStructuralSimilarity(img1, img2, opts), nil
+}
+
+func multiScaleStructuralSimilarity(img1, img2 image.Image, opts SSIMOptions) float64 {
+	opts = opts.withDefaults()
+
+	x := luminanceMatrix(img1)
+	y := luminanceMatrix(img2)
+
+	levels := 0
+	for size := min(len(x), len(x[0])); levels < len(msssimWeights) && size >= opts.WindowSize; size /= 2 {
+		levels++
+	}
+	levels = max(levels, 1)
+
+	weights := msssimWeights[:levels]
+	var weightSum float64
+	for _, w := range weights {
+		weightSum += w
+	}
+
+	result := 1.0
+	for level := 0; level < levels; level++ {
+		ssim, cs := ssimComponents(x, y, opts)
+		weight := weights[level] / weightSum
+
+		if level == levels-1 {
+			result *= math.Pow(math.Max(ssim, 0), weight)
+		} else {
+			result *= math.Pow(math.Max(cs, 0), weight)
+			x, y = downsampleByTwo(x), downsampleByTwo(y)
+		}
+	}
+
+	return result
+}
+
+// PeakSignalToNoiseRatioPerChannel calculates PSNR separately for the red, green and blue channels.
+func PeakSignalToNoiseRatioPerChannel(img1, img2 image.Image) (r, g, b float64, err error) {
+	img2, err = matchOrigin(img1, img2)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	r, g, b = peakSignalToNoiseRatioPerChannel(img1, img2)
+	return r, g, b, nil
+}
+
+func peakSignalToNoiseRatioPerChannel(img1, img2 image.Image) (r, g, b float64) {
+	maxValue := float64(maxPixelValue(img1))
+	totalR, totalG, totalB := pixelSquaredDifference(img1, img2)
+	pixelsInTotal := float64(img1.Bounds().Dx() * img1.Bounds().Dy())
+
+	psnr := func(squaredDifference float64) float64 {
+		mse := squaredDifference / pixelsInTotal
+		if mse == 0 {
+			return math.Inf(1)
+		}
+		return 10 * math.Log10(maxValue*maxValue/mse)
+	}
+
+	return psnr(totalR), psnr(totalG), psnr(totalB)
+}
+
+// MeanAbsoluteError calculates the mean absolute difference over all channels of two images.
+func MeanAbsoluteError(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return meanAbsoluteError(img1, img2), nil
+}
+
+func meanAbsoluteError(img1, img2 image.Image) float64 {
+	r, g, b := meanAbsoluteErrorPerChannel(img1, img2)
+	return (r + g + b) / 3
+}
+
+// NormalizedCrossCorrelation calculates the zero-mean normalized cross-correlation of the luma of two images.
+// The result lies in [-1, 1], where 1 means the images are identical up to brightness and contrast.
+func NormalizedCrossCorrelation(img1, img2 image.Image) (float64, error) {
+	img2, err := matchOrigin(img1, img2)
+	if err != nil {
+		return 0, err
+	}
+	return normalizedCrossCorrelation(img1, img2), nil
+}
+
+func normalizedCrossCorrelation(img1, img2 image.Image) float64 {
+	x := luminanceMatrix(img1)
+	y := luminanceMatrix(img2)
+
+	var meanX, meanY float64
+	var count int
+	for i := range x {
+		for j := range x[i] {
+			meanX += x[i][j]
+			meanY += y[i][j]
+			count++
+		}
+	}
+	meanX /= float64(count)
+	meanY /= float64(count)
+
+	var numerator, denominatorX, denominatorY float64
+	for i := range x {
+		for j := range x[i] {
+			dx := x[i][j] - meanX
+			dy := y[i][j] - meanY
+			numerator += dx * dy
+			denominatorX += dx * dx
+			denominatorY += dy * dy
+		}
+	}
+
+	if denominatorX == 0 || denominatorY == 0 {
+		// Flat images only correlate when they are flat in the same way
+		if denominatorX == denominatorY {
+			return 1
+		}
+		return 0
+	}
+
+	return numerator / math.Sqrt(denominatorX*denominatorY)
+}
@@ -0,0 +1,83 @@
+package analysis
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func gradientImage(width, height int, offset uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8((x*7+y*3)%200) + offset
+			img.Set(x, y, color.RGBA{v, v / 2, 255 - v, 255})
+		}
+	}
+	return img
+}
+
+func TestStructuralSimilarity(t *testing.T) {
+	img := gradientImage(64, 64, 0)
+	shifted := gradientImage(64, 64, 40)
+
+	if ssim, err := StructuralSimilarity(img, img, SSIMOptions{}); err != nil || math.Abs(ssim-1) > 1e-9 {
+		t.Errorf("SSIM of identical images = %f, expected 1", ssim)
+	}
+	if msssim, err := MultiScaleStructuralSimilarity(img, img, SSIMOptions{}); err != nil || math.Abs(msssim-1) > 1e-9 {
+		t.Errorf("MS-SSIM of identical images = %f, expected 1", msssim)
+	}
+	if ssim, err := StructuralSimilarity(img, shifted, SSIMOptions{WindowSize: 7, Sigma: -1}); err != nil || ssim >= 1 || ssim <= 0 {
+		t.Errorf("SSIM of different images = %f, expected value in (0, 1)", ssim)
+	}
+}
+
+func TestNormalizedCrossCorrelation(t *testing.T) {
+	img := gradientImage(32, 32, 0)
+	brighter := gradientImage(32, 32, 40)
+
+	// The luma of both images differs only by a constant offset
+	if ncc, err := NormalizedCrossCorrelation(img, brighter); err != nil || math.Abs(ncc-1) > 1e-9 {
+		t.Errorf("NCC of offset images = %f, expected 1", ncc)
+	}
+}
+
+func TestMeanAbsoluteError(t *testing.T) {
+	img1 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img2 := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img1.Set(0, 0, color.RGBA{10, 20, 30, 255})
+	img2.Set(0, 0, color.RGBA{13, 20, 27, 255})
+
+	// 6 / (3 channels * 2 pixels)
+	if mae, err := MeanAbsoluteError(img1, img2); err != nil || mae != 1 {
+		t.Errorf("MAE = %f, expected 1", mae)
+	}
+}
+
+func TestDeltaE2000(t *testing.T) {
+	// Selected pairs from the test data published by Sharma, Wu and Dalal
+	tests := []struct {
+		c1, c2   Lab
+		expected float64
+	}{
+		{Lab{50, 2.6772, -79.7751}, Lab{50, 0, -82.7485}, 2.0425},
+		{Lab{50, 0, 0}, Lab{50, -1, 2}, 2.3669},
+		{Lab{50, 2.5, 0}, Lab{73, 25, -18}, 27.1492},
+		{Lab{50, 2.5, 0}, Lab{50, 0, -2.5}, 4.3065},
+		{Lab{2.0776, 0.0795, -1.1350}, Lab{0.9033, -0.0636, -0.5514}, 0.9082},
+	}
+
+	for _, tt := range tests {
+		if got := DeltaE2000(tt.c1, tt.c2); math.Abs(got-tt.expected) > 1e-4 {
+			t.Errorf("DeltaE2000(%v, %v) = %.4f, expected %.4f", tt.c1, tt.c2, got, tt.expected)
+		}
+	}
+}
+
+func TestRGBToLab(t *testing.T) {
+	white := RGBToLab(255, 255, 255)
+	if math.Abs(white.L-100) > 1e-2 || math.Abs(white.A) > 1e-2 || math.Abs(white.B) > 1e-2 {
+		t.Errorf("RGBToLab(white) = %+v, expected {100 0 0}", white)
+	}
+}
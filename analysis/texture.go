@@ -0,0 +1,320 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GLCMOffset is the displacement from a reference pixel to its neighbour, y grows downwards.
+type GLCMOffset struct {
+	DX int `json:"dx"`
+	DY int `json:"dy"`
+}
+
+func (o GLCMOffset) String() string {
+	return fmt.Sprintf("%d:%d", o.DX, o.DY)
+}
+
+// OffsetForAngle returns the offset of a neighbour at the given distance and angle, one of 0, 45, 90 or 135 degrees.
+func OffsetForAngle(distance, angle int) (GLCMOffset, error) {
+	switch angle {
+	case 0:
+		return GLCMOffset{DX: distance}, nil
+	case 45:
+		return GLCMOffset{DX: distance, DY: -distance}, nil
+	case 90:
+		return GLCMOffset{DY: -distance}, nil
+	case 135:
+		return GLCMOffset{DX: -distance, DY: -distance}, nil
+	default:
+		return GLCMOffset{}, fmt.Errorf("unsupported angle %d, expected 0, 45, 90 or 135", angle)
+	}
+}
+
+// ParseGLCMOffsets parses a comma separated list of dx:dy offsets, e.g. "1:0,0:-1".
+func ParseGLCMOffsets(value string) ([]GLCMOffset, error) {
+	var offsets []GLCMOffset
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		dx, dy, found := strings.Cut(part, ":")
+		x, errX := strconv.Atoi(dx)
+		y, errY := strconv.Atoi(dy)
+		if !found || errX != nil || errY != nil {
+			return nil, fmt.Errorf("invalid offset %q, expected dx:dy", part)
+		}
+		if x == 0 && y == 0 {
+			return nil, fmt.Errorf("offset %q does not point to a neighbour", part)
+		}
+
+		offsets = append(offsets, GLCMOffset{DX: x, DY: y})
+	}
+
+	return offsets, nil
+}
+
+// ParseGLCMAngles parses a comma separated list of angles in degrees, e.g. "0,45,90,135".
+func ParseGLCMAngles(value string) ([]int, error) {
+	var angles []int
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		angle, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid angle %q", part)
+		}
+		if _, err := OffsetForAngle(1, angle); err != nil {
+			return nil, err
+		}
+
+		angles = append(angles, angle)
+	}
+
+	return angles, nil
+}
+
+// GLCMOptions configures the gray-level co-occurrence matrices.
+type GLCMOptions struct {
+	// Offsets lists the neighbour displacements explicitly, when empty they are built from Distance and Angles.
+	Offsets  []GLCMOffset
+	Distance int
+	Angles   []int
+	// Levels is the number of gray levels luma is quantized to, from 2 to 256.
+	Levels int
+	// Symmetric counts every pair in both directions, which makes the result independent of the offset sign.
+	Symmetric bool
+}
+
+var DefaultGLCMOptions = GLCMOptions{Distance: 1, Angles: []int{0, 45, 90, 135}, Levels: 8, Symmetric: true}
+
+// offsets returns the explicit offsets or the ones built from the distance and angles.
+func (o GLCMOptions) offsets() ([]GLCMOffset, error) {
+	if len(o.Offsets) > 0 {
+		return o.Offsets, nil
+	}
+
+	if o.Distance < 1 {
+		return nil, fmt.Errorf("GLCM distance must be at least 1, got %d", o.Distance)
+	}
+	if len(o.Angles) == 0 {
+		return nil, errors.New("at least one GLCM angle or offset is required")
+	}
+
+	offsets := make([]GLCMOffset, 0, len(o.Angles))
+	for _, angle := range o.Angles {
+		offset, err := OffsetForAngle(o.Distance, angle)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+
+// GLCM is a normalized gray-level co-occurrence matrix, P[i][j] is the probability of level j
+// being found at Offset from a pixel of level i.
+type GLCM struct {
+	Offset GLCMOffset
+	Levels int
+	P      [][]float64
+}
+
+// quantizeLuma maps the luma of every pixel to one of the given number of levels.
+func quantizeLuma(img image.Image, levels int) [][]int {
+	luma := luminanceMatrix(img)
+	quantized := make([][]int, len(luma))
+
+	for y, row := range luma {
+		quantized[y] = make([]int, len(row))
+		for x, value := range row {
+			level := int(math.Round(value)) * levels / 256
+			quantized[y][x] = min(level, levels-1)
+		}
+	}
+
+	return quantized
+}
+
+func computeGLCM(quantized [][]int, offset GLCMOffset, levels int, symmetric bool) GLCM {
+	glcm := GLCM{Offset: offset, Levels: levels, P: make([][]float64, levels)}
+	for i := range glcm.P {
+		glcm.P[i] = make([]float64, levels)
+	}
+
+	var pairs float64
+	for y, row := range quantized {
+		ny := y + offset.DY
+		if ny < 0 || ny >= len(quantized) {
+			continue
+		}
+		for x, level := range row {
+			nx := x + offset.DX
+			if nx < 0 || nx >= len(row) {
+				continue
+			}
+
+			neighbour := quantized[ny][nx]
+			glcm.P[level][neighbour]++
+			pairs++
+			if symmetric {
+				glcm.P[neighbour][level]++
+				pairs++
+			}
+		}
+	}
+
+	if pairs > 0 {
+		for i := range glcm.P {
+			for j := range glcm.P[i] {
+				glcm.P[i][j] /= pairs
+			}
+		}
+	}
+
+	return glcm
+}
+
+// ComputeGLCM calculates the co-occurrence matrix of the quantized luma of img for a single offset.
+func ComputeGLCM(img image.Image, offset GLCMOffset, levels int, symmetric bool) (GLCM, error) {
+	if levels < 2 || levels > 256 {
+		return GLCM{}, fmt.Errorf("GLCM levels must be between 2 and 256, got %d", levels)
+	}
+	return computeGLCM(quantizeLuma(img, levels), offset, levels, symmetric), nil
+}
+
+// HaralickFeatures are texture descriptors calculated from a co-occurrence matrix.
+type HaralickFeatures struct {
+	// Contrast is the intensity difference between neighbours, sum of (i-j)^2 * p(i,j).
+	Contrast float64 `json:"contrast"`
+	// Correlation is the linear dependency of neighbour levels, 1 for uniform images.
+	Correlation float64 `json:"correlation"`
+	// Energy is the angular second moment, sum of p(i,j)^2.
+	Energy float64 `json:"energy"`
+	// Homogeneity is the inverse difference moment, sum of p(i,j) / (1 + (i-j)^2).
+	Homogeneity float64 `json:"homogeneity"`
+	// Entropy is the randomness of the matrix in bits.
+	Entropy float64 `json:"entropy"`
+}
+
+// Haralick calculates the Haralick features of the matrix.
+func (g GLCM) Haralick() HaralickFeatures {
+	var features HaralickFeatures
+	var meanI, meanJ float64
+
+	for i, row := range g.P {
+		for j, p := range row {
+			if p == 0 {
+				continue
+			}
+
+			d := float64(i - j)
+			features.Contrast += d * d * p
+			features.Energy += p * p
+			features.Homogeneity += p / (1 + d*d)
+			features.Entropy -= p * math.Log2(p)
+
+			meanI += float64(i) * p
+			meanJ += float64(j) * p
+		}
+	}
+
+	var varianceI, varianceJ, covariance float64
+	for i, row := range g.P {
+		for j, p := range row {
+			di, dj := float64(i)-meanI, float64(j)-meanJ
+			varianceI += di * di * p
+			varianceJ += dj * dj * p
+			covariance += di * dj * p
+		}
+	}
+
+	if varianceI == 0 || varianceJ == 0 {
+		features.Correlation = 1
+	} else {
+		features.Correlation = covariance / math.Sqrt(varianceI*varianceJ)
+	}
+
+	return features
+}
+
+// OffsetTextureFeatures are the Haralick features of the matrix of a single offset.
+type OffsetTextureFeatures struct {
+	Offset GLCMOffset `json:"offset"`
+	HaralickFeatures
+}
+
+// TextureFeatures holds the Haralick features of every offset and their mean, which is less sensitive to orientation.
+type TextureFeatures struct {
+	Levels    int                     `json:"levels"`
+	PerOffset []OffsetTextureFeatures `json:"per_offset"`
+	Mean      HaralickFeatures        `json:"mean"`
+}
+
+// CalculateTextureFeatures calculates the co-occurrence matrices of img for every configured offset and their Haralick features.
+func CalculateTextureFeatures(img image.Image, opts GLCMOptions) (TextureFeatures, error) {
+	if opts.Levels < 2 || opts.Levels > 256 {
+		return TextureFeatures{}, fmt.Errorf("GLCM levels must be between 2 and 256, got %d", opts.Levels)
+	}
+
+	offsets, err := opts.offsets()
+	if err != nil {
+		return TextureFeatures{}, err
+	}
+
+	quantized := quantizeLuma(img, opts.Levels)
+	features := TextureFeatures{Levels: opts.Levels}
+
+	for _, offset := range offsets {
+		haralick := computeGLCM(quantized, offset, opts.Levels, opts.Symmetric).Haralick()
+		features.PerOffset = append(features.PerOffset, OffsetTextureFeatures{Offset: offset, HaralickFeatures: haralick})
+
+		features.Mean.Contrast += haralick.Contrast
+		features.Mean.Correlation += haralick.Correlation
+		features.Mean.Energy += haralick.Energy
+		features.Mean.Homogeneity += haralick.Homogeneity
+		features.Mean.Entropy += haralick.Entropy
+	}
+
+	n := float64(len(offsets))
+	features.Mean.Contrast /= n
+	features.Mean.Correlation /= n
+	features.Mean.Energy /= n
+	features.Mean.Homogeneity /= n
+	features.Mean.Entropy /= n
+
+	return features, nil
+}
+
+// Entries lists the mean features as characteristic entries.
+func (f TextureFeatures) Entries(imageName string) []CharacteristicsEntry {
+	entry := func(label string, value float64, unit Unit) CharacteristicsEntry {
+		return CharacteristicsEntry{
+			MetricMethod: "GLCM",
+			Description:  fmt.Sprintf("Calculated GLCM %s with %d levels over %d offsets for %s", strings.ToLower(label), f.Levels, len(f.PerOffset), imageName),
+			Label:        label,
+			MetricValue:  MetricValue{Value: value, Unit: unit},
+			Img1Name:     imageName,
+		}
+	}
+
+	return []CharacteristicsEntry{
+		entry("Contrast", f.Mean.Contrast, UnitNone),
+		entry("Correlation", f.Mean.Correlation, UnitNone),
+		entry("Energy", f.Mean.Energy, UnitNone),
+		entry("Homogeneity", f.Mean.Homogeneity, UnitNone),
+		entry("Entropy", f.Mean.Entropy, UnitBits),
+	}
+}
@@ -0,0 +1,95 @@
+package analysis
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+// textureTestImage is the 4 level example of the scikit-image graycomatrix documentation.
+func textureTestImage() image.Image {
+	levels := [][]uint8{
+		{0, 0, 1, 1},
+		{0, 0, 1, 1},
+		{0, 2, 2, 2},
+		{2, 2, 3, 3},
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for y, row := range levels {
+		for x, level := range row {
+			img.SetGray(x, y, color.Gray{Y: level * 64})
+		}
+	}
+	return img
+}
+
+func TestComputeGLCM(t *testing.T) {
+	glcm, err := ComputeGLCM(textureTestImage(), GLCMOffset{DX: 1}, 4, false)
+	if err != nil {
+		t.Fatalf("ComputeGLCM returned error: %v", err)
+	}
+
+	expected := [][]float64{
+		{2, 2, 1, 0},
+		{0, 2, 0, 0},
+		{0, 0, 3, 1},
+		{0, 0, 0, 1},
+	}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(glcm.P[i][j]*12-expected[i][j]) > 1e-9 {
+				t.Errorf("P[%d][%d] = %v, expected %v/12", i, j, glcm.P[i][j], expected[i][j])
+			}
+		}
+	}
+
+	features := glcm.Haralick()
+	if math.Abs(features.Contrast-7.0/12) > 1e-9 {
+		t.Errorf("Contrast = %v, expected %v", features.Contrast, 7.0/12)
+	}
+	if math.Abs(features.Energy-24.0/144) > 1e-9 {
+		t.Errorf("Energy = %v, expected %v", features.Energy, 24.0/144)
+	}
+}
+
+func TestCalculateTextureFeaturesUniform(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 8, 8))
+	for i := range img.Pix {
+		img.Pix[i] = 100
+	}
+
+	features, err := CalculateTextureFeatures(img, DefaultGLCMOptions)
+	if err != nil {
+		t.Fatalf("CalculateTextureFeatures returned error: %v", err)
+	}
+
+	if len(features.PerOffset) != 4 {
+		t.Errorf("expected 4 offsets, got %d", len(features.PerOffset))
+	}
+
+	mean := features.Mean
+	if mean.Contrast != 0 || mean.Correlation != 1 || mean.Energy != 1 || mean.Homogeneity != 1 || mean.Entropy != 0 {
+		t.Errorf("unexpected features of a uniform image %+v", mean)
+	}
+}
+
+func TestGLCMOptionsValidation(t *testing.T) {
+	img := textureTestImage()
+
+	if _, err := CalculateTextureFeatures(img, GLCMOptions{Distance: 1, Angles: []int{30}, Levels: 8}); err == nil {
+		t.Error("expected error for an unsupported angle")
+	}
+	if _, err := CalculateTextureFeatures(img, GLCMOptions{Distance: 1, Angles: []int{0}, Levels: 1}); err == nil {
+		t.Error("expected error for a single gray level")
+	}
+	if _, err := ParseGLCMOffsets("0:0"); err == nil {
+		t.Error("expected error for a zero offset")
+	}
+
+	offsets, err := ParseGLCMOffsets("1:0, 0:-2")
+	if err != nil || len(offsets) != 2 || offsets[1] != (GLCMOffset{DX: 0, DY: -2}) {
+		t.Errorf("ParseGLCMOffsets = %v, %v", offsets, err)
+	}
+}
@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
 	"image"
 	"imagio/analysis"
@@ -13,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,7 +22,9 @@ type commandInvocation struct {
 	Name        string
 	Description string
 	Result      string
-	Duration    time.Duration
+	// Details holds additional, already indented lines printed below the result.
+	Details  string
+	Duration time.Duration
 }
 
 type ImageQueueItem struct {
@@ -30,6 +34,23 @@ type ImageQueueItem struct {
 	IsHistogram bool
 }
 
+// DataQueueItem is a non-image result, e.g. an exported histogram, saved next to the images.
+type DataQueueItem struct {
+	Data     []byte
+	Filename string
+}
+
+// morphologicalOutputNames are the words used in the output file names of the structuring element operations.
+var morphologicalOutputNames = map[string]string{
+	"dilation": "dilated",
+	"erosion":  "eroded",
+	"opening":  "opened",
+	"closing":  "closed",
+	"gradient": "gradient",
+	"tophat":   "tophat",
+	"blackhat": "blackhat",
+}
+
 func RunAsCliApp() {
 
 	imagePath := os.Args[len(os.Args)-1]
@@ -61,11 +82,35 @@ func RunAsCliApp() {
 
 	var commandResults []commandInvocation
 	var imageQueue []ImageQueueItem
+	var dataQueue []DataQueueItem
 
 	for _, command := range commands {
 		cmdResult := commandInvocation{Name: command.Name}
 		startTime := time.Now()
 
+		// reportRows fills cmdResult for every row of a command reporting several values, every row except
+		// the last one is reported on its own and the last one is left to be reported like a single result
+		reportRows := func(count int, fill func(i int)) {
+			for i := 0; i < count; i++ {
+				fill(i)
+
+				if i < count-1 {
+					cmdResult.Duration = time.Since(startTime)
+					commandResults = append(commandResults, cmdResult)
+					durationSum += cmdResult.Duration
+
+					cmdResult = commandInvocation{Name: command.Name}
+					startTime = time.Now()
+				}
+			}
+		}
+
+		if structureElementsFile, ok := command.Args["sefile"]; ok {
+			if err := morphological.LoadStructureElementsFile(structureElementsFile); err != nil {
+				log.Fatalf("Error loading structure elements file: %v", err)
+			}
+		}
+
 		switch command.Name {
 		case "brightness":
 			brightness, err := strconv.Atoi(command.Args["value"])
@@ -180,11 +225,26 @@ func RunAsCliApp() {
 				log.Fatal("Max window size must be greater than min window size")
 			}
 
-			newImg := noise.AdaptiveMedianFilter(img, minWindowSize, maxWindowSize)
+			withStats := GetOrDefault(command.Args["stats"], 0) == 1
+			withDiagnostics := GetOrDefault(command.Args["diagnostics"], 0) == 1
+
+			newImg, report := noise.AdaptiveMedianFilterWithReport(img, minWindowSize, maxWindowSize, getBorderPolicy(command))
 			outputFileName := fmt.Sprintf("%s_adaptive_median_filter.bmp", originalNameWithoutExt)
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, Denoised: true})
 
+			if withDiagnostics {
+				heatMapFileName := fmt.Sprintf("%s_adaptive_median_window_sizes.bmp", originalNameWithoutExt)
+				maskFileName := fmt.Sprintf("%s_adaptive_median_modified_mask.bmp", originalNameWithoutExt)
+
+				imageQueue = append(imageQueue, ImageQueueItem{Image: report.WindowSizeHeatMap(), Filename: heatMapFileName})
+				imageQueue = append(imageQueue, ImageQueueItem{Image: report.ModifiedMask(), Filename: maskFileName})
+			}
+
+			if withStats || withDiagnostics {
+				cmdResult.Result = report.Summary()
+			}
+
 			cmdResult.Description = "Adaptive median filter applied"
 
 		case "adaptive-parallel":
@@ -196,11 +256,26 @@ func RunAsCliApp() {
 				log.Fatal("Max window size must be greater than min window size")
 			}
 
-			newImg := noise.AdaptiveMedianFilterParallel(img, minWindowSize, maxWindowSize)
+			withStats := GetOrDefault(command.Args["stats"], 0) == 1
+			withDiagnostics := GetOrDefault(command.Args["diagnostics"], 0) == 1
+
+			newImg, report := noise.AdaptiveMedianFilterParallelWithReport(img, minWindowSize, maxWindowSize, getBorderPolicy(command))
 			outputFileName := fmt.Sprintf("%s_adaptive_parallel_median_filter.bmp", originalNameWithoutExt)
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, Denoised: true})
 
+			if withDiagnostics {
+				heatMapFileName := fmt.Sprintf("%s_adaptive_parallel_median_window_sizes.bmp", originalNameWithoutExt)
+				maskFileName := fmt.Sprintf("%s_adaptive_parallel_median_modified_mask.bmp", originalNameWithoutExt)
+
+				imageQueue = append(imageQueue, ImageQueueItem{Image: report.WindowSizeHeatMap(), Filename: heatMapFileName})
+				imageQueue = append(imageQueue, ImageQueueItem{Image: report.ModifiedMask(), Filename: maskFileName})
+			}
+
+			if withStats || withDiagnostics {
+				cmdResult.Result = report.Summary()
+			}
+
 			cmdResult.Description = fmt.Sprintf("Adaptive median filter applied with min window size %d and max window size %d", minWindowSize, maxWindowSize)
 
 		case "min":
@@ -209,7 +284,7 @@ func RunAsCliApp() {
 			if err != nil {
 				log.Fatalf("Window size must be an int: %v", err)
 			}
-			newImg := noise.MinFilter(img, windowSize)
+			newImg := noise.MinFilter(img, windowSize, getBorderPolicy(command))
 			outputFileName := fmt.Sprintf("%s_min_filter.bmp", originalNameWithoutExt)
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, Denoised: true})
@@ -222,105 +297,118 @@ func RunAsCliApp() {
 			if err != nil {
 				log.Fatalf("Window size must be an int: %v", err)
 			}
-			newImg := noise.MaxFilter(img, windowSize)
+			newImg := noise.MaxFilter(img, windowSize, getBorderPolicy(command))
 			outputFileName := fmt.Sprintf("%s_max_filter.bmp", originalNameWithoutExt)
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, Denoised: true})
 
 			cmdResult.Description = fmt.Sprintf("Max filter applied with window size %d", windowSize)
 
-		case "mse":
+		case "mse", "pmse", "snr", "psnr", "md", "ssim", "ms-ssim", "psnr-rgb", "mae", "ncc", "ciede2000":
 			if comparisonImage == nil {
-				log.Fatalf("Comparison image is required for MSE.")
+				log.Fatalf("Comparison image is required for %s.", strings.ToUpper(command.Name))
 			}
 
-			var mse float64
-			lastDenoisedImage := getLastDenoisedImage(imageQueue)
-
-			if lastDenoisedImage != nil {
-				mse = analysis.MeanSquareError(lastDenoisedImage, comparisonImage)
-			} else {
-				mse = analysis.MeanSquareError(img, comparisonImage)
+			compared := image.Image(img)
+			if lastDenoisedImage := getLastDenoisedImage(imageQueue); lastDenoisedImage != nil {
+				compared = lastDenoisedImage
 			}
 
-			cmdResult.Description = "Mean Square Error calculated"
-			cmdResult.Result = fmt.Sprintf("MSE: %f", mse)
+			options := getComparisonOptions(command)
 
-		case "pmse":
-			if comparisonImage == nil {
-				log.Fatalf("Comparison image is required for PMSE.")
+			result, err := analysis.CalculateComparisonCharacteristic(command.Name, compared, comparisonImage, options)
+			if err != nil {
+				log.Fatalf("Error comparing images for %s: %v", command.Name, err)
 			}
+			cmdResult.Description = result.Description
+			cmdResult.Result = result.FormatResult()
 
-			var pmse float64
-			lastDenoisedImage := getLastDenoisedImage(imageQueue)
+			if command.Args["diff"] == "1" {
+				alignedCompared, alignedComparison, err := analysis.AlignImages(compared, comparisonImage, options.Alignment)
+				if err != nil {
+					log.Fatalf("Error aligning images for %s: %v", command.Name, err)
+				}
 
-			if lastDenoisedImage != nil {
-				pmse = analysis.PeakMeanSquareError(lastDenoisedImage, comparisonImage)
-			} else {
-				pmse = analysis.PeakMeanSquareError(img, comparisonImage)
-			}
+				differenceImg, err := analysis.AbsoluteDifferenceImage(alignedCompared, alignedComparison)
+				if err != nil {
+					log.Fatalf("Error computing difference images for %s: %v", command.Name, err)
+				}
+				heatMapImg, err := analysis.DifferenceHeatMap(alignedCompared, alignedComparison)
+				if err != nil {
+					log.Fatalf("Error computing difference images for %s: %v", command.Name, err)
+				}
 
-			cmdResult.Description = "Peak Mean Square Error calculated"
-			cmdResult.Result = fmt.Sprintf("PMSE: %f", pmse)
+				imageQueue = append(imageQueue,
+					ImageQueueItem{Image: differenceImg, Filename: fmt.Sprintf("%s_%s_abs_difference.bmp", originalNameWithoutExt, command.Name)},
+					ImageQueueItem{Image: heatMapImg, Filename: fmt.Sprintf("%s_%s_difference_heatmap.bmp", originalNameWithoutExt, command.Name)},
+				)
 
-		case "snr":
-			if comparisonImage == nil {
-				log.Fatalf("Comparison image is required for SNR.")
+				cmdResult.Description += ", difference images saved"
 			}
 
-			var snr float64
-			lastDenoisedImage := getLastDenoisedImage(imageQueue)
+		case "histogram":
 
-			if lastDenoisedImage != nil {
-				snr = analysis.SignalToNoiseRatio(lastDenoisedImage, comparisonImage)
-			} else {
-				snr = analysis.SignalToNoiseRatio(img, comparisonImage)
-			}
+			channels := getHistogramChannels(command)
+			plotOptions := getHistogramPlotOptions(command)
 
-			cmdResult.Description = "Signal to Noise Ratio calculated"
-			cmdResult.Result = fmt.Sprintf("SNR: %f", snr)
+			for _, channel := range channels {
+				outputFileName := getHistogramFileName(originalNameWithoutExt, channel)
+				newImg, err := manipulations.PlotHistogram(manipulations.CalculateChannelHistogram(img, channel), channel, plotOptions)
+				if err != nil {
+					log.Fatalf("Error plotting histogram for %s: %v", command.Name, err)
+				}
 
-		case "psnr":
-			if comparisonImage == nil {
-				log.Fatalf("Comparison image is required for PSNR.")
+				imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, IsHistogram: true})
+
+				if command.Args["compare"] == "1" {
+					if comparisonImage == nil {
+						log.Fatalf("Comparison image is required to compare histograms.")
+					}
+
+					comparisonName := filepath.Base(comparisonImagePath)
+					comparisonImg, err := manipulations.GenerateHistogramComparison(
+						manipulations.CalculateChannelHistogram(comparisonImage, channel),
+						manipulations.CalculateChannelHistogram(img, channel),
+						comparisonName, originalName, channel, plotOptions,
+					)
+					if err != nil {
+						log.Fatalf("Error plotting histogram comparison for %s: %v", command.Name, err)
+					}
+					comparisonFileName := strings.TrimSuffix(outputFileName, ".bmp") + "_comparison.bmp"
+
+					imageQueue = append(imageQueue, ImageQueueItem{Image: comparisonImg, Filename: comparisonFileName, IsHistogram: true})
+				}
 			}
 
-			var psnr float64
-			lastDenoisedImage := getLastDenoisedImage(imageQueue)
+			if len(channels) > 1 {
+				overlaidImg, err := manipulations.GenerateOverlaidRGBHistogram(
+					manipulations.CalculateChannelHistogram(img, manipulations.HistogramRed),
+					manipulations.CalculateChannelHistogram(img, manipulations.HistogramGreen),
+					manipulations.CalculateChannelHistogram(img, manipulations.HistogramBlue),
+					plotOptions,
+				)
+				if err != nil {
+					log.Fatalf("Error plotting histogram for %s: %v", command.Name, err)
+				}
+				outputFileName := fmt.Sprintf("%s_histogram_rgb.bmp", originalNameWithoutExt)
 
-			if lastDenoisedImage != nil {
-				psnr = analysis.PeakSignalToNoiseRatio(lastDenoisedImage, comparisonImage)
-			} else {
-				psnr = analysis.PeakSignalToNoiseRatio(img, comparisonImage)
+				imageQueue = append(imageQueue, ImageQueueItem{Image: overlaidImg, Filename: outputFileName, IsHistogram: true})
 			}
 
-			cmdResult.Description = "Peak Signal to Noise Ratio calculated"
-			cmdResult.Result = fmt.Sprintf("PSNR: %f", psnr)
-
-		case "md":
-			if comparisonImage == nil {
-				log.Fatalf("Comparison image is required for MD.")
-			}
+			if exportArg, ok := command.Args["export"]; ok {
+				format, err := analysis.ParseHistogramExportFormat(exportArg)
+				if err != nil {
+					log.Fatalf("Invalid export argument for %s: %v", command.Name, err)
+				}
 
-			var md int
-			lastDenoisedImage := getLastDenoisedImage(imageQueue)
+				var data bytes.Buffer
+				if err := analysis.NewHistogramExport(img, originalName, channels).Write(&data, format); err != nil {
+					log.Fatalf("Error exporting histogram: %v", err)
+				}
 
-			if lastDenoisedImage != nil {
-				md = analysis.MaxDifference(lastDenoisedImage, comparisonImage)
-			} else {
-				md = analysis.MaxDifference(img, comparisonImage)
+				dataQueue = append(dataQueue, DataQueueItem{Data: data.Bytes(), Filename: getHistogramDataFileName(originalNameWithoutExt, channels, format)})
 			}
 
-			cmdResult.Description = "Max Difference calculated"
-			cmdResult.Result = fmt.Sprintf("Max Difference: %d", md)
-
-		case "histogram":
-
-			outputFileName := fmt.Sprintf("%s_histogram.bmp", originalNameWithoutExt)
-			newImg := manipulations.GenerateGraphicalRepresentationOfHistogram(manipulations.CalculateHistogram(img))
-
-			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName, IsHistogram: true})
-
 			cmdResult.Description = "Computed Graphical Representation of Histogram"
 
 		case "cmean", "cvariance", "cstdev", "cvarcoi", "casyco", "cflatco", "cvarcoii", "centropy":
@@ -336,18 +424,88 @@ func RunAsCliApp() {
 				}
 			}
 
-			var histogram [256]int
-
+			sourceImg := image.Image(histogramImg)
 			if histogramImg == nil {
-				histogram = manipulations.CalculateHistogram(img)
+				sourceImg = img
 				histogramImgFilename = fmt.Sprintf("%s_histogram.bmp", originalNameWithoutExt)
-			} else {
-				histogram = manipulations.CalculateHistogram(histogramImg)
 			}
 
-			result := analysis.CalculateHistogramCharacteristic(command.Name, histogram, histogramImgFilename)
-			cmdResult.Result = result.Result
-			cmdResult.Description = result.Description
+			channels := getHistogramChannels(command)
+
+			reportRows(len(channels), func(i int) {
+				channel := channels[i]
+				histogram := manipulations.CalculateChannelHistogram(sourceImg, channel)
+
+				result, err := analysis.CalculateHistogramCharacteristic(command.Name, histogram, histogramImgFilename)
+				if err != nil {
+					log.Fatalf("Error calculating histogram characteristic: %v", err)
+				}
+				if channel != manipulations.HistogramValue || len(channels) > 1 {
+					result.Channel = analysis.HistogramChannelOf(channel)
+				}
+
+				cmdResult.Result = result.FormatResult()
+				cmdResult.Description = result.Description
+			})
+
+		case "stats":
+
+			percentiles, err := analysis.ParsePercentiles(command.Args["percentiles"])
+			if err != nil {
+				log.Fatalf("Invalid percentiles argument for %s: %v", command.Name, err)
+			}
+
+			statsOptions := analysis.StatisticsOptions{
+				Percentiles:        percentiles,
+				GrayscaleTolerance: GetOrDefault(command.Args["tolerance"], 0),
+			}
+
+			stats := analysis.CalculateImageStatistics(img, originalName, statsOptions)
+
+			switch format := GetOrDefault(command.Args["format"], "text"); format {
+			case "text":
+				var details strings.Builder
+				if err := stats.WriteText(&details); err != nil {
+					log.Fatalf("Error formatting statistics: %v", err)
+				}
+				cmdResult.Details = details.String()
+
+			case "json":
+				var data bytes.Buffer
+				if err := stats.WriteJSON(&data); err != nil {
+					log.Fatalf("Error encoding statistics: %v", err)
+				}
+				dataQueue = append(dataQueue, DataQueueItem{Data: data.Bytes(), Filename: fmt.Sprintf("%s_stats.json", originalNameWithoutExt)})
+
+			default:
+				log.Fatalf("Invalid format argument for %s: %q, expected text or json", command.Name, format)
+			}
+
+			cmdResult.Description = fmt.Sprintf("Calculated image statistics for %s", originalNameWithoutExt)
+			cmdResult.Result = "Image: " + stats.Summary()
+
+		case "glcm":
+
+			textureFeatures, err := analysis.CalculateTextureFeatures(img, getGLCMOptions(command))
+			if err != nil {
+				log.Fatalf("Error calculating texture features: %v", err)
+			}
+
+			entries := textureFeatures.Entries(originalName)
+
+			reportRows(len(entries), func(i int) {
+				cmdResult.Result = entries[i].FormatResult()
+				cmdResult.Description = entries[i].Description
+			})
+
+			if len(textureFeatures.PerOffset) > 1 {
+				var details strings.Builder
+				for _, offset := range textureFeatures.PerOffset {
+					fmt.Fprintf(&details, "  Offset %s: contrast=%.6f correlation=%.6f energy=%.6f homogeneity=%.6f entropy=%.6f\n",
+						offset.Offset, offset.Contrast, offset.Correlation, offset.Energy, offset.Homogeneity, offset.Entropy)
+				}
+				cmdResult.Details = details.String()
+			}
 
 		case "hrayleigh":
 
@@ -363,11 +521,27 @@ func RunAsCliApp() {
 
 			newImg := manipulations.EnhanceImageWithRayleigh(img, float64(gMin), float64(gMax), alpha)
 
-			if commands.Includes("histogram") {
-				histogramImgAfterTransformation := manipulations.GenerateGraphicalRepresentationOfHistogram(manipulations.CalculateHistogram(newImg))
+			if histogramCommand, ok := commands.Find("histogram"); ok {
+				plotOptions := getHistogramPlotOptions(histogramCommand)
+				histogramBefore := manipulations.CalculateHistogram(img)
+				histogramAfter := manipulations.CalculateHistogram(newImg)
+
+				histogramImgAfterTransformation, err := manipulations.PlotHistogram(histogramAfter, manipulations.HistogramValue, plotOptions)
+				if err != nil {
+					log.Fatalf("Error plotting histogram for %s: %v", command.Name, err)
+				}
 				histogramFilename := fmt.Sprintf("%s_histogram_after_rayleigh_min%d_max%d_alpha%.2f.bmp", originalNameWithoutExt, gMin, gMax, alpha)
 
-				imageQueue = append(imageQueue, ImageQueueItem{Image: histogramImgAfterTransformation, Filename: histogramFilename, IsHistogram: true})
+				histogramComparisonImg, err := manipulations.GenerateHistogramComparison(histogramBefore, histogramAfter, "before", "after", manipulations.HistogramValue, plotOptions)
+				if err != nil {
+					log.Fatalf("Error plotting histogram comparison for %s: %v", command.Name, err)
+				}
+				histogramComparisonFilename := fmt.Sprintf("%s_histogram_comparison_rayleigh_min%d_max%d_alpha%.2f.bmp", originalNameWithoutExt, gMin, gMax, alpha)
+
+				imageQueue = append(imageQueue,
+					ImageQueueItem{Image: histogramImgAfterTransformation, Filename: histogramFilename, IsHistogram: true},
+					ImageQueueItem{Image: histogramComparisonImg, Filename: histogramComparisonFilename, IsHistogram: true},
+				)
 			}
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
@@ -385,7 +559,7 @@ func RunAsCliApp() {
 
 			outputFileName := fmt.Sprintf("%s_sharpened_edges_%s.bmp", originalNameWithoutExt, chosenMask)
 
-			newImg := manipulations.ApplyConvolutionUniversal(img, mask)
+			newImg := manipulations.ApplyConvolutionUniversal(img, mask, getBorderPolicy(command))
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
 
@@ -393,11 +567,11 @@ func RunAsCliApp() {
 
 			outputFileName := fmt.Sprintf("%s_kirsh_edge_detection.bmp", originalNameWithoutExt)
 
-			newImg := manipulations.ApplyKirshEdgeDetection(img)
+			newImg := manipulations.ApplyKirshEdgeDetection(img, getBorderPolicy(command))
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
 
-		case "dilation":
+		case "dilation", "erosion", "opening", "closing", "gradient", "tophat", "blackhat":
 
 			chosenStructureElement := GetOrDefault(command.Args["se"], "iv")
 
@@ -407,80 +581,254 @@ func RunAsCliApp() {
 				log.Fatalf("Error getting structural element: %v", err)
 			}
 
-			outputFileName := fmt.Sprintf("%s_dilated_se_%s.bmp", originalNameWithoutExt, chosenStructureElement)
+			mode := getMorphologyMode(command)
 
-			newBinaryImg := morphological.Dilation(morphological.ConvertIntoBinaryImage(img), se)
+			iterations, err := strconv.Atoi(GetOrDefault(command.Args["iterations"], "1"))
+			if err != nil {
+				log.Fatalf("Iterations must be an int number: %v", err)
+			}
 
-			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
+			newImg, err := morphological.ApplyIteratedOperation(img, morphological.Operation(command.Name), se, mode, iterations, getBorderPolicy(command))
 
-		case "erosion":
+			if err != nil {
+				log.Fatalf("Error applying %s: %v", command.Name, err)
+			}
 
-			chosenStructureElement := GetOrDefault(command.Args["se"], "iv")
+			seLabel := morphological.StructureElementLabel(chosenStructureElement)
+			if iterations > 1 {
+				seLabel = fmt.Sprintf("%s_iterations_%d", seLabel, iterations)
+			}
+			outputFileName := fmt.Sprintf("%s_%s_se_%s.bmp", originalNameWithoutExt, morphologicalOutputNames[command.Name], seLabel)
+			if mode == morphological.ModeGray {
+				outputFileName = fmt.Sprintf("%s_%s_gray_se_%s.bmp", originalNameWithoutExt, morphologicalOutputNames[command.Name], seLabel)
+			}
 
-			se, err := morphological.GetStructureElement(chosenStructureElement)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
+
+		case "asf":
 
+			chosenStructureElement := GetOrDefault(command.Args["se"], "iii")
+
+			se, err := morphological.GetStructureElement(chosenStructureElement)
 			if err != nil {
 				log.Fatalf("Error getting structural element: %v", err)
 			}
 
-			outputFileName := fmt.Sprintf("%s_eroded_se_%s.bmp", originalNameWithoutExt, chosenStructureElement)
+			size, err := strconv.Atoi(GetOrDefault(command.Args["size"], "2"))
+			if err != nil {
+				log.Fatalf("Size must be an int number: %v", err)
+			}
 
-			newBinaryImg := morphological.Erosion(morphological.ConvertIntoBinaryImage(img), se)
+			order, err := morphological.ParseASFOrder(command.Args["order"])
+			if err != nil {
+				log.Fatalf("Invalid order argument for asf: %v", err)
+			}
 
-			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
+			mode := getMorphologyMode(command)
 
-		case "opening":
+			newImg, err := morphological.ApplyAlternatingSequentialFilter(img, se, size, order, mode)
+			if err != nil {
+				log.Fatalf("Error applying alternating sequential filter: %v", err)
+			}
 
-			chosenStructureElement := GetOrDefault(command.Args["se"], "iv")
+			outputFileName := fmt.Sprintf("%s_asf_%s_%d_se_%s.bmp", originalNameWithoutExt, order, size, morphological.StructureElementLabel(chosenStructureElement))
+			if mode == morphological.ModeGray {
+				outputFileName = fmt.Sprintf("%s_asf_%s_%d_gray_se_%s.bmp", originalNameWithoutExt, order, size, morphological.StructureElementLabel(chosenStructureElement))
+			}
 
-			se, err := morphological.GetStructureElement(chosenStructureElement)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Alternating sequential filter of size %d, order %s", size, order)
 
+		case "morph":
+
+			expression, err := morphological.ParseExpression(command.Args["expr"])
 			if err != nil {
-				log.Fatalf("Error getting structural element: %v", err)
+				log.Fatalf("Invalid expr argument for morph: %v", err)
 			}
 
-			outputFileName := fmt.Sprintf("%s_opened_se_%s.bmp", originalNameWithoutExt, chosenStructureElement)
+			result, err := expression.Evaluate(morphological.ConvertIntoBinaryImage(img))
+			if err != nil {
+				log.Fatalf("Error evaluating morphology expression: %v", err)
+			}
 
-			newBinaryImg := morphological.Opening(morphological.ConvertIntoBinaryImage(img), se)
+			outputFileName := fmt.Sprintf("%s_morph_%s.bmp", originalNameWithoutExt, morphological.ExpressionLabel(expression.String()))
 
-			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(result), Filename: outputFileName})
 
-		case "closing":
+			cmdResult.Description = fmt.Sprintf("Evaluated %s", expression)
 
-			chosenStructureElement := GetOrDefault(command.Args["se"], "iv")
+		case "reconstruct", "geodesic":
 
-			se, err := morphological.GetStructureElement(chosenStructureElement)
+			if comparisonImage == nil {
+				log.Fatalf("Marker image is required for %s.", command.Name)
+			}
 
+			method, err := morphological.ParseReconstructionMethod(command.Args["method"])
 			if err != nil {
-				log.Fatalf("Error getting structural element: %v", err)
+				log.Fatalf("Invalid method argument for %s: %v", command.Name, err)
 			}
 
-			outputFileName := fmt.Sprintf("%s_closed_se_%s.bmp", originalNameWithoutExt, chosenStructureElement)
+			steps := 0
+			if command.Name == "geodesic" {
+				steps = GetOrDefault(command.Args["steps"], 1)
+				if steps < 1 {
+					log.Fatalf("Invalid steps argument for geodesic: %d, expected at least 1", steps)
+				}
+			}
 
-			newBinaryImg := morphological.Closing(morphological.ConvertIntoBinaryImage(img), se)
+			connectivity := getConnectivity(command)
+			mode := getMorphologyMode(command)
 
-			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
+			newImg, err := morphological.ApplyInMode(img, comparisonImage, mode, func(mask, marker morphological.GrayImage) morphological.GrayImage {
+				return morphological.Reconstruct(marker, mask, method, connectivity, steps)
+			})
+			if err != nil {
+				log.Fatalf("Error applying %s: %v", command.Name, err)
+			}
 
-		case "HMT":
+			outputFileName := fmt.Sprintf("%s_reconstructed_%s_%s.bmp", originalNameWithoutExt, method, mode)
+			if steps > 0 {
+				outputFileName = fmt.Sprintf("%s_geodesic_%s_%d_%s.bmp", originalNameWithoutExt, method, steps, mode)
+			}
 
-			foregroundStructureElement := GetOrDefault(command.Args["se1"], "xi-l")
-			backgroundStructureElement := GetOrDefault(command.Args["se2"], "xi-c")
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
+
+		case "fillholes", "clearborder":
+
+			connectivity := getConnectivity(command)
+			mode := getMorphologyMode(command)
 
-			se1, err1 := morphological.GetStructureElement(foregroundStructureElement)
-			se2, err2 := morphological.GetStructureElement(backgroundStructureElement)
+			operation := morphological.FillHoles
+			outputFileName := fmt.Sprintf("%s_filled_%s.bmp", originalNameWithoutExt, mode)
+			if command.Name == "clearborder" {
+				operation = morphological.ClearBorder
+				outputFileName = fmt.Sprintf("%s_cleared_%s.bmp", originalNameWithoutExt, mode)
+			}
 
-			if err1 != nil || err2 != nil {
-				log.Fatalf("Error getting structural element: %v | %v", err, err2)
+			newImg, err := morphological.ApplyInMode(img, comparisonImage, mode, func(img, seeds morphological.GrayImage) morphological.GrayImage {
+				return operation(img, seeds, connectivity)
+			})
+			if err != nil {
+				log.Fatalf("Error applying %s: %v", command.Name, err)
 			}
 
-			outputFileName := fmt.Sprintf("%s_hmt_se1_%s_se2_%s.bmp", originalNameWithoutExt, foregroundStructureElement, backgroundStructureElement)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
+
+		case "hmaxima", "hminima":
+
+			h := GetOrDefault(command.Args["h"], 10)
+			if h < 0 || h > 255 {
+				log.Fatalf("Invalid h argument for %s: %d, expected a value from 0 to 255", command.Name, h)
+			}
+
+			connectivity := getConnectivity(command)
+
+			operation := morphological.HMaxima
+			if command.Name == "hminima" {
+				operation = morphological.HMinima
+			}
+
+			newImg, err := morphological.ApplyInMode(img, nil, morphological.ModeGray, func(img, _ morphological.GrayImage) morphological.GrayImage {
+				return operation(img, h, connectivity)
+			})
+			if err != nil {
+				log.Fatalf("Error applying %s: %v", command.Name, err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_%s_h%d.bmp", originalNameWithoutExt, command.Name, h)
+
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
 
-			newBinaryImg := morphological.HitOrMiss(morphological.ConvertIntoBinaryImage(img), se1, se2)
+		case "rmaxima":
+
+			maxima := morphological.RegionalMaxima(morphological.ConvertIntoGrayImage(img), getConnectivity(command))
+
+			outputFileName := fmt.Sprintf("%s_regional_maxima.bmp", originalNameWithoutExt)
+
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(maxima), Filename: outputFileName})
+
+		case "distance":
+
+			metric, err := morphological.ParseDistanceCriterion(command.Args["metric"])
+			if err != nil {
+				log.Fatalf("Invalid metric argument for distance: %v", err)
+			}
+
+			dist, err := morphological.DistanceTransform(morphological.ConvertIntoBinaryImage(img), metric)
+			if err != nil {
+				log.Fatalf("Error calculating distance transform: %v", err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_distance_%s.bmp", originalNameWithoutExt, metric)
+
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.DistanceImage(dist), Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Calculated %s distance transform of %s", metric, originalName)
+			cmdResult.Result = fmt.Sprintf("Max distance: %.4f", morphological.MaxDistance(dist))
+
+		case "hmt":
+
+			var pair morphological.HitOrMissPair
+			var label string
+
+			if template, ok := command.Args["template"]; ok {
+				pair, err = morphological.ParseHitOrMissTemplate(template)
+				if err != nil {
+					log.Fatalf("Invalid template argument for hmt: %v", err)
+				}
+				label = "template"
+			} else {
+				foregroundStructureElement := GetOrDefault(command.Args["se1"], "xi-l")
+				backgroundStructureElement := GetOrDefault(command.Args["se2"], "xi-c")
+
+				se1, err1 := morphological.ResolveStructureElement(foregroundStructureElement)
+				se2, err2 := morphological.ResolveStructureElement(backgroundStructureElement)
+
+				if err1 != nil || err2 != nil {
+					log.Fatalf("Error getting structural element: %v | %v", err1, err2)
+				}
+
+				pair = morphological.HitOrMissPair{Foreground: se1, Background: se2}
+				label = fmt.Sprintf("se1_%s_se2_%s", morphological.StructureElementLabel(foregroundStructureElement), morphological.StructureElementLabel(backgroundStructureElement))
+			}
+
+			rotationsCount, err := strconv.Atoi(GetOrDefault(command.Args["rotations"], "1"))
+			if err != nil {
+				log.Fatalf("Rotations must be an int number: %v", err)
+			}
+
+			rotations, err := pair.Rotations(rotationsCount)
+			if err != nil {
+				log.Fatalf("Invalid rotations argument for hmt: %v", err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_hmt_%s.bmp", originalNameWithoutExt, label)
+			if rotationsCount > 1 {
+				outputFileName = fmt.Sprintf("%s_hmt_%s_rotations_%d.bmp", originalNameWithoutExt, label, rotationsCount)
+			}
+
+			newBinaryImg := morphological.HitOrMissUnion(morphological.ConvertIntoBinaryImage(img), rotations, getBorderPolicy(command))
 
 			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
 
+			matches := 0
+			for _, row := range newBinaryImg {
+				for _, value := range row {
+					matches += value
+				}
+			}
+
+			cmdResult.Description = fmt.Sprintf("Hit-or-miss transformation with %d distinct rotations", len(rotations))
+			cmdResult.Result = fmt.Sprintf("Matches: %d", matches)
+
 		case "thinning":
 
+			algorithm, err := morphological.ParseSkeletonAlgorithm(command.Args["method"])
+			if err != nil {
+				log.Fatalf("Invalid method argument for thinning: %v", err)
+			}
+
 			chosenStructuralElementsSeries := GetOrDefault(command.Args["se"], "xii")
 
 			var seSeries []morphological.BinaryImage
@@ -489,20 +837,113 @@ func RunAsCliApp() {
 				seSeries = morphological.SeriesXISE
 			case "xii":
 				seSeries = morphological.SeriesXIISE
+			default:
+				log.Fatalf("Invalid se argument for thinning: %q, expected xi or xii", chosenStructuralElementsSeries)
 			}
 
-			outputFileName := fmt.Sprintf("%s_thinned_se_%s_series_applied.bmp", originalNameWithoutExt, chosenStructuralElementsSeries)
+			skeletonOptions := morphological.SkeletonOptions{
+				Algorithm:     algorithm,
+				Series:        seSeries,
+				Border:        getBorderPolicy(command),
+				MaxIterations: GetOrDefault(command.Args["maxiter"], 0),
+				PruneLength:   GetOrDefault(command.Args["prune"], 0),
+			}
 
-			newBinaryImg := morphological.Thinning(morphological.ConvertIntoBinaryImage(img), seSeries)
+			binaryImg := morphological.ConvertIntoBinaryImage(img)
+			skeleton, err := morphological.Skeletonize(binaryImg, skeletonOptions)
+			if err != nil {
+				log.Fatalf("Error thinning image: %v", err)
+			}
 
-			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(newBinaryImg), Filename: outputFileName})
+			outputFileName := fmt.Sprintf("%s_thinned_%s.bmp", originalNameWithoutExt, algorithm)
+			if algorithm == morphological.SkeletonHitOrMiss {
+				outputFileName = fmt.Sprintf("%s_thinned_se_%s_series_applied.bmp", originalNameWithoutExt, chosenStructuralElementsSeries)
+			}
+
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(skeleton.Image), Filename: outputFileName})
+
+			if skeleton.Distance != nil {
+				medialAxisFileName := fmt.Sprintf("%s_medial_axis_distance.bmp", originalNameWithoutExt)
+				imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.MedialAxisImage(skeleton.Image, skeleton.Distance), Filename: medialAxisFileName})
+			}
+
+			cmdResult.Description = fmt.Sprintf("Thinned with %s in %d iterations", algorithm, skeleton.Iterations)
+			if skeletonOptions.MaxIterations > 0 && skeleton.Iterations == skeletonOptions.MaxIterations {
+				cmdResult.Description = fmt.Sprintf("Thinned with %s, stopped after %d iterations", algorithm, skeleton.Iterations)
+			}
+
+			if GetOrDefault(command.Args["points"], 0) == 1 {
+				endpoints, branchPoints := morphological.SkeletonPoints(skeleton.Image)
+
+				var data bytes.Buffer
+				if err := morphological.WriteSkeletonPointsJSON(&data, endpoints, branchPoints); err != nil {
+					log.Fatalf("Error exporting skeleton points: %v", err)
+				}
+				dataQueue = append(dataQueue, DataQueueItem{Data: data.Bytes(), Filename: fmt.Sprintf("%s_skeleton_points.json", originalNameWithoutExt)})
+
+				overlayFileName := fmt.Sprintf("%s_skeleton_points.bmp", originalNameWithoutExt)
+				imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.DrawSkeletonOverlay(binaryImg, skeleton.Image, endpoints, branchPoints), Filename: overlayFileName})
+
+				cmdResult.Result = fmt.Sprintf("Skeleton points: %d endpoints, %d branch points", len(endpoints), len(branchPoints))
+			}
+
+		case "hull":
+
+			maxIterations := GetOrDefault(command.Args["maxiter"], 0)
+			binaryImg := morphological.ConvertIntoBinaryImage(img)
+
+			var hull morphological.BinaryImage
+			var iterations int
+			outputFileName := fmt.Sprintf("%s_convex_hulls.bmp", originalNameWithoutExt)
+			if GetOrDefault(command.Args["components"], 1) == 1 {
+				hull, iterations = morphological.ComponentConvexHulls(binaryImg, getConnectivity(command), maxIterations)
+			} else {
+				hull, iterations = morphological.ConvexHull(binaryImg, maxIterations)
+				outputFileName = fmt.Sprintf("%s_convex_hull.bmp", originalNameWithoutExt)
+			}
+
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(hull), Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Filled the convex hull in %d iterations", iterations)
+			if maxIterations > 0 && iterations == maxIterations {
+				cmdResult.Description = fmt.Sprintf("Filled the convex hull, stopped after %d iterations", iterations)
+			}
+
+		case "thicken":
+
+			maxIterations := GetOrDefault(command.Args["maxiter"], 0)
+
+			thickened, iterations := morphological.Thickening(morphological.ConvertIntoBinaryImage(img), nil, maxIterations)
+
+			outputFileName := fmt.Sprintf("%s_thickened.bmp", originalNameWithoutExt)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(thickened), Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Thickened in %d iterations", iterations)
+			if maxIterations > 0 && iterations == maxIterations {
+				cmdResult.Description = fmt.Sprintf("Thickened, stopped after %d iterations", iterations)
+			}
+
+		case "prune":
+
+			length := GetOrDefault(command.Args["length"], 3)
+			if length < 1 {
+				log.Fatalf("Length must be a positive number")
+			}
+
+			pruned := morphological.PruneHitOrMiss(morphological.ConvertIntoBinaryImage(img), length)
+
+			outputFileName := fmt.Sprintf("%s_pruned_%d.bmp", originalNameWithoutExt, length)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(pruned), Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Pruned spurs of at most %d pixels", length)
 
 		case "region-grow":
 
-			seeds, err := morphological.ParseSeedPoints(command.Args["seeds"])
+			seeds := getSeeds(command, img, "", 16)
 
+			reference, err := morphological.ParseGrowthReference(command.Args["reference"])
 			if err != nil {
-				log.Fatalf("Error parsing seed points: %v", err)
+				log.Fatalf("Invalid reference argument: %v", err)
 			}
 
 			distanceMetric := morphological.DistanceCriterion(GetOrDefault(command.Args["metric"], 0))
@@ -512,12 +953,209 @@ func RunAsCliApp() {
 				log.Fatalf("Threshold must be a positive number")
 			}
 
-			outputFileName := fmt.Sprintf("%s_region_growing_threshold_%v_method_%v.bmp", originalNameWithoutExt, threshold, distanceMetric)
+			connectivity := getConnectivity(command)
+
+			segmented, newImg, err := morphological.GrowRegions(img, seeds, morphological.RegionGrowingOptions{
+				Criterion:    distanceMetric,
+				Threshold:    threshold,
+				Reference:    reference,
+				Deviations:   GetOrDefault(command.Args["deviations"], 2.0),
+				Connectivity: connectivity,
+			})
+			if err != nil {
+				log.Fatalf("Error growing regions: %v", err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_region_growing_threshold_%v_method_%v", originalNameWithoutExt, threshold, distanceMetric)
+			if reference != morphological.ReferenceSeed {
+				outputFileName += "_reference_" + string(reference)
+			}
+			if connectivity != morphological.Connectivity8 {
+				outputFileName += fmt.Sprintf("_c%d", connectivity)
+			}
 
-			_, newImg := morphological.RegionGrowing(img, seeds, distanceMetric, threshold)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName + ".bmp"})
 
+			regions := morphological.RegionCount(segmented)
+
+			cmdResult.Result = fmt.Sprintf("Regions: %d", regions)
+			cmdResult.Description = fmt.Sprintf("Grew %d regions from %d seeds covering %.1f%% of the image", regions, len(seeds), 100*coveredFraction(segmented))
+
+		case "region-merge":
+
+			seeds := getSeeds(command, img, morphological.SeedGrid, 256)
+
+			distanceMetric := morphological.DistanceCriterion(GetOrDefault(command.Args["metric"], 0))
+			threshold := GetOrDefault(command.Args["threshold"], 20.0)
+			mergeThreshold := GetOrDefault(command.Args["mergethreshold"], threshold)
+
+			if threshold < 0 || mergeThreshold < 0 {
+				log.Fatalf("Thresholds must be positive numbers")
+			}
+
+			connectivity := getConnectivity(command)
+
+			grown, _, err := morphological.GrowRegions(img, seeds, morphological.RegionGrowingOptions{
+				Criterion:    distanceMetric,
+				Threshold:    threshold,
+				Reference:    morphological.ReferenceMean,
+				Connectivity: connectivity,
+			})
+			if err != nil {
+				log.Fatalf("Error growing regions: %v", err)
+			}
+
+			merged, newImg, err := morphological.MergeRegions(img, grown, morphological.MergeOptions{
+				Criterion:    distanceMetric,
+				Threshold:    mergeThreshold,
+				MinArea:      GetOrDefault(command.Args["minarea"], 0),
+				Connectivity: connectivity,
+			})
+			if err != nil {
+				log.Fatalf("Error merging regions: %v", err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_region_merging_threshold_%v_method_%v.bmp", originalNameWithoutExt, mergeThreshold, distanceMetric)
 			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
 
+			regions := morphological.RegionCount(merged)
+
+			cmdResult.Result = fmt.Sprintf("Regions: %d", regions)
+			cmdResult.Description = fmt.Sprintf("Merged %d grown regions into %d covering %.1f%% of the image", morphological.RegionCount(grown), regions, 100*coveredFraction(merged))
+
+		case "split-merge":
+
+			predicate, err := morphological.ParseHomogeneityPredicate(command.Args["predicate"])
+			if err != nil {
+				log.Fatalf("Invalid predicate argument: %v", err)
+			}
+
+			threshold := GetOrDefault(command.Args["threshold"], 10.0)
+			if threshold < 0 {
+				log.Fatalf("Threshold must be a positive number")
+			}
+
+			labels, newImg, err := morphological.SplitAndMerge(img, morphological.SplitMergeOptions{
+				Criterion:    morphological.DistanceCriterion(GetOrDefault(command.Args["metric"], 0)),
+				Predicate:    predicate,
+				Threshold:    threshold,
+				MinSize:      GetOrDefault(command.Args["minsize"], 2),
+				MinArea:      GetOrDefault(command.Args["minarea"], 0),
+				Connectivity: getConnectivity(command),
+			})
+			if err != nil {
+				log.Fatalf("Error in split and merge: %v", err)
+			}
+
+			outputFileName := fmt.Sprintf("%s_split_merge_%s_%v.bmp", originalNameWithoutExt, predicate, threshold)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: newImg, Filename: outputFileName})
+
+			cmdResult.Result = fmt.Sprintf("Regions: %d", morphological.RegionCount(labels))
+			cmdResult.Description = fmt.Sprintf("Split into homogeneous quadtree blocks by %s at most %v and merged them", predicate, threshold)
+
+		case "shapes":
+
+			labeling := getLabeling(command, morphological.ConvertIntoBinaryImage(img), getConnectivity(command))
+			shapes := morphological.DescribeComponents(labeling)
+
+			var data bytes.Buffer
+			var err error
+
+			format := GetOrDefault(command.Args["export"], "csv")
+			switch format {
+			case "csv":
+				err = morphological.WriteShapesCSV(&data, shapes)
+			case "json":
+				err = morphological.WriteShapesJSON(&data, shapes)
+			default:
+				log.Fatalf("Invalid export argument for %s: %q, expected csv or json", command.Name, format)
+			}
+			if err != nil {
+				log.Fatalf("Error exporting shape descriptors: %v", err)
+			}
+
+			dataQueue = append(dataQueue, DataQueueItem{Data: data.Bytes(), Filename: fmt.Sprintf("%s_shapes.%s", originalNameWithoutExt, format)})
+
+			if GetOrDefault(command.Args["overlay"], 0) == 1 {
+				outputFileName := fmt.Sprintf("%s_shapes_overlay.bmp", originalNameWithoutExt)
+				imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.DrawShapesOverlay(labeling.BinaryImage(), shapes), Filename: outputFileName})
+			}
+
+			cmdResult.Description = fmt.Sprintf("Measured shape descriptors of connected components in %s", originalNameWithoutExt)
+			cmdResult.Result = fmt.Sprintf("Components: %d", len(shapes))
+
+		case "boundary":
+
+			chosenStructureElement := GetOrDefault(command.Args["se"], "iii")
+
+			se, err := morphological.GetStructureElement(chosenStructureElement)
+			if err != nil {
+				log.Fatalf("Error getting structural element: %v", err)
+			}
+
+			boundary := morphological.Boundary(morphological.ConvertIntoBinaryImage(img), se)
+
+			outputFileName := fmt.Sprintf("%s_boundary_se_%s.bmp", originalNameWithoutExt, morphological.StructureElementLabel(chosenStructureElement))
+			imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.ConvertIntoImage(boundary), Filename: outputFileName})
+
+		case "contours":
+
+			method, err := morphological.ParseContourMethod(command.Args["method"])
+			if err != nil {
+				log.Fatalf("Invalid method argument for %s: %v", command.Name, err)
+			}
+
+			epsilon := GetOrDefault(command.Args["epsilon"], 0.0)
+			binaryImg := morphological.ConvertIntoBinaryImage(img)
+
+			contours, err := morphological.TraceContours(binaryImg, method)
+			if err != nil {
+				log.Fatalf("Error tracing contours: %v", err)
+			}
+
+			var data bytes.Buffer
+
+			format := GetOrDefault(command.Args["export"], "json")
+			switch format {
+			case "json":
+				err = morphological.WriteContoursJSON(&data, contours, epsilon)
+			case "svg":
+				err = morphological.WriteContoursSVG(&data, contours, img.Bounds().Dx(), img.Bounds().Dy(), epsilon)
+			default:
+				log.Fatalf("Invalid export argument for %s: %q, expected json or svg", command.Name, format)
+			}
+			if err != nil {
+				log.Fatalf("Error exporting contours: %v", err)
+			}
+
+			dataQueue = append(dataQueue, DataQueueItem{Data: data.Bytes(), Filename: fmt.Sprintf("%s_contours_%s.%s", originalNameWithoutExt, method, format)})
+
+			if GetOrDefault(command.Args["overlay"], 0) == 1 {
+				outputFileName := fmt.Sprintf("%s_contours_%s_overlay.bmp", originalNameWithoutExt, method)
+				imageQueue = append(imageQueue, ImageQueueItem{Image: morphological.DrawContoursOverlay(binaryImg, contours, epsilon), Filename: outputFileName})
+			}
+
+			holes := 0
+			for _, c := range contours {
+				if c.Hole {
+					holes++
+				}
+			}
+
+			cmdResult.Description = fmt.Sprintf("Traced %d contours with %d holes in %s using %s", len(contours), holes, originalNameWithoutExt, method)
+			cmdResult.Result = fmt.Sprintf("Contours: %d", len(contours))
+
+		case "label":
+
+			connectivity := getConnectivity(command)
+			labeling := getLabeling(command, morphological.ConvertIntoBinaryImage(img), connectivity)
+
+			outputFileName := fmt.Sprintf("%s_labeled_c%d.bmp", originalNameWithoutExt, connectivity)
+			imageQueue = append(imageQueue, ImageQueueItem{Image: labeling.Colorize(), Filename: outputFileName})
+
+			cmdResult.Description = fmt.Sprintf("Labeled %d-connected components in %s", connectivity, originalNameWithoutExt)
+			cmdResult.Result = fmt.Sprintf("Components: %d", len(labeling.Components))
+
 		case "bandpass":
 
 			lowCut := GetOrDefault(command.Args["low"], 15)
@@ -613,6 +1251,15 @@ func RunAsCliApp() {
 		}
 	}
 
+	for _, dataItem := range dataQueue {
+		err = imageio.SaveDataFile(dataItem.Data, dataItem.Filename)
+		if err != nil {
+			log.Fatalf("\nError saving file: %v", err)
+		} else {
+			fmt.Printf("\nData saved successfully as: %s\n", dataItem.Filename)
+		}
+	}
+
 	fmt.Println("Execution Report:")
 	for _, result := range commandResults {
 		fmt.Printf("Command: %s\n", result.Name)
@@ -620,6 +1267,9 @@ func RunAsCliApp() {
 		if result.Result != "" {
 			fmt.Printf("Result: %s\n", result.Result)
 		}
+		if result.Details != "" {
+			fmt.Print(result.Details)
+		}
 		fmt.Printf("Duration: %v\n\n", result.Duration)
 	}
 
@@ -634,3 +1284,202 @@ func getLastDenoisedImage(queue []ImageQueueItem) *image.RGBA {
 	}
 	return nil
 }
+
+func getHistogramChannels(command Command) []manipulations.HistogramChannel {
+	channels, err := manipulations.ParseHistogramChannels(command.Args["channel"])
+	if err != nil {
+		log.Fatalf("Invalid channel argument for %s: %v", command.Name, err)
+	}
+	return channels
+}
+
+func getHistogramPlotOptions(command Command) manipulations.HistogramPlotOptions {
+	scale, err := manipulations.ParseHistogramScale(command.Args["scale"])
+	if err != nil {
+		log.Fatalf("Invalid scale argument for %s: %v", command.Name, err)
+	}
+
+	markers, err := manipulations.ParseHistogramMarkers(command.Args["markers"])
+	if err != nil {
+		log.Fatalf("Invalid markers argument for %s: %v", command.Name, err)
+	}
+
+	options := manipulations.HistogramPlotOptions{
+		Width:      GetOrDefault(command.Args["width"], manipulations.DefaultHistogramPlotOptions.Width),
+		Height:     GetOrDefault(command.Args["height"], manipulations.DefaultHistogramPlotOptions.Height),
+		Scale:      scale,
+		Cumulative: command.Args["cdf"] == "1",
+		Markers:    markers,
+	}
+
+	if err := options.Validate(); err != nil {
+		log.Fatalf("Invalid plot size for %s: %v", command.Name, err)
+	}
+
+	return options
+}
+
+// getHistogramDataFileName names exported histograms like the plot of a single channel, or "_all" for several channels.
+func getHistogramDataFileName(nameWithoutExt string, channels []manipulations.HistogramChannel, format analysis.HistogramExportFormat) string {
+	if len(channels) == 1 {
+		return strings.TrimSuffix(getHistogramFileName(nameWithoutExt, channels[0]), ".bmp") + "." + string(format)
+	}
+	return fmt.Sprintf("%s_histogram_all.%s", nameWithoutExt, format)
+}
+
+// getHistogramFileName keeps the original name for the HSV value histogram and suffixes other channels.
+func getHistogramFileName(nameWithoutExt string, channel manipulations.HistogramChannel) string {
+	if channel == manipulations.HistogramValue {
+		return fmt.Sprintf("%s_histogram.bmp", nameWithoutExt)
+	}
+	return fmt.Sprintf("%s_histogram_%s.bmp", nameWithoutExt, channel)
+}
+
+// getLabeling labels the components of the binary image and drops the ones outside of -minarea and -maxarea.
+func getLabeling(command Command, binaryImg morphological.BinaryImage, connectivity morphological.Connectivity) morphological.Labeling {
+	minArea := GetOrDefault(command.Args["minarea"], 0)
+	maxArea := GetOrDefault(command.Args["maxarea"], 0)
+	if maxArea > 0 && maxArea < minArea {
+		log.Fatalf("Invalid area range for %s: maxarea %d is smaller than minarea %d", command.Name, maxArea, minArea)
+	}
+
+	labeling := morphological.LabelComponents(binaryImg, connectivity)
+	if minArea > 0 || maxArea > 0 {
+		labeling = labeling.FilterByArea(minArea, maxArea)
+	}
+
+	return labeling
+}
+
+func getConnectivity(command Command) morphological.Connectivity {
+	connectivity, err := morphological.ParseConnectivity(command.Args["connectivity"])
+	if err != nil {
+		log.Fatalf("Invalid connectivity argument for %s: %v", command.Name, err)
+	}
+	return connectivity
+}
+
+// getSeeds collects the region growing seeds of -seeds, -seedfile and -autoseed, the default strategy is used
+// when none of them is given and an empty one makes the seeds required.
+func getSeeds(command Command, img image.Image, defaultStrategy morphological.SeedStrategy, defaultCount int) []morphological.Point {
+	var seeds []morphological.Point
+
+	if command.Args["seeds"] != "" {
+		points, err := morphological.ParseSeedPoints(command.Args["seeds"])
+		if err != nil {
+			log.Fatalf("Error parsing seed points: %v", err)
+		}
+		seeds = append(seeds, points...)
+	}
+
+	if command.Args["seedfile"] != "" {
+		points, err := morphological.LoadSeedPoints(command.Args["seedfile"])
+		if err != nil {
+			log.Fatalf("Error loading seed points: %v", err)
+		}
+		seeds = append(seeds, points...)
+	}
+
+	strategy := defaultStrategy
+	if command.Args["autoseed"] != "" {
+		var err error
+		if strategy, err = morphological.ParseSeedStrategy(command.Args["autoseed"]); err != nil {
+			log.Fatalf("Invalid autoseed argument: %v", err)
+		}
+	} else if len(seeds) > 0 {
+		strategy = ""
+	}
+
+	if strategy != "" {
+		points, err := morphological.GenerateSeeds(img, morphological.SeedingOptions{
+			Strategy:   strategy,
+			Count:      GetOrDefault(command.Args["seedcount"], defaultCount),
+			RandomSeed: int64(GetOrDefault(command.Args["randomseed"], 1)),
+		})
+		if err != nil {
+			log.Fatalf("Error generating seed points: %v", err)
+		}
+		seeds = append(seeds, points...)
+	}
+
+	if len(seeds) == 0 {
+		log.Fatalf("%s needs seed points, pass -seeds, -seedfile or -autoseed", command.Name)
+	}
+	return seeds
+}
+
+// coveredFraction is the fraction of pixels belonging to a region.
+func coveredFraction(labels [][]int) float64 {
+	covered, total := 0, 0
+	for y := range labels {
+		for _, label := range labels[y] {
+			if label >= 0 {
+				covered++
+			}
+			total++
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(covered) / float64(total)
+}
+
+func getMorphologyMode(command Command) morphological.Mode {
+	mode, err := morphological.ParseMode(command.Args["mode"])
+	if err != nil {
+		log.Fatalf("Invalid mode argument for %s: %v", command.Name, err)
+	}
+	return mode
+}
+
+func getGLCMOptions(command Command) analysis.GLCMOptions {
+	opts := analysis.DefaultGLCMOptions
+	opts.Distance = GetOrDefault(command.Args["distance"], opts.Distance)
+	opts.Levels = GetOrDefault(command.Args["levels"], opts.Levels)
+	opts.Symmetric = GetOrDefault(command.Args["symmetric"], 1) == 1
+
+	if anglesArg, ok := command.Args["angles"]; ok {
+		angles, err := analysis.ParseGLCMAngles(anglesArg)
+		if err != nil {
+			log.Fatalf("Invalid angles argument for %s: %v", command.Name, err)
+		}
+		opts.Angles = angles
+	}
+
+	if offsetsArg, ok := command.Args["offsets"]; ok {
+		offsets, err := analysis.ParseGLCMOffsets(offsetsArg)
+		if err != nil {
+			log.Fatalf("Invalid offsets argument for %s: %v", command.Name, err)
+		}
+		opts.Offsets = offsets
+	}
+
+	return opts
+}
+
+func getComparisonOptions(command Command) analysis.ComparisonOptions {
+	alignment, err := analysis.ParseAlignmentMode(command.Args["align"])
+	if err != nil {
+		log.Fatalf("Invalid align argument for %s: %v", command.Name, err)
+	}
+
+	return analysis.ComparisonOptions{
+		SSIM: analysis.SSIMOptions{
+			WindowSize: GetOrDefault(command.Args["window"], analysis.DefaultSSIMOptions.WindowSize),
+			Sigma:      GetOrDefault(command.Args["sigma"], analysis.DefaultSSIMOptions.Sigma),
+			K1:         GetOrDefault(command.Args["k1"], analysis.DefaultSSIMOptions.K1),
+			K2:         GetOrDefault(command.Args["k2"], analysis.DefaultSSIMOptions.K2),
+		},
+		PerChannel: command.Args["channels"] == "1",
+		Alignment:  alignment,
+	}
+}
+
+func getBorderPolicy(command Command) manipulations.BorderPolicy {
+	border, err := manipulations.ParseBorderPolicy(command.Args["border"])
+	if err != nil {
+		log.Fatalf("Invalid border argument for %s: %v", command.Name, err)
+	}
+	return border
+}
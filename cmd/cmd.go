@@ -69,39 +69,127 @@ var AvailableCommands = []CommandInfo{
 	{"dflip", "--dflip <bmp_image_path>", "Flip the image diagonally.", []string{}},
 	{"shrink", "--shrink -value=2 <bmp_image_path>", "Shrink the image by a factor.", []string{"-value=(int): Shrink factor."}},
 	{"enlarge", "--enlarge -value=2 <bmp_image_path>", "Enlarge the image by a factor.", []string{"-value=(int): Enlarge factor."}},
-	{"adaptive", "--adaptive <bmp_image_path>", "Apply adaptive median noise removal filter to the image.", []string{"-min=(int): Minimal size of window size for filter defaults, to 3.", "-max=(int): Maximal size of window size for filter defaults, to 7."}},
-	{"min", "--min -value=3 <bmp_image_path>", "Apply min noise removal filter.", []string{"-value=(int): Window size."}},
-	{"max", "--max -value=3 <bmp_image_path>", "Apply max noise removal filter.", []string{"-value=(int): Window size."}},
-	{"mse", "--mse <comparison_image_path> <bmp_image_path>", "Calculate Mean Square Error with a comparison image.", []string{}},
-	{"pmse", "--pmse <comparison_image_path> <bmp_image_path>", "Calculate Peak Mean Square Error with a comparison image.", []string{}},
-	{"snr", "--snr <comparison_image_path> <bmp_image_path>", "Calculate Signal to Noise Ratio with a comparison image.", []string{}},
-	{"psnr", "--psnr <comparison_image_path> <bmp_image_path>", "Calculate Peak Signal to Noise Ratio with a comparison image.", []string{}},
-	{"md", "--md <comparison_image_path> <bmp_image_path>", "Calculate Max Difference with a comparison image.", []string{}},
-	{"histogram", "--histogram <bmp_image_path>", "Generate and save a graphical representation of the histogram of the image.", []string{}},
+	{"adaptive", "--adaptive <bmp_image_path>", "Apply adaptive median noise removal filter to the image.", []string{"-min=(int): Minimal size of window size for filter defaults, to 3.", "-max=(int): Maximal size of window size for filter defaults, to 7.", "-stats=(int): Report replaced pixel counts and final window sizes (0 or 1).", "-diagnostics=(int): Also save a window size heat-map and a mask of modified pixels (0 or 1).", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"min", "--min -value=3 <bmp_image_path>", "Apply min noise removal filter.", []string{"-value=(int): Window size.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"max", "--max -value=3 <bmp_image_path>", "Apply max noise removal filter.", []string{"-value=(int): Window size.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"mse", "--mse <comparison_image_path> <bmp_image_path>", "Calculate Mean Square Error with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"pmse", "--pmse <comparison_image_path> <bmp_image_path>", "Calculate Peak Mean Square Error with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"snr", "--snr <comparison_image_path> <bmp_image_path>", "Calculate Signal to Noise Ratio with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"psnr", "--psnr <comparison_image_path> <bmp_image_path>", "Calculate Peak Signal to Noise Ratio with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"md", "--md <comparison_image_path> <bmp_image_path>", "Calculate Max Difference with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"ssim", "--ssim <comparison_image_path> <bmp_image_path>", "Calculate Structural Similarity Index with a comparison image.", []string{"-window=(int): Side length of the gaussian window, defaults to 11.", "-sigma=(float): Standard deviation of the window, negative for a uniform window, defaults to 1.5.", "-k1=(float): Luminance stabilizing constant, defaults to 0.01.", "-k2=(float): Contrast stabilizing constant, defaults to 0.03.", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"ms-ssim", "--ms-ssim <comparison_image_path> <bmp_image_path>", "Calculate Multi-Scale Structural Similarity Index with a comparison image.", []string{"-window=(int): Side length of the gaussian window, defaults to 11.", "-sigma=(float): Standard deviation of the window, negative for a uniform window, defaults to 1.5.", "-k1=(float): Luminance stabilizing constant, defaults to 0.01.", "-k2=(float): Contrast stabilizing constant, defaults to 0.03.", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"psnr-rgb", "--psnr-rgb <comparison_image_path> <bmp_image_path>", "Calculate Peak Signal to Noise Ratio of every color channel with a comparison image.", []string{"-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"mae", "--mae <comparison_image_path> <bmp_image_path>", "Calculate Mean Absolute Error with a comparison image.", []string{"-channels=(int): Also report the value of every color channel (0 or 1).", "-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"ncc", "--ncc <comparison_image_path> <bmp_image_path>", "Calculate Normalized Cross-Correlation with a comparison image.", []string{"-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"ciede2000", "--ciede2000 <comparison_image_path> <bmp_image_path>", "Calculate mean CIEDE2000 color difference with a comparison image.", []string{"-diff=(int): Save an absolute difference image and a difference heat-map (0 or 1).", "-align=(string): Match images of different size or shifted content (none, overlap, resize, translate), defaults to none."}},
+	{"histogram", "--histogram [comparison_image_path] <bmp_image_path>", "Generate and save a graphical representation of the histogram of the image.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value). With all an overlaid RGB plot is saved too.", "-width=(int): Plot width, defaults to 500.", "-height=(int): Plot height, defaults to 500.", "-scale=(string): Y-axis scale (linear or log), defaults to linear.", "-cdf=(int): Overlay the cumulative distribution (0 or 1).", "-markers=(string): Comma separated markers to draw (mean, median, pN e.g. p5,p95).", "-compare=(int): Also plot the histogram of the comparison image against this one (0 or 1). Combined with --hrayleigh a before/after plot is always saved.", "-export=(string): Also write the raw bins and summary statistics of the plotted channels to a data file (csv or json)."}},
+	{"stats", "--stats <bmp_image_path>", "Describe the image: dimensions, color model, per channel distribution, unique colors, grayscale or binary content and saturated pixels.", []string{"-format=(string): Output format (text or json), defaults to text. JSON is saved next to the images.", "-percentiles=(string): Comma separated percentiles to report, defaults to 1,5,25,75,95,99.", "-tolerance=(int): Largest channel spread of a pixel that still counts as gray, defaults to 0."}},
+	{"glcm", "--glcm -distance=1 -angles=0,45,90,135 -levels=8 <bmp_image_path>", "Calculate Haralick texture features (contrast, correlation, energy, homogeneity, entropy) from gray-level co-occurrence matrices of the image luma, averaged over all offsets.", []string{"-distance=(int): Neighbour distance in pixels, defaults to 1.", "-angles=(string): Comma separated angles (0, 45, 90, 135), defaults to all four.", "-offsets=(string): Comma separated dx:dy offsets used instead of distance and angles, e.g. 1:0,0:-2.", "-levels=(int): Number of gray levels luma is quantized to (2 to 256), defaults to 8.", "-symmetric=(int): Count every pair in both directions (0 or 1), defaults to 1."}},
 	{"hrayleigh", "--hrayleigh -min=0 -max=255 -alpha=\"0.2\" <bmp_image_path>", "Apply Rayleigh transformation to the image.", []string{"-min=(int): Minimum value in the range [0, 255].", "-max=(int): Maximum value in the range [0, 255], must be greater than min.", "-alpha=(float): Alpha value for transformation. Note: Quote float values (e.g., -alpha=\"0.5\")."}},
-	{"cmean", "--cmean <bmp_image_path>", "Calculate the mean intensity from the histogram of the image.", []string{}},
-	{"cvariance", "--cvariance <bmp_image_path>", "Calculate the variance intensity from the histogram of the image.", []string{}},
-	{"cstdev", "--cstdev <bmp_image_path>", "Calculate the standard deviation from the histogram of the image.", []string{}},
-	{"cvarcoi", "--cvarcoi <bmp_image_path>", "Calculate the coefficient of variation (type I) from the histogram.", []string{}},
-	{"casyco", "--casyco <bmp_image_path>", "Calculate the asymmetry coefficient from the histogram.", []string{}},
-	{"cflatco", "--cflatco <bmp_image_path>", "Calculate the flattening coefficient from the histogram.", []string{}},
-	{"cvarcoii", "--cvarcoii <bmp_image_path>", "Calculate the coefficient of variation (type II) from the histogram.", []string{}},
-	{"centropy", "--centropy <bmp_image_path>", "Calculate the entropy from the histogram of the image.", []string{}},
-	{"sedgesharp", "--sedgesharp -mask=\"edge1\" <bmp_image_path>", "Apply edge sharpening with the specified mask.", []string{"-mask=(string): The name of the mask to use."}},
-	{"okirsf", "--okirsf <bmp_image_path>", "Apply Kirsch edge detection to the image.", []string{}},
-	{"dilation", "--dilation -se=<structuring_element> <bmp_image_path>", "Apply dilation operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json."}},
-	{"erosion", "--erosion -se=<structuring_element> <bmp_image_path>", "Apply erosion operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json."}},
-	{"opening", "--opening -se=<structuring_element> <bmp_image_path>", "Apply opening operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json."}},
-	{"closing", "--closing -se=<structuring_element> <bmp_image_path>", "Apply closing operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json."}},
+	{"cmean", "--cmean <bmp_image_path>", "Calculate the mean intensity from the histogram of the image.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"cvariance", "--cvariance <bmp_image_path>", "Calculate the variance intensity from the histogram of the image.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"cstdev", "--cstdev <bmp_image_path>", "Calculate the standard deviation from the histogram of the image.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"cvarcoi", "--cvarcoi <bmp_image_path>", "Calculate the coefficient of variation (type I) from the histogram.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"casyco", "--casyco <bmp_image_path>", "Calculate the asymmetry coefficient from the histogram.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"cflatco", "--cflatco <bmp_image_path>", "Calculate the flattening coefficient from the histogram.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"cvarcoii", "--cvarcoii <bmp_image_path>", "Calculate the coefficient of variation (type II) from the histogram.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"centropy", "--centropy <bmp_image_path>", "Calculate the entropy from the histogram of the image.", []string{"-channel=(string): Channel to use (r, g, b, v, luma or all), defaults to v (HSV value)."}},
+	{"sedgesharp", "--sedgesharp -mask=\"edge1\" <bmp_image_path>", "Apply edge sharpening with the specified mask.", []string{"-mask=(string): The name of the mask to use.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"okirsf", "--okirsf <bmp_image_path>", "Apply Kirsch edge detection to the image.", []string{"-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"dilation", "--dilation -se=<structuring_element> <bmp_image_path>", "Apply dilation operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"erosion", "--erosion -se=<structuring_element> <bmp_image_path>", "Apply erosion operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"opening", "--opening -se=<structuring_element> <bmp_image_path>", "Apply opening operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"closing", "--closing -se=<structuring_element> <bmp_image_path>", "Apply closing operation using the specified structuring element.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"gradient", "--gradient -se=<structuring_element> <bmp_image_path>", "Compute the morphological gradient, the dilation minus the erosion.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"tophat", "--tophat -se=<structuring_element> <bmp_image_path>", "Compute the white top-hat, the image minus its opening, keeping bright details smaller than the SE.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"blackhat", "--blackhat -se=<structuring_element> <bmp_image_path>", "Compute the black top-hat, the closing minus the image, keeping dark details smaller than the SE.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iv.", "-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary.", "-iterations=(int): Repeat every dilation and erosion of the operation this many times, defaults to 1.", "-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip."}},
+	{"asf", "--asf -se=<structuring_element> -size=<n> <bmp_image_path>", "Apply the alternating sequential filter, openings and closings with the SE applied 1 to size times.", []string{"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, defaults to iii.", "-size=(int): Largest number of SE iterations, defaults to 2.", "-order=(string): oc opens before closing at every size, co closes first, defaults to oc.", "-mode=(string): binary binarizes the image first, gray works on the intensities of every RGB channel, defaults to binary."}},
+	{"morph", "--morph -expr=\"close(disk3) | open(iv) - erode(iii)\" <bmp_image_path>", "Evaluate a morphology expression on the binarized image.", []string{"-expr=(string): Operations dilate, erode, open, close, gradient, tophat, blackhat and asf written as name(se[, iterations][, expr]) combined with | (union), & (intersection), - (difference), ^ (xor) and ~ (complement), x is the image."}},
+	{"reconstruct", "--reconstruct -method=<dilation|erosion> <marker_image_path> <bmp_image_path>", "Reconstruct the image from the marker image, repeating geodesic dilation or erosion until stability.", []string{"-method=(string): dilation grows the marker under the image, erosion shrinks it above the image, defaults to dilation.", "-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8.", "-mode=(string): binary binarizes the images first, gray works on the intensities of every RGB channel, defaults to binary."}},
+	{"geodesic", "--geodesic -method=<dilation|erosion> -steps=<n> <marker_image_path> <bmp_image_path>", "Apply a fixed number of geodesic dilations or erosions of the marker image constrained by the image.", []string{"-method=(string): dilation or erosion, defaults to dilation.", "-steps=(int): Number of elementary geodesic steps, defaults to 1.", "-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8.", "-mode=(string): binary binarizes the images first, gray works on the intensities of every RGB channel, defaults to binary."}},
+	{"fillholes", "--fillholes [<marker_image_path>] <bmp_image_path>", "Fill the holes of the image, dark regions not connected to the border, or to the marker image when given.", []string{"-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8.", "-mode=(string): binary binarizes the images first, gray works on the intensities of every RGB channel, defaults to binary."}},
+	{"clearborder", "--clearborder [<marker_image_path>] <bmp_image_path>", "Remove the objects touching the image border, or the marker image when given.", []string{"-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8.", "-mode=(string): binary binarizes the images first, gray works on the intensities of every RGB channel, defaults to binary."}},
+	{"hmaxima", "--hmaxima -h=<height> <bmp_image_path>", "Suppress the regional maxima not higher than h above their surroundings, in every RGB channel.", []string{"-h=(int): Height from 0 to 255, defaults to 10.", "-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8."}},
+	{"hminima", "--hminima -h=<depth> <bmp_image_path>", "Suppress the regional minima not deeper than h below their surroundings, in every RGB channel.", []string{"-h=(int): Depth from 0 to 255, defaults to 10.", "-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8."}},
+	{"rmaxima", "--rmaxima <bmp_image_path>", "Mark the regional maxima of the image luma as a binary image.", []string{"-connectivity=(int): Pixel connectivity, 4 or 8, defaults to 8."}},
+	{"distance", "--distance -metric=<metric> <bmp_image_path>", "Calculate the distance of every foreground pixel to the background and save it scaled to the full gray range.", []string{"-metric=(string): Distance metric (euclidean, cityblock or chessboard), defaults to euclidean."}},
 	{"hmt", "--hmt -se1=<foreground_se> -se2=<background_se> <bmp_image_path>", "Perform hit-or-miss transformation using foreground and background structuring elements.", []string{
-		"-se1=(string): Path to or inline definition of the foreground structuring element.",
-		"-se2=(string): Path to or inline definition of the background structuring element.",
+		"-se1=(string): Name, shape, path to a JSON file or inline matrix (e.g. '1,1,1;x,1,x;0,0,0', rows separated by ; or /) of the foreground structuring element, defaults to xi-l.",
+		"-se2=(string): Name, shape, path to a JSON file or inline matrix of the background structuring element, defaults to xi-c.",
+		"-template=(string): Inline matrix replacing se1 and se2, 1 has to be foreground, 0 background and x does not matter.",
+		"-rotations=(int): Also match the elements turned in steps of 90 (4) or 45 (8) degrees, defaults to 1.",
+		"-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.",
+		"-border=(string): Border handling (skip, clamp, reflect, wrap, constant[:value]), defaults to skip.",
+	}},
+	{"thinning", "--thinning <bmp_image_path>", "Apply thinning operation to the image.", []string{
+		"-method=(string): Thinning algorithm (hmt, zhang-suen, guo-hall or medial-axis), defaults to hmt. medial-axis also saves the skeleton shaded by the distance to the background.",
+		"-se=(string): Structuring elements series to use with hmt (xi or xii), defaults to xii.",
+		"-border=(string): Border handling with hmt (skip, clamp, reflect, wrap, constant[:value]), defaults to skip.",
+		"-maxiter=(int): Stop after this many iterations, defaults to 0 (until no pixel changes).",
+		"-prune=(int): Remove spurs of at most this many pixels, defaults to 0 (no pruning).",
+		"-points=(int): Save the end and branch points as JSON and as an overlay image (0 or 1).",
+	}},
+	{"hull", "--hull -components=1 -maxiter=0 <bmp_image_path>", "Fill the convex hull of the binarized objects by iterating hit-or-miss with the xi series, limited to the bounding box of every object.", []string{
+		"-components=(int): 1 fills the hull of every connected component on its own, 0 of all of the foreground as one set, defaults to 1.",
+		"-connectivity=(int): Neighbourhood joining pixels into components (4 or 8), defaults to 8.",
+		"-maxiter=(int): Stop every element of the series after this many iterations, defaults to 0 (until no pixel changes).",
+	}},
+	{"thicken", "--thicken -maxiter=10 <bmp_image_path>", "Thicken the binarized image with the xii series with foreground and background swapped.", []string{
+		"-maxiter=(int): Stop after this many passes over the series, defaults to 0 (until no pixel changes).",
+	}},
+	{"prune", "--prune -length=3 <bmp_image_path>", "Remove spurs from a skeleton: thin away the end points length times, then grow the remaining end points back along the skeleton.", []string{
+		"-length=(int): Longest spur to remove in pixels, it is also the number of thinning and growing iterations, defaults to 3.",
 	}},
-	{"thinning", "--thinning <bmp_image_path>", "Apply thinning operation to the image.", []string{}},
 	{"region-grow", "--region-grow -seeds=<seeds> -metric=<metric> -threshold=<value> <bmp_image_path>", "Perform region growing segmentation on the image.", []string{
 		"-seeds=(string): List of seed points as [x,y][x,y][x,y].",
+		"-seedfile=(string): File with seed points, a JSON array of {\"x\":..,\"y\":..} objects or [x,y] pairs, or [x,y] text.",
+		"-autoseed=(string): Place seeds automatically: grid, extrema (regional maxima and minima), histogram (histogram peaks) or random.",
+		"-seedcount=(int): Number of automatic seeds, extrema and histogram place at most this many, defaults to 16.",
+		"-randomseed=(int): Seed of the random generator for -autoseed=random, defaults to 1.",
 		"-metric=(int): Distance metric ('0 - Euclidean', '1 - Manhattan', '2 - Chebyshev').",
 		"-threshold=(double): Similarity threshold for region growing.",
+		"-reference=(string): Compare neighbours to the seed value (seed), the running region mean (mean) or the mean with the threshold widened by the region deviation (stats), defaults to seed.",
+		"-deviations=(double): Standard deviations added to the threshold with -reference=stats, defaults to 2.",
+		"-connectivity=(int): Neighbourhood the regions grow through (4 or 8), defaults to 8.",
+	}},
+	{"region-merge", "--region-merge -threshold=15 -mergethreshold=25 -minarea=50 <bmp_image_path>", "Grow regions from seeds and merge the adjacent ones with similar means through a region adjacency graph.", []string{
+		"-seeds=(string), -seedfile=(string), -autoseed=(string), -seedcount=(int), -randomseed=(int): Seeds as for --region-grow, defaults to a grid of 256 seeds.",
+		"-metric=(int): Distance metric ('0 - Euclidean', '1 - Manhattan', '2 - Chebyshev').",
+		"-threshold=(double): Similarity threshold for growing the regions by their running mean, defaults to 20.",
+		"-mergethreshold=(double): Largest distance between the means of merged regions, defaults to the threshold.",
+		"-minarea=(int): Merge smaller regions into their most similar neighbour, defaults to 0.",
+		"-connectivity=(int): Neighbourhood of the regions (4 or 8), defaults to 8.",
+	}},
+	{"split-merge", "--split-merge -predicate=deviation -threshold=10 -minsize=2 <bmp_image_path>", "Split the image into homogeneous quadtree blocks and merge the adjacent regions whose union stays homogeneous.", []string{
+		"-predicate=(string): Homogeneity of a region, its standard deviation (deviation) or its value range (range) at most the threshold, defaults to deviation.",
+		"-threshold=(double): Largest deviation or range of a homogeneous region, defaults to 10.",
+		"-minsize=(int): Blocks of at most this side are not split further, defaults to 2.",
+		"-minarea=(int): Merge smaller regions into their most similar neighbour, defaults to 0.",
+		"-metric=(int): Distance metric the deviation and range are measured with ('0 - Euclidean', '1 - Manhattan', '2 - Chebyshev').",
+		"-connectivity=(int): Neighbourhood of the regions (4 or 8), defaults to 8.",
+	}},
+	{"label", "--label -connectivity=8 -minarea=10 <bmp_image_path>", "Label the connected components of the binary image and save them in distinct colors.", []string{
+		"-connectivity=(int): Neighbourhood joining pixels into components (4 or 8), defaults to 8.",
+		"-minarea=(int): Drop components with fewer pixels, defaults to 0.",
+		"-maxarea=(int): Drop components with more pixels, defaults to no limit.",
+	}},
+	{"shapes", "--shapes -export=csv -overlay=1 <bmp_image_path>", "Measure every connected component of the binary image: area, perimeter, centroid, bounding box, circularity, eccentricity, orientation and raw, central and Hu moments.", []string{
+		"-export=(string): Format of the table saved next to the images (csv or json), defaults to csv.",
+		"-overlay=(int): Save an image with bounding boxes, major axes, centroids and labels drawn over the components (0 or 1).",
+		"-connectivity=(int): Neighbourhood joining pixels into components (4 or 8), defaults to 8.",
+		"-minarea=(int): Skip components with fewer pixels, defaults to 0.",
+		"-maxarea=(int): Skip components with more pixels, defaults to no limit.",
+	}},
+	{"boundary", "--boundary -se=iii <bmp_image_path>", "Extract the inner boundary of the objects, the binarized image minus its erosion.", []string{
+		"-se=(string): Name of SE based on structure_elements.json or a shape disk:<r>, diamond:<r>, rect:<w>x<h> or line:<length>:<angle>, iii gives a 4-connected and iv an 8-connected boundary, defaults to iii.",
+		"-sefile=(string): JSON file with additional structure elements in the format of structure_elements.json.",
+	}},
+	{"contours", "--contours -method=suzuki -epsilon=1.5 -export=svg <bmp_image_path>", "Trace the contours of the binarized image as ordered point lists with their Freeman chain codes and lengths.", []string{
+		"-method=(string): suzuki follows outer and hole borders and records their nesting, moore follows outer borders only, defaults to suzuki.",
+		"-epsilon=(float): Approximate every contour with a Douglas-Peucker polygon no further than epsilon pixels from it, defaults to 0 keeping every point.",
+		"-export=(string): Format of the contours saved next to the images (json or svg), defaults to json.",
+		"-overlay=(int): Save an image with the contours drawn over the objects, outer borders in red and holes in blue (0 or 1).",
 	}},
 	{"bandpass", "--bandpass -low=15 -high=50 -spectrum=1 <bmp_image_path>", "Apply bandpass filtering to the image.", []string{"-low=(int): Lower cutoff frequency.", "-high=(int): Upper cutoff frequency.", "-spectrum=(int): Include spectrum in output (0 or 1)."}},
 	{"lowpass", "--lowpass -cutoff=15 -spectrum=1 <bmp_image_path>", "Apply lowpass filtering to the image.", []string{"-cutoff=(int): Cutoff frequency.", "-spectrum=(int): Include spectrum in output (0 or 1)."}},
@@ -159,6 +247,16 @@ func GetOrDefault[T int | string | float64](val string, defaultValue T) T {
 	return result.(T)
 }
 
+// Find returns the first command with the given name.
+func (commands Commands) Find(name string) (Command, bool) {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd, true
+		}
+	}
+	return Command{}, false
+}
+
 func (commands Commands) Includes(name string) bool {
 	for _, cmd := range commands {
 		if cmd.Name == name {
@@ -3,7 +3,9 @@ package executioner
 import (
 	"errors"
 	"fmt"
+	"imagio/analysis"
 	"imagio/manipulations"
+	"imagio/morphological"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -32,6 +34,8 @@ var commandRegistry = map[string]CommandExecutionHandler{
 	"img_comparison_commands":       imgComparisonExecutioner,
 	"generate_img_histogram":        generateImgHistogramExecutioner,
 	"histogram_img_characteristics": histogramImgCharacteristicsExecutioner,
+	"img_statistics":                imgStatisticsExecutioner,
+	"texture_features":              textureFeaturesExecutioner,
 	"rayleigh_transform":            rayleighTransformExecutioner,
 	"mask_edge_sharpening":          maskEdgeSharpeningExecutioner,
 	"kirsh_edge_detection":          kirshEdgeDetectionExecutioner,
@@ -39,9 +43,29 @@ var commandRegistry = map[string]CommandExecutionHandler{
 	"erosion":                       erosionExecutioner,
 	"opening":                       openingExecutioner,
 	"closing":                       closingExecutioner,
+	"morphological_gradient":        gradientExecutioner,
+	"white_top_hat":                 whiteTopHatExecutioner,
+	"black_top_hat":                 blackTopHatExecutioner,
+	"alternating_sequential_filter": alternatingSequentialFilterExecutioner,
+	"morphology_expression":         morphologyExpressionExecutioner,
+	"reconstruction":                reconstructionExecutioner,
+	"fill_holes":                    fillHolesExecutioner,
+	"clear_border":                  clearBorderExecutioner,
+	"h_extrema":                     hExtremaExecutioner,
+	"regional_maxima":               regionalMaximaExecutioner,
+	"distance_transform":            distanceTransformExecutioner,
 	"hit_or_miss":                   hitOrMissExecutioner,
 	"thinning":                      thinningExecutioner,
+	"convex_hull":                   convexHullExecutioner,
+	"thickening":                    thickeningExecutioner,
+	"pruning":                       pruningExecutioner,
+	"component_labeling":            componentLabelingExecutioner,
+	"shape_descriptors":             shapeDescriptorsExecutioner,
+	"boundary_extraction":           boundaryExtractionExecutioner,
+	"contour_tracing":               contourTracingExecutioner,
 	"region_grow":                   regionGrowExecutioner,
+	"region_merge":                  regionMergeExecutioner,
+	"split_and_merge":               splitAndMergeExecutioner,
 	"bandpass":                      bandpassExecutioner,
 	"lowpass":                       lowpassExecutioner,
 	"highpass":                      highpassExecutioner,
@@ -222,10 +246,37 @@ func adaptiveNoiseFilterExecutioner(imgPath string, args map[string]string) Exec
 		}
 	}
 
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	withStats, err := parseBoolArg(args, "withStats")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	withDiagnostics, err := parseBoolArg(args, "withDiagnostics")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
-		imgPath:       imgPath,
-		minWindowSize: minWindowSize,
-		maxWindowSize: maxWindowSize,
+		imgPath:         imgPath,
+		minWindowSize:   minWindowSize,
+		maxWindowSize:   maxWindowSize,
+		borderPolicy:    border,
+		withStats:       withStats,
+		withDiagnostics: withDiagnostics,
 	}
 
 	msg, err := handleAdaptiveNoiseFilterCommand(opts)
@@ -245,9 +296,18 @@ func minNoiseFilterExecutioner(imgPath string, args map[string]string) Execution
 		}
 	}
 
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:       imgPath,
 		minWindowSize: minWindowSize,
+		borderPolicy:  border,
 	}
 
 	msg, err := handleMinNoiseFilterCommand(opts)
@@ -267,9 +327,18 @@ func maxNoiseFilterExecutioner(imgPath string, args map[string]string) Execution
 		}
 	}
 
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:       imgPath,
 		maxWindowSize: maxWindowSize,
+		borderPolicy:  border,
 	}
 
 	msg, err := handleMaxNoiseFilterCommand(opts)
@@ -281,10 +350,75 @@ func maxNoiseFilterExecutioner(imgPath string, args map[string]string) Execution
 }
 
 func imgComparisonExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	perChannel, err := parseBoolArg(args, "perChannel")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	withDifferenceImages, err := parseBoolArg(args, "withDifferenceImages")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	alignment, err := parseAlignmentArg(args, "alignment")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	ssimWindowSize, err := parseIntArg(args, "ssimWindowSize")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	ssimSigma, err := parseFloatArg(args, "ssimSigma")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	ssimK1, err := parseFloatArg(args, "ssimK1")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	ssimK2, err := parseFloatArg(args, "ssimK2")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:                    imgPath,
 		comparisonImagePath:        args["comparisonImagePath"],
 		selectedComparisonCommands: args["selectedComparisonCommands"],
+		perChannel:                 perChannel,
+		withDifferenceImages:       withDifferenceImages,
+		alignment:                  alignment,
+		ssimOptions: analysis.SSIMOptions{
+			WindowSize: ssimWindowSize,
+			Sigma:      ssimSigma,
+			K1:         ssimK1,
+			K2:         ssimK2,
+		},
 	}
 
 	msg, output, err := handleImgComparisonCommand(opts)
@@ -297,8 +431,56 @@ func imgComparisonExecutioner(imgPath string, args map[string]string) ExecutionR
 }
 
 func generateImgHistogramExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	channels, err := parseHistogramChannelsArg(args, "histogramChannel")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	scale, err := manipulations.ParseHistogramScale(args["histogramScale"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	withCumulative, err := parseBoolArg(args, "withCumulative")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	markers, err := manipulations.ParseHistogramMarkers(args["histogramMarkers"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	exportFormat, err := parseHistogramExportArg(args, "histogramExport")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	plotOptions := manipulations.DefaultHistogramPlotOptions
+	plotOptions.Scale = scale
+	plotOptions.Cumulative = withCumulative
+	plotOptions.Markers = markers
+
 	opts := handlingCommandOptions{
-		imgPath: imgPath,
+		imgPath:              imgPath,
+		histogramChannels:    channels,
+		histogramPlotOptions: plotOptions,
+		histogramExport:      exportFormat,
 	}
 
 	msg, err := handleImgHistogramCommand(opts)
@@ -310,9 +492,18 @@ func generateImgHistogramExecutioner(imgPath string, args map[string]string) Exe
 }
 
 func histogramImgCharacteristicsExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	channels, err := parseHistogramChannelsArg(args, "histogramChannel")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:                                  imgPath,
 		selectedHistogramCharacteristicsCommands: args["selectedHistogramCharacteristicsCommands"],
+		histogramChannels:                        channels,
 	}
 
 	msg, output, err := handleHistogramImgCharacteristicsCommand(opts)
@@ -324,6 +515,84 @@ func histogramImgCharacteristicsExecutioner(imgPath string, args map[string]stri
 	}
 }
 
+func imgStatisticsExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	percentiles, err := parsePercentilesArg(args, "percentiles")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	tolerance, err := parseIntArg(args, "grayscaleTolerance")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath: imgPath,
+		statisticsOptions: analysis.StatisticsOptions{
+			Percentiles:        percentiles,
+			GrayscaleTolerance: tolerance,
+		},
+	}
+
+	msg, output, err := handleImgStatisticsCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Output:  output,
+		Err:     err,
+	}
+}
+
+func textureFeaturesExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	distance, err := parseIntArg(args, "glcmDistance")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	angles, err := analysis.ParseGLCMAngles(args["glcmAngles"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	levels, err := parseIntArg(args, "glcmLevels")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath: imgPath,
+		glcmOptions: analysis.GLCMOptions{
+			Distance:  distance,
+			Angles:    angles,
+			Levels:    levels,
+			Symmetric: true,
+		},
+	}
+
+	msg, output, err := handleTextureFeaturesCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Output:  output,
+		Err:     err,
+	}
+}
+
 func rayleighTransformExecutioner(imgPath string, args map[string]string) ExecutionResult {
 	lowCut, err := parseIntArg(args, "lowCut")
 	if err != nil {
@@ -381,10 +650,19 @@ func maskEdgeSharpeningExecutioner(imgPath string, args map[string]string) Execu
 		}
 	}
 
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:            imgPath,
 		maskName:           maskName,
 		edgeSharpeningMask: mask,
+		borderPolicy:       border,
 	}
 
 	msg, err := handleMaskEdgeSharpeningCommand(opts)
@@ -396,33 +674,20 @@ func maskEdgeSharpeningExecutioner(imgPath string, args map[string]string) Execu
 }
 
 func kirshEdgeDetectionExecutioner(imgPath string, args map[string]string) ExecutionResult {
-	opts := handlingCommandOptions{
-		imgPath: imgPath,
-	}
-
-	msg, err := handleKirshEdgeDetectionCommand(opts)
-
-	return ExecutionResult{
-		Message: msg,
-		Err:     err,
-	}
-}
-
-func dilationExecutioner(imgPath string, args map[string]string) ExecutionResult {
-	seElementName := strings.TrimSpace(args["structureElementName"])
-	if strings.TrimSpace(seElementName) == "" {
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
 		return ExecutionResult{
 			Message: "",
-			Err:     errors.New("structure element name cannot be empty"),
+			Err:     err,
 		}
 	}
 
 	opts := handlingCommandOptions{
-		imgPath:              imgPath,
-		structureElementName: seElementName,
+		imgPath:      imgPath,
+		borderPolicy: border,
 	}
 
-	msg, err := handleDilationCommand(opts)
+	msg, err := handleKirshEdgeDetectionCommand(opts)
 
 	return ExecutionResult{
 		Message: msg,
@@ -430,7 +695,7 @@ func dilationExecutioner(imgPath string, args map[string]string) ExecutionResult
 	}
 }
 
-func erosionExecutioner(imgPath string, args map[string]string) ExecutionResult {
+func morphologicalOperationExecutioner(imgPath string, args map[string]string, operation morphological.Operation) ExecutionResult {
 	seElementName := strings.TrimSpace(args["structureElementName"])
 	if strings.TrimSpace(seElementName) == "" {
 		return ExecutionResult{
@@ -439,56 +704,317 @@ func erosionExecutioner(imgPath string, args map[string]string) ExecutionResult
 		}
 	}
 
-	opts := handlingCommandOptions{
-		imgPath:              imgPath,
-		structureElementName: seElementName,
-	}
-
-	msg, err := handleErosionCommand(opts)
-
-	return ExecutionResult{
-		Message: msg,
-		Err:     err,
-	}
-}
-
-func openingExecutioner(imgPath string, args map[string]string) ExecutionResult {
-	seElementName := strings.TrimSpace(args["structureElementName"])
-	if strings.TrimSpace(seElementName) == "" {
+	mode, err := morphological.ParseMode(strings.TrimSpace(args["morphologyMode"]))
+	if err != nil {
 		return ExecutionResult{
 			Message: "",
-			Err:     errors.New("structure element name cannot be empty"),
+			Err:     err,
 		}
 	}
 
-	opts := handlingCommandOptions{
-		imgPath:              imgPath,
-		structureElementName: seElementName,
+	iterations := 1
+	if strings.TrimSpace(args["iterations"]) != "" {
+		if iterations, err = parseIntArg(args, "iterations"); err != nil {
+			return ExecutionResult{
+				Message: "",
+				Err:     err,
+			}
+		}
 	}
-
-	msg, err := handleOpeningCommand(opts)
-
-	return ExecutionResult{
-		Message: msg,
-		Err:     err,
+	if iterations < 1 {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("iterations must be a positive number"),
+		}
 	}
-}
 
-func closingExecutioner(imgPath string, args map[string]string) ExecutionResult {
-	seElementName := strings.TrimSpace(args["structureElementName"])
-	if strings.TrimSpace(seElementName) == "" {
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
 		return ExecutionResult{
 			Message: "",
-			Err:     errors.New("structure element name cannot be empty"),
+			Err:     err,
 		}
 	}
 
 	opts := handlingCommandOptions{
-		imgPath:              imgPath,
-		structureElementName: seElementName,
+		imgPath:               imgPath,
+		structureElementName:  seElementName,
+		morphologyMode:        mode,
+		structureElementsFile: strings.TrimSpace(args["structureElementsFile"]),
+		iterations:            iterations,
+		borderPolicy:          border,
 	}
 
-	msg, err := handleClosingCommand(opts)
+	msg, err := handleMorphologicalOperationCommand(opts, operation)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func dilationExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationDilation)
+}
+
+func erosionExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationErosion)
+}
+
+func openingExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationOpening)
+}
+
+func closingExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationClosing)
+}
+
+func gradientExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationGradient)
+}
+
+func whiteTopHatExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationWhiteTopHat)
+}
+
+func blackTopHatExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	return morphologicalOperationExecutioner(imgPath, args, morphological.OperationBlackTopHat)
+}
+
+func alternatingSequentialFilterExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	seElementName := strings.TrimSpace(args["structureElementName"])
+	if seElementName == "" {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("structure element name cannot be empty"),
+		}
+	}
+
+	size, err := parseIntArg(args, "asfSize")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	order, err := morphological.ParseASFOrder(args["asfOrder"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	mode, err := morphological.ParseMode(strings.TrimSpace(args["morphologyMode"]))
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:               imgPath,
+		structureElementName:  seElementName,
+		morphologyMode:        mode,
+		structureElementsFile: strings.TrimSpace(args["structureElementsFile"]),
+		asfSize:               size,
+		asfOrder:              order,
+	}
+
+	msg, err := handleAlternatingSequentialFilterCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func morphologyExpressionExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	expression := strings.TrimSpace(args["expression"])
+	if expression == "" {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("morphology expression cannot be empty"),
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:               imgPath,
+		structureElementsFile: strings.TrimSpace(args["structureElementsFile"]),
+		morphologyExpression:  expression,
+	}
+
+	msg, err := handleMorphologyExpressionCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func reconstructionExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	markerImagePath := strings.TrimSpace(args["markerImagePath"])
+	if markerImagePath == "" {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("marker image path cannot be empty"),
+		}
+	}
+
+	method, err := morphological.ParseReconstructionMethod(args["reconstructionMethod"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	steps, err := parseIntArg(args, "geodesicSteps")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	mode, err := morphological.ParseMode(args["morphologyMode"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:              imgPath,
+		markerImagePath:      markerImagePath,
+		reconstructionMethod: method,
+		geodesicSteps:        steps,
+		morphologyMode:       mode,
+		connectivity:         connectivity,
+	}
+
+	msg, err := handleReconstructionCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func fillHolesExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	opts, err := parseCleanupArgs(imgPath, args)
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	msg, err := handleFillHolesCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func clearBorderExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	opts, err := parseCleanupArgs(imgPath, args)
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	msg, err := handleClearBorderCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func hExtremaExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	h, err := parseIntArg(args, "hValue")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:      imgPath,
+		extrema:      args["extrema"],
+		hValue:       h,
+		connectivity: connectivity,
+	}
+
+	msg, err := handleHExtremaCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func regionalMaximaExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:      imgPath,
+		connectivity: connectivity,
+	}
+
+	msg, err := handleRegionalMaximaCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func distanceTransformExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	metric, err := morphological.ParseDistanceCriterion(args["distanceMetric"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:           imgPath,
+		distanceCriterion: metric,
+	}
+
+	msg, err := handleDistanceTransformCommand(opts)
 
 	return ExecutionResult{
 		Message: msg,
@@ -499,21 +1025,208 @@ func closingExecutioner(imgPath string, args map[string]string) ExecutionResult
 func hitOrMissExecutioner(imgPath string, args map[string]string) ExecutionResult {
 	foregroundSE := strings.TrimSpace(args["foregroundStructureElementName"])
 	backgroundSE := strings.TrimSpace(args["backgroundStructureElementName"])
+	template := strings.TrimSpace(args["hitOrMissTemplate"])
+
+	if template == "" && (foregroundSE == "" || backgroundSE == "") {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("structure element names cannot be empty for hit-or-miss operation"),
+		}
+	}
+
+	rotations, err := parseIntArg(args, "rotations")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:               imgPath,
+		foregroundSE:          foregroundSE,
+		backgroundSE:          backgroundSE,
+		structureElementsFile: strings.TrimSpace(args["structureElementsFile"]),
+		hitOrMissTemplate:     template,
+		rotations:             rotations,
+		borderPolicy:          border,
+	}
+
+	msg, err := handleHitOrMissCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func thinningExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	border, err := parseBorderArg(args, "borderMode")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	algorithm, err := morphological.ParseSkeletonAlgorithm(args["skeletonAlgorithm"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	maxIterations, err := parseIntArg(args, "maxIterations")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	pruneLength, err := parseIntArg(args, "pruneLength")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:      imgPath,
+		borderPolicy: border,
+		skeletonOptions: morphological.SkeletonOptions{
+			Algorithm:     algorithm,
+			MaxIterations: maxIterations,
+			PruneLength:   pruneLength,
+		},
+	}
+
+	msg, err := handleThinningCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func convexHullExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	perComponent, err := parseBoolArg(args, "perComponent")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	maxIterations, err := parseIntArg(args, "maxIterations")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:       imgPath,
+		perComponent:  perComponent,
+		connectivity:  connectivity,
+		maxIterations: maxIterations,
+	}
+
+	msg, err := handleConvexHullCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
 
-	if foregroundSE == "" || backgroundSE == "" {
+func thickeningExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	maxIterations, err := parseIntArg(args, "maxIterations")
+	if err != nil {
 		return ExecutionResult{
 			Message: "",
-			Err:     errors.New("structure element names cannot be empty for hit-or-miss operation"),
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:       imgPath,
+		maxIterations: maxIterations,
+	}
+
+	msg, err := handleThickeningCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func pruningExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	pruneLength, err := parseIntArg(args, "pruneLength")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:     imgPath,
+		pruneLength: pruneLength,
+	}
+
+	msg, err := handlePruningCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func componentLabelingExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	minArea, err := parseIntArg(args, "minArea")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
 		}
 	}
 
 	opts := handlingCommandOptions{
 		imgPath:      imgPath,
-		foregroundSE: foregroundSE,
-		backgroundSE: backgroundSE,
+		connectivity: connectivity,
+		minArea:      minArea,
 	}
 
-	msg, err := handleHitOrMissCommand(opts)
+	msg, err := handleComponentLabelingCommand(opts)
 
 	return ExecutionResult{
 		Message: msg,
@@ -521,12 +1234,86 @@ func hitOrMissExecutioner(imgPath string, args map[string]string) ExecutionResul
 	}
 }
 
-func thinningExecutioner(imgPath string, args map[string]string) ExecutionResult {
+func shapeDescriptorsExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	withOverlay, err := parseBoolArg(args, "withOverlay")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
-		imgPath: imgPath,
+		imgPath:      imgPath,
+		shapesExport: args["shapesExport"],
+		withOverlay:  withOverlay,
 	}
 
-	msg, err := handleThinningCommand(opts)
+	msg, err := handleShapeDescriptorsCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func boundaryExtractionExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	seElementName := strings.TrimSpace(args["structureElementName"])
+	if seElementName == "" {
+		return ExecutionResult{
+			Message: "",
+			Err:     errors.New("structure element name cannot be empty"),
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:               imgPath,
+		structureElementName:  seElementName,
+		structureElementsFile: strings.TrimSpace(args["structureElementsFile"]),
+	}
+
+	msg, err := handleBoundaryExtractionCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func contourTracingExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	method, err := morphological.ParseContourMethod(args["contourMethod"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	epsilon, err := parseFloatArg(args, "contourEpsilon")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	withOverlay, err := parseBoolArg(args, "withOverlay")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:        imgPath,
+		contourMethod:  method,
+		contourEpsilon: epsilon,
+		contoursExport: args["contoursExport"],
+		withOverlay:    withOverlay,
+	}
+
+	msg, err := handleContourTracingCommand(opts)
 
 	return ExecutionResult{
 		Message: msg,
@@ -536,10 +1323,39 @@ func thinningExecutioner(imgPath string, args map[string]string) ExecutionResult
 
 func regionGrowExecutioner(imgPath string, args map[string]string) ExecutionResult {
 	seedPoints := strings.TrimSpace(args["seedPoints"])
-	if seedPoints == "" {
+	seedFile := strings.TrimSpace(args["seedFile"])
+
+	var seeding morphological.SeedingOptions
+	if strategy := strings.TrimSpace(args["seedStrategy"]); strategy != "" {
+		var err error
+		if seeding.Strategy, err = morphological.ParseSeedStrategy(strategy); err != nil {
+			return ExecutionResult{
+				Message: "",
+				Err:     err,
+			}
+		}
+
+		if seeding.Count, err = parseIntArg(args, "seedCount"); err != nil {
+			return ExecutionResult{
+				Message: "",
+				Err:     err,
+			}
+		}
+
+		randomSeed, err := parseIntArg(args, "randomSeed")
+		if err != nil {
+			return ExecutionResult{
+				Message: "",
+				Err:     err,
+			}
+		}
+		seeding.RandomSeed = int64(randomSeed)
+	}
+
+	if seedPoints == "" && seedFile == "" && seeding.Strategy == "" {
 		return ExecutionResult{
 			Message: "",
-			Err:     errors.New("seed points cannot be empty"),
+			Err:     errors.New("seed points, a seed file or a seeding strategy are required"),
 		}
 	}
 
@@ -574,14 +1390,195 @@ func regionGrowExecutioner(imgPath string, args map[string]string) ExecutionResu
 		}
 	}
 
+	growthReference, err := morphological.ParseGrowthReference(args["growthReference"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	deviations := 2.0
+	if growthReference == morphological.ReferenceStatistics {
+		if deviations, err = parseFloatArg(args, "deviations"); err != nil {
+			return ExecutionResult{
+				Message: "",
+				Err:     err,
+			}
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:         imgPath,
+		seedPointsStr:   seedPoints,
+		seedFile:        seedFile,
+		seeding:         seeding,
+		distanceMetric:  distanceMetric,
+		thresholdValue:  threshold,
+		growthReference: growthReference,
+		deviations:      deviations,
+		connectivity:    connectivity,
+	}
+
+	msg, err := handleRegionGrowCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func regionMergeExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	strategy, err := morphological.ParseSeedStrategy(args["seedStrategy"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	seedCount, err := parseIntArg(args, "seedCount")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	randomSeed, err := parseIntArg(args, "randomSeed")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	distanceMetric, err := parseIntArg(args, "distanceMetric")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	threshold, err := parseFloatArg(args, "threshold")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	mergeThreshold, err := parseFloatArg(args, "mergeThreshold")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	minArea, err := parseIntArg(args, "minArea")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
 	opts := handlingCommandOptions{
 		imgPath:        imgPath,
-		seedPointsStr:  seedPoints,
+		seeding:        morphological.SeedingOptions{Strategy: strategy, Count: seedCount, RandomSeed: int64(randomSeed)},
 		distanceMetric: distanceMetric,
 		thresholdValue: threshold,
+		mergeThreshold: mergeThreshold,
+		minArea:        minArea,
+		connectivity:   connectivity,
 	}
 
-	msg, err := handleRegionGrowCommand(opts)
+	msg, err := handleRegionMergeCommand(opts)
+
+	return ExecutionResult{
+		Message: msg,
+		Err:     err,
+	}
+}
+
+func splitAndMergeExecutioner(imgPath string, args map[string]string) ExecutionResult {
+	predicate, err := morphological.ParseHomogeneityPredicate(args["homogeneityPredicate"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	threshold, err := parseFloatArg(args, "threshold")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	minBlockSize, err := parseIntArg(args, "minBlockSize")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	minArea, err := parseIntArg(args, "minArea")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	distanceMetric, err := parseIntArg(args, "distanceMetric")
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return ExecutionResult{
+			Message: "",
+			Err:     err,
+		}
+	}
+
+	opts := handlingCommandOptions{
+		imgPath:              imgPath,
+		homogeneityPredicate: predicate,
+		thresholdValue:       threshold,
+		minBlockSize:         minBlockSize,
+		minArea:              minArea,
+		distanceMetric:       distanceMetric,
+		connectivity:         connectivity,
+	}
+
+	msg, err := handleSplitAndMergeCommand(opts)
 
 	return ExecutionResult{
 		Message: msg,
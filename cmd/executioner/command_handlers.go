@@ -1,8 +1,10 @@
 package executioner
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
+	"image"
 	"imagio/analysis"
 	"imagio/cmd"
 	"imagio/imageio"
@@ -18,8 +20,48 @@ type handlingCommandOptions struct {
 	lowCut, highCut, brightnessPercentage, contrast, factor, distanceMetric                                                                                                                 int
 	cutoff, k, l, maxWindowSize, minWindowSize                                                                                                                                              int
 	alphaValue, thresholdValue                                                                                                                                                              float64
-	withSpectrumImgGenerated                                                                                                                                                                bool
+	withSpectrumImgGenerated, withStats, withDiagnostics, perChannel, withDifferenceImages                                                                                                  bool
 	edgeSharpeningMask                                                                                                                                                                      [][]int
+	borderPolicy                                                                                                                                                                            manipulations.BorderPolicy
+	alignment                                                                                                                                                                               analysis.AlignmentMode
+	histogramChannels                                                                                                                                                                       []manipulations.HistogramChannel
+	histogramPlotOptions                                                                                                                                                                    manipulations.HistogramPlotOptions
+	histogramExport                                                                                                                                                                         analysis.HistogramExportFormat
+	statisticsOptions                                                                                                                                                                       analysis.StatisticsOptions
+	glcmOptions                                                                                                                                                                             analysis.GLCMOptions
+	shapesExport                                                                                                                                                                            string
+	connectivity                                                                                                                                                                            morphological.Connectivity
+	minArea                                                                                                                                                                                 int
+	withOverlay                                                                                                                                                                             bool
+	morphologyMode                                                                                                                                                                          morphological.Mode
+	reconstructionMethod                                                                                                                                                                    morphological.ReconstructionMethod
+	markerImagePath                                                                                                                                                                         string
+	geodesicSteps                                                                                                                                                                           int
+	extrema                                                                                                                                                                                 string
+	hValue                                                                                                                                                                                  int
+	skeletonOptions                                                                                                                                                                         morphological.SkeletonOptions
+	distanceCriterion                                                                                                                                                                       morphological.DistanceCriterion
+	structureElementsFile                                                                                                                                                                   string
+	hitOrMissTemplate                                                                                                                                                                       string
+	rotations                                                                                                                                                                               int
+	iterations                                                                                                                                                                              int
+	asfSize                                                                                                                                                                                 int
+	asfOrder                                                                                                                                                                                morphological.ASFOrder
+	morphologyExpression                                                                                                                                                                    string
+	contourMethod                                                                                                                                                                           morphological.ContourMethod
+	contourEpsilon                                                                                                                                                                          float64
+	contoursExport                                                                                                                                                                          string
+	perComponent                                                                                                                                                                            bool
+	maxIterations                                                                                                                                                                           int
+	pruneLength                                                                                                                                                                             int
+	seedFile                                                                                                                                                                                string
+	seeding                                                                                                                                                                                 morphological.SeedingOptions
+	growthReference                                                                                                                                                                         morphological.GrowthReference
+	deviations                                                                                                                                                                              float64
+	mergeThreshold                                                                                                                                                                          float64
+	homogeneityPredicate                                                                                                                                                                    morphological.HomogeneityPredicate
+	minBlockSize                                                                                                                                                                            int
+	ssimOptions                                                                                                                                                                             analysis.SSIMOptions
 }
 
 func validateFrequencyRange(lowCut, highCut, imgWidth int) error {
@@ -38,6 +80,14 @@ func validateFrequencyRange(lowCut, highCut, imgWidth int) error {
 	return nil
 }
 
+// loadStructureElementsFile makes the elements of an optional JSON file available by name.
+func loadStructureElementsFile(path string) error {
+	if path == "" {
+		return nil
+	}
+	return morphological.LoadStructureElementsFile(path)
+}
+
 func saveFilteringResults(handlerResult []cmd.ResultImage) error {
 	for _, resultImg := range handlerResult {
 		err := imageio.SaveBmpImage(resultImg.GetImage(), resultImg.GetName())
@@ -275,17 +325,28 @@ func handleAdaptiveNoiseFilterCommand(opts handlingCommandOptions) (successMsgSt
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_denoised_via_adaptive_filter.bmp", imgFileName)
 
-	denoisedImg := noise.AdaptiveMedianFilter(img, opts.minWindowSize, opts.maxWindowSize)
+	denoisedImg, report := noise.AdaptiveMedianFilterWithReport(img, opts.minWindowSize, opts.maxWindowSize, opts.borderPolicy)
 
-	denoisedResult := cmd.BasicImgResult{
+	results := []cmd.ResultImage{cmd.BasicImgResult{
 		Img:  denoisedImg,
 		Name: outputFileName,
+	}}
+
+	if opts.withDiagnostics {
+		results = append(results,
+			cmd.BasicImgResult{Img: report.WindowSizeHeatMap(), Name: fmt.Sprintf("%s_adaptive_filter_window_sizes.bmp", imgFileName)},
+			cmd.BasicImgResult{Img: report.ModifiedMask(), Name: fmt.Sprintf("%s_adaptive_filter_modified_mask.bmp", imgFileName)},
+		)
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{denoisedResult}); err != nil {
+	if err := saveFilteringResults(results); err != nil {
 		return "", err
 	}
 
+	if opts.withStats || opts.withDiagnostics {
+		return "Image denoised via adaptive filter successfully. " + report.Summary(), nil
+	}
+
 	return "Image denoised via adaptive filter successfully", nil
 }
 
@@ -306,7 +367,7 @@ func handleMinNoiseFilterCommand(opts handlingCommandOptions) (successMsgString
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_denoised_via_min_filter.bmp", imgFileName)
 
-	denoisedImg := noise.MinFilter(img, opts.minWindowSize)
+	denoisedImg := noise.MinFilter(img, opts.minWindowSize, opts.borderPolicy)
 
 	denoisedResult := cmd.BasicImgResult{
 		Img:  denoisedImg,
@@ -337,7 +398,7 @@ func handleMaxNoiseFilterCommand(opts handlingCommandOptions) (successMsgString
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_denoised_via_max_filter.bmp", imgFileName)
 
-	denoisedImg := noise.MaxFilter(img, opts.maxWindowSize)
+	denoisedImg := noise.MaxFilter(img, opts.maxWindowSize, opts.borderPolicy)
 
 	denoisedResult := cmd.BasicImgResult{
 		Img:  denoisedImg,
@@ -374,12 +435,53 @@ func handleImgComparisonCommand(opts handlingCommandOptions) (successMsgString s
 	img2Name := imageio.GetFileName(opts.comparisonImagePath)
 
 	for _, comparison := range selectedCommands {
-		result := analysis.CalculateComparisonCharacteristic(comparison, img, comparisonImg)
+		comparisonOptions := analysis.ComparisonOptions{SSIM: opts.ssimOptions, PerChannel: opts.perChannel, Alignment: opts.alignment}
+
+		result, err := analysis.CalculateComparisonCharacteristic(comparison, img, comparisonImg, comparisonOptions)
+		if err != nil {
+			return "", nil, err
+		}
 		result.Img1Name = img1Name
 		result.Img2Name = img2Name
 		entries = append(entries, result)
 	}
 
+	if opts.withDifferenceImages {
+		imgFileName := imageio.GetPureFileName(opts.imgPath)
+
+		alignedImg, alignedComparisonImg, err := analysis.AlignImages(img, comparisonImg, opts.alignment)
+		if err != nil {
+			return "", nil, err
+		}
+
+		differenceImg, err := analysis.AbsoluteDifferenceImage(alignedImg, alignedComparisonImg)
+		if err != nil {
+			return "", nil, err
+		}
+
+		heatMapImg, err := analysis.DifferenceHeatMap(alignedImg, alignedComparisonImg)
+		if err != nil {
+			return "", nil, err
+		}
+
+		differenceResults := []cmd.ResultImage{
+			cmd.BasicImgResult{
+				Img:  differenceImg,
+				Name: fmt.Sprintf("%s_abs_difference.bmp", imgFileName),
+			},
+			cmd.BasicImgResult{
+				Img:  heatMapImg,
+				Name: fmt.Sprintf("%s_difference_heatmap.bmp", imgFileName),
+			},
+		}
+
+		if err := saveFilteringResults(differenceResults); err != nil {
+			return "", nil, err
+		}
+
+		return "Images compared successfully, difference images saved", entries, nil
+	}
+
 	return "Images compared successfully", entries, nil
 }
 
@@ -390,17 +492,60 @@ func handleImgHistogramCommand(opts handlingCommandOptions) (successMsgString st
 	}
 
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
-	histogramImg := manipulations.GenerateGraphicalRepresentationOfHistogram(manipulations.CalculateHistogram(img))
 
-	histogramResult := cmd.BasicImgResult{
-		Img:  histogramImg,
-		Name: fmt.Sprintf("%s_histogram.bmp", imgFileName),
+	var histogramResults []cmd.ResultImage
+
+	for _, channel := range opts.histogramChannels {
+		histogramImg, err := manipulations.PlotHistogram(manipulations.CalculateChannelHistogram(img, channel), channel, opts.histogramPlotOptions)
+		if err != nil {
+			return "", err
+		}
+
+		name := fmt.Sprintf("%s_histogram.bmp", imgFileName)
+		if channel != manipulations.HistogramValue {
+			name = fmt.Sprintf("%s_histogram_%s.bmp", imgFileName, channel)
+		}
+
+		histogramResults = append(histogramResults, cmd.BasicImgResult{Img: histogramImg, Name: name})
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{histogramResult}); err != nil {
+	if len(opts.histogramChannels) > 1 {
+		overlaidImg, err := manipulations.GenerateOverlaidRGBHistogram(
+			manipulations.CalculateChannelHistogram(img, manipulations.HistogramRed),
+			manipulations.CalculateChannelHistogram(img, manipulations.HistogramGreen),
+			manipulations.CalculateChannelHistogram(img, manipulations.HistogramBlue),
+			opts.histogramPlotOptions,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		histogramResults = append(histogramResults, cmd.BasicImgResult{Img: overlaidImg, Name: fmt.Sprintf("%s_histogram_rgb.bmp", imgFileName)})
+	}
+
+	if err := saveFilteringResults(histogramResults); err != nil {
 		return "", err
 	}
 
+	if opts.histogramExport != "" {
+		var data bytes.Buffer
+		export := analysis.NewHistogramExport(img, imageio.GetFileName(opts.imgPath), opts.histogramChannels)
+		if err := export.Write(&data, opts.histogramExport); err != nil {
+			return "", err
+		}
+
+		name := fmt.Sprintf("%s_histogram_all.%s", imgFileName, opts.histogramExport)
+		if len(opts.histogramChannels) == 1 {
+			name = strings.TrimSuffix(histogramResults[0].GetName(), ".bmp") + "." + string(opts.histogramExport)
+		}
+
+		if err := imageio.SaveDataFile(data.Bytes(), name); err != nil {
+			return "", err
+		}
+
+		return "Histogram calculated successfully, data exported", nil
+	}
+
 	return "Histogram calculated successfully", nil
 }
 
@@ -422,15 +567,57 @@ func handleHistogramImgCharacteristicsCommand(opts handlingCommandOptions) (succ
 	var characteristics []analysis.CharacteristicsEntry
 
 	for _, characteristic := range selectedCharacteristics {
-		histogram := manipulations.CalculateHistogram(img)
-		result := analysis.CalculateHistogramCharacteristic(characteristic, histogram, imgPureName)
-		result.Img1Name = imgName
-		characteristics = append(characteristics, result)
+		for _, channel := range opts.histogramChannels {
+			histogram := manipulations.CalculateChannelHistogram(img, channel)
+			result, err := analysis.CalculateHistogramCharacteristic(characteristic, histogram, imgPureName)
+			if err != nil {
+				return "", nil, err
+			}
+			if channel != manipulations.HistogramValue || len(opts.histogramChannels) > 1 {
+				result.Channel = analysis.HistogramChannelOf(channel)
+			}
+			result.Img1Name = imgName
+			characteristics = append(characteristics, result)
+		}
 	}
 
 	return "Histogram characteristics calculated successfully", characteristics, nil
 }
 
+func handleImgStatisticsCommand(opts handlingCommandOptions) (successMsgString string, output []analysis.CharacteristicsEntry, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	stats := analysis.CalculateImageStatistics(img, imageio.GetFileName(opts.imgPath), opts.statisticsOptions)
+
+	var data bytes.Buffer
+	if err := stats.WriteJSON(&data); err != nil {
+		return "", nil, err
+	}
+
+	if err := imageio.SaveDataFile(data.Bytes(), fmt.Sprintf("%s_stats.json", imageio.GetPureFileName(opts.imgPath))); err != nil {
+		return "", nil, err
+	}
+
+	return "Image statistics calculated successfully, JSON saved", stats.Entries(), nil
+}
+
+func handleTextureFeaturesCommand(opts handlingCommandOptions) (successMsgString string, output []analysis.CharacteristicsEntry, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	features, err := analysis.CalculateTextureFeatures(img, opts.glcmOptions)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return "Texture features calculated successfully", features.Entries(imageio.GetFileName(opts.imgPath)), nil
+}
+
 func handleRayleighTransformCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
@@ -470,7 +657,7 @@ func handleMaskEdgeSharpeningCommand(opts handlingCommandOptions) (successMsgStr
 		return "", err
 	}
 
-	sharpenedImg := manipulations.ApplyConvolutionUniversal(img, opts.edgeSharpeningMask)
+	sharpenedImg := manipulations.ApplyConvolutionUniversal(img, opts.edgeSharpeningMask, opts.borderPolicy)
 
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_sharpened_with_%s_mask.bmp", imgFileName, opts.maskName)
@@ -494,7 +681,7 @@ func handleKirshEdgeDetectionCommand(opts handlingCommandOptions) (successMsgStr
 		return "", err
 	}
 
-	edgeDetectedImg := manipulations.ApplyKirshEdgeDetection(img)
+	edgeDetectedImg := manipulations.ApplyKirshEdgeDetection(img, opts.borderPolicy)
 
 	imgFileName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_kirsh_edge_detected.bmp", imgFileName)
@@ -512,152 +699,375 @@ func handleKirshEdgeDetectionCommand(opts handlingCommandOptions) (successMsgStr
 	return msg, nil
 }
 
-func handleDilationCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+// morphologicalOperationNames are the words used in the output file and success message of every operation.
+var morphologicalOperationNames = map[morphological.Operation][2]string{
+	morphological.OperationDilation:    {"dilated", "Dilation"},
+	morphological.OperationErosion:     {"eroded", "Erosion"},
+	morphological.OperationOpening:     {"opened", "Opening"},
+	morphological.OperationClosing:     {"closed", "Closing"},
+	morphological.OperationGradient:    {"gradient", "Morphological gradient"},
+	morphological.OperationWhiteTopHat: {"tophat", "White top-hat"},
+	morphological.OperationBlackTopHat: {"blackhat", "Black top-hat"},
+}
+
+func handleMorphologicalOperationCommand(opts handlingCommandOptions, operation morphological.Operation) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
 		return "", err
 	}
 
+	if err := loadStructureElementsFile(opts.structureElementsFile); err != nil {
+		return "", err
+	}
+
 	structuringElement, err := morphological.GetStructureElement(opts.structureElementName)
 	if err != nil {
 		return "", err
 	}
 
-	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	dilatedBinaryImg := morphological.Dilation(binaryImg, structuringElement)
-	dilatedImg := morphological.ConvertIntoImage(dilatedBinaryImg)
+	iterations := max(opts.iterations, 1)
+	resultImg, err := morphological.ApplyIteratedOperation(img, operation, structuringElement, opts.morphologyMode, iterations, opts.borderPolicy)
+	if err != nil {
+		return "", err
+	}
 
+	names := morphologicalOperationNames[operation]
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_dilated_with_%s.bmp", pureImgName, opts.structureElementName)
+	label := morphological.StructureElementLabel(opts.structureElementName)
+	if iterations > 1 {
+		label = fmt.Sprintf("%s_iterations_%d", label, iterations)
+	}
+	outputFileName := fmt.Sprintf("%s_%s_with_%s.bmp", pureImgName, names[0], label)
+	if opts.morphologyMode == morphological.ModeGray {
+		outputFileName = fmt.Sprintf("%s_%s_gray_with_%s.bmp", pureImgName, names[0], label)
+	}
 
-	dilatedResult := cmd.BasicImgResult{
-		Img:  dilatedImg,
+	result := cmd.BasicImgResult{
+		Img:  resultImg,
 		Name: outputFileName,
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{dilatedResult}); err != nil {
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Dilation applied successfully with %s structural element", opts.structureElementName)
+	msg := fmt.Sprintf("%s applied successfully in %s mode with %s structural element", names[1], opts.morphologyMode, opts.structureElementName)
+	if iterations > 1 {
+		msg = fmt.Sprintf("%s applied successfully in %s mode with %s structural element and %d iterations", names[1], opts.morphologyMode, opts.structureElementName, iterations)
+	}
 	return msg, nil
 }
 
-func handleErosionCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+func handleAlternatingSequentialFilterCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
 		return "", err
 	}
 
+	if err := loadStructureElementsFile(opts.structureElementsFile); err != nil {
+		return "", err
+	}
+
 	structuringElement, err := morphological.GetStructureElement(opts.structureElementName)
 	if err != nil {
 		return "", err
 	}
 
-	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	dilatedBinaryImg := morphological.Erosion(binaryImg, structuringElement)
-	dilatedImg := morphological.ConvertIntoImage(dilatedBinaryImg)
+	resultImg, err := morphological.ApplyAlternatingSequentialFilter(img, structuringElement, opts.asfSize, opts.asfOrder, opts.morphologyMode)
+	if err != nil {
+		return "", err
+	}
 
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_eroded_with_%s.bmp", pureImgName, opts.structureElementName)
+	outputFileName := fmt.Sprintf("%s_asf_%s_%d_se_%s.bmp", pureImgName, opts.asfOrder, opts.asfSize, morphological.StructureElementLabel(opts.structureElementName))
+	if opts.morphologyMode == morphological.ModeGray {
+		outputFileName = fmt.Sprintf("%s_asf_%s_%d_gray_se_%s.bmp", pureImgName, opts.asfOrder, opts.asfSize, morphological.StructureElementLabel(opts.structureElementName))
+	}
 
-	dilatedResult := cmd.BasicImgResult{
-		Img:  dilatedImg,
+	result := cmd.BasicImgResult{
+		Img:  resultImg,
 		Name: outputFileName,
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{dilatedResult}); err != nil {
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Erosion applied successfully with %s structural element", opts.structureElementName)
+	msg := fmt.Sprintf("Alternating sequential filter of size %d applied successfully in %s mode with %s structural element", opts.asfSize, opts.morphologyMode, opts.structureElementName)
 	return msg, nil
 }
 
-func handleOpeningCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+func handleMorphologyExpressionCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
 		return "", err
 	}
 
-	structuringElement, err := morphological.GetStructureElement(opts.structureElementName)
+	if err := loadStructureElementsFile(opts.structureElementsFile); err != nil {
+		return "", err
+	}
+
+	expression, err := morphological.ParseExpression(opts.morphologyExpression)
 	if err != nil {
 		return "", err
 	}
 
-	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	dilatedBinaryImg := morphological.Opening(binaryImg, structuringElement)
-	dilatedImg := morphological.ConvertIntoImage(dilatedBinaryImg)
+	binaryImg, err := expression.Evaluate(morphological.ConvertIntoBinaryImage(img))
+	if err != nil {
+		return "", err
+	}
 
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_opened_with_%s.bmp", pureImgName, opts.structureElementName)
-
-	dilatedResult := cmd.BasicImgResult{
-		Img:  dilatedImg,
-		Name: outputFileName,
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(binaryImg),
+		Name: fmt.Sprintf("%s_morph_%s.bmp", pureImgName, morphological.ExpressionLabel(opts.morphologyExpression)),
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{dilatedResult}); err != nil {
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Opening applied successfully with %s structural element", opts.structureElementName)
+	msg := fmt.Sprintf("Morphology expression %s evaluated successfully", expression)
 	return msg, nil
 }
 
-func handleClosingCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+func handleReconstructionCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
 		return "", err
 	}
 
-	structuringElement, err := morphological.GetStructureElement(opts.structureElementName)
+	marker, err := imageio.OpenBmpImage(opts.markerImagePath)
 	if err != nil {
 		return "", err
 	}
 
-	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	dilatedBinaryImg := morphological.Closing(binaryImg, structuringElement)
-	dilatedImg := morphological.ConvertIntoImage(dilatedBinaryImg)
+	if opts.geodesicSteps < 0 {
+		return "", errors.New("number of geodesic steps must not be negative")
+	}
+
+	reconstructedImg, err := morphological.ApplyInMode(img, marker, opts.morphologyMode, func(mask, marker morphological.GrayImage) morphological.GrayImage {
+		return morphological.Reconstruct(marker, mask, opts.reconstructionMethod, opts.connectivity, opts.geodesicSteps)
+	})
+	if err != nil {
+		return "", err
+	}
 
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_closed_with_%s.bmp", pureImgName, opts.structureElementName)
+	outputFileName := fmt.Sprintf("%s_reconstructed_%s_%s.bmp", pureImgName, opts.reconstructionMethod, opts.morphologyMode)
+	if opts.geodesicSteps > 0 {
+		outputFileName = fmt.Sprintf("%s_geodesic_%s_%d_%s.bmp", pureImgName, opts.reconstructionMethod, opts.geodesicSteps, opts.morphologyMode)
+	}
 
-	dilatedResult := cmd.BasicImgResult{
-		Img:  dilatedImg,
+	result := cmd.BasicImgResult{
+		Img:  reconstructedImg,
 		Name: outputFileName,
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{dilatedResult}); err != nil {
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Closing applied successfully with %s structural element", opts.structureElementName)
+	msg := fmt.Sprintf("Reconstruction by %s applied successfully with %s marker", opts.reconstructionMethod, imageio.GetFileName(opts.markerImagePath))
 	return msg, nil
 }
 
-func handleHitOrMissCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+func handleFillHolesCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	marker, err := openOptionalMarker(opts.markerImagePath)
+	if err != nil {
+		return "", err
+	}
+
+	filledImg, err := morphological.ApplyInMode(img, marker, opts.morphologyMode, func(img, seeds morphological.GrayImage) morphological.GrayImage {
+		return morphological.FillHoles(img, seeds, opts.connectivity)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result := cmd.BasicImgResult{
+		Img:  filledImg,
+		Name: fmt.Sprintf("%s_filled_%s.bmp", imageio.GetPureFileName(opts.imgPath), opts.morphologyMode),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return "Holes filled successfully", nil
+}
+
+func handleClearBorderCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	marker, err := openOptionalMarker(opts.markerImagePath)
+	if err != nil {
+		return "", err
+	}
+
+	clearedImg, err := morphological.ApplyInMode(img, marker, opts.morphologyMode, func(img, seeds morphological.GrayImage) morphological.GrayImage {
+		return morphological.ClearBorder(img, seeds, opts.connectivity)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result := cmd.BasicImgResult{
+		Img:  clearedImg,
+		Name: fmt.Sprintf("%s_cleared_%s.bmp", imageio.GetPureFileName(opts.imgPath), opts.morphologyMode),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return "Objects touching the border cleared successfully", nil
+}
+
+// openOptionalMarker opens the marker image of fill_holes and clear_border, an empty path means the image border.
+func openOptionalMarker(markerImagePath string) (image.Image, error) {
+	if markerImagePath == "" {
+		return nil, nil
+	}
+	return imageio.OpenBmpImage(markerImagePath)
+}
+
+func handleHExtremaCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
 		return "", err
 	}
 
-	fse, err := morphological.GetStructureElement(opts.foregroundSE)
+	if opts.hValue < 0 || opts.hValue > 255 {
+		return "", errors.New("h must be between 0 and 255")
+	}
+
+	var operation func(morphological.GrayImage, int, morphological.Connectivity) morphological.GrayImage
+	switch opts.extrema {
+	case "maxima":
+		operation = morphological.HMaxima
+	case "minima":
+		operation = morphological.HMinima
+	default:
+		return "", fmt.Errorf("unknown extrema %q, expected maxima or minima", opts.extrema)
+	}
+
+	suppressedImg, err := morphological.ApplyInMode(img, nil, morphological.ModeGray, func(img, _ morphological.GrayImage) morphological.GrayImage {
+		return operation(img, opts.hValue, opts.connectivity)
+	})
 	if err != nil {
 		return "", err
 	}
 
-	bse, err := morphological.GetStructureElement(opts.backgroundSE)
+	result := cmd.BasicImgResult{
+		Img:  suppressedImg,
+		Name: fmt.Sprintf("%s_h%s_h%d.bmp", imageio.GetPureFileName(opts.imgPath), opts.extrema, opts.hValue),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Regional %s lower than %d suppressed successfully", opts.extrema, opts.hValue), nil
+}
+
+func handleRegionalMaximaCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	maxima := morphological.RegionalMaxima(morphological.ConvertIntoGrayImage(img), opts.connectivity)
+
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(maxima),
+		Name: fmt.Sprintf("%s_regional_maxima.bmp", imageio.GetPureFileName(opts.imgPath)),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return "Regional maxima marked successfully", nil
+}
+
+func handleDistanceTransformCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	dist, err := morphological.DistanceTransform(morphological.ConvertIntoBinaryImage(img), opts.distanceCriterion)
+	if err != nil {
+		return "", err
+	}
+
+	result := cmd.BasicImgResult{
+		Img:  morphological.DistanceImage(dist),
+		Name: fmt.Sprintf("%s_distance_%s.bmp", imageio.GetPureFileName(opts.imgPath), opts.distanceCriterion),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Distance transform calculated successfully, max %s distance %.4f", opts.distanceCriterion, morphological.MaxDistance(dist)), nil
+}
+
+func handleHitOrMissCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := loadStructureElementsFile(opts.structureElementsFile); err != nil {
+		return "", err
+	}
+
+	var pair morphological.HitOrMissPair
+	label := "template"
+	if opts.hitOrMissTemplate != "" {
+		pair, err = morphological.ParseHitOrMissTemplate(opts.hitOrMissTemplate)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		fse, err := morphological.ResolveStructureElement(opts.foregroundSE)
+		if err != nil {
+			return "", err
+		}
+
+		bse, err := morphological.ResolveStructureElement(opts.backgroundSE)
+		if err != nil {
+			return "", err
+		}
+
+		pair = morphological.HitOrMissPair{Foreground: fse, Background: bse}
+		label = fmt.Sprintf("fse_%s_bse_%s", morphological.StructureElementLabel(opts.foregroundSE), morphological.StructureElementLabel(opts.backgroundSE))
+	}
+
+	rotations, err := pair.Rotations(opts.rotations)
 	if err != nil {
 		return "", err
 	}
 
 	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	hitOrMissBinaryImg := morphological.HitOrMiss(binaryImg, fse, bse)
+	hitOrMissBinaryImg := morphological.HitOrMissUnion(binaryImg, rotations, opts.borderPolicy)
 	hitOrMissImg := morphological.ConvertIntoImage(hitOrMissBinaryImg)
 
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_hit_or_miss_fse_%s_bse_%s.bmp", pureImgName, opts.foregroundSE, opts.backgroundSE)
+	outputFileName := fmt.Sprintf("%s_hit_or_miss_%s.bmp", pureImgName, label)
+	if opts.rotations > 1 {
+		outputFileName = fmt.Sprintf("%s_hit_or_miss_%s_rotations_%d.bmp", pureImgName, label, opts.rotations)
+	}
 
 	hitOrMissResult := cmd.BasicImgResult{
 		Img:  hitOrMissImg,
@@ -668,7 +1078,10 @@ func handleHitOrMissCommand(opts handlingCommandOptions) (successMsgString strin
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Hit or miss applied successfully with foreground SE: %s and background SE: %s", opts.foregroundSE, opts.backgroundSE)
+	msg := fmt.Sprintf("Hit or miss applied successfully with %d distinct rotations of foreground SE: %s and background SE: %s", len(rotations), opts.foregroundSE, opts.backgroundSE)
+	if opts.hitOrMissTemplate != "" {
+		msg = fmt.Sprintf("Hit or miss applied successfully with %d distinct rotations of template %s", len(rotations), opts.hitOrMissTemplate)
+	}
 	return msg, nil
 }
 
@@ -678,24 +1091,286 @@ func handleThinningCommand(opts handlingCommandOptions) (successMsgString string
 		return "", err
 	}
 
-	binaryImg := morphological.ConvertIntoBinaryImage(img)
-	seSeries := morphological.SeriesXIISE
-	thinnedBinaryImg := morphological.Thinning(binaryImg, seSeries)
-	thinnedImg := morphological.ConvertIntoImage(thinnedBinaryImg)
+	skeletonOptions := opts.skeletonOptions
+	skeletonOptions.Border = opts.borderPolicy
+
+	skeleton, err := morphological.Skeletonize(morphological.ConvertIntoBinaryImage(img), skeletonOptions)
+	if err != nil {
+		return "", err
+	}
 
 	pureImgName := imageio.GetPureFileName(opts.imgPath)
 	outputFileName := fmt.Sprintf("%s_thinned.bmp", pureImgName)
+	if skeletonOptions.Algorithm != morphological.SkeletonHitOrMiss {
+		outputFileName = fmt.Sprintf("%s_thinned_%s.bmp", pureImgName, skeletonOptions.Algorithm)
+	}
+
+	results := []cmd.ResultImage{cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(skeleton.Image),
+		Name: outputFileName,
+	}}
+
+	if skeleton.Distance != nil {
+		results = append(results, cmd.BasicImgResult{
+			Img:  morphological.MedialAxisImage(skeleton.Image, skeleton.Distance),
+			Name: fmt.Sprintf("%s_medial_axis_distance.bmp", pureImgName),
+		})
+	}
+
+	if err := saveFilteringResults(results); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Thinning applied successfully in %d iterations", skeleton.Iterations), nil
+}
+
+func handleConvexHullCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	binaryImg := morphological.ConvertIntoBinaryImage(img)
+	pureImgName := imageio.GetPureFileName(opts.imgPath)
+
+	var hull morphological.BinaryImage
+	var iterations int
+	outputFileName := fmt.Sprintf("%s_convex_hulls.bmp", pureImgName)
+	if opts.perComponent {
+		hull, iterations = morphological.ComponentConvexHulls(binaryImg, opts.connectivity, opts.maxIterations)
+	} else {
+		hull, iterations = morphological.ConvexHull(binaryImg, opts.maxIterations)
+		outputFileName = fmt.Sprintf("%s_convex_hull.bmp", pureImgName)
+	}
 
-	thinnedResult := cmd.BasicImgResult{
-		Img:  thinnedImg,
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(hull),
 		Name: outputFileName,
 	}
 
-	if err := saveFilteringResults([]cmd.ResultImage{thinnedResult}); err != nil {
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Convex hull filled successfully in %d iterations", iterations), nil
+}
+
+func handleThickeningCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	thickened, iterations := morphological.Thickening(morphological.ConvertIntoBinaryImage(img), nil, opts.maxIterations)
+
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(thickened),
+		Name: fmt.Sprintf("%s_thickened.bmp", imageio.GetPureFileName(opts.imgPath)),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Thickening applied successfully in %d iterations", iterations), nil
+}
+
+func handlePruningCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.pruneLength < 1 {
+		return "", errors.New("prune length must be a positive number")
+	}
+
+	pruned := morphological.PruneHitOrMiss(morphological.ConvertIntoBinaryImage(img), opts.pruneLength)
+
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(pruned),
+		Name: fmt.Sprintf("%s_pruned_%d.bmp", imageio.GetPureFileName(opts.imgPath), opts.pruneLength),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Spurs of at most %d pixels pruned successfully", opts.pruneLength), nil
+}
+
+func handleComponentLabelingCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	if opts.minArea < 0 {
+		return "", errors.New("minimum area must not be negative")
+	}
+
+	labeling := morphological.LabelComponents(morphological.ConvertIntoBinaryImage(img), opts.connectivity).FilterByArea(opts.minArea, 0)
+
+	labeledResult := cmd.BasicImgResult{
+		Img:  labeling.Colorize(),
+		Name: fmt.Sprintf("%s_labeled_c%d.bmp", imageio.GetPureFileName(opts.imgPath), opts.connectivity),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{labeledResult}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Labeled %d components successfully", len(labeling.Components)), nil
+}
+
+func handleShapeDescriptorsCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	binaryImg := morphological.ConvertIntoBinaryImage(img)
+	shapes := morphological.DescribeShapes(binaryImg)
+	pureImgName := imageio.GetPureFileName(opts.imgPath)
+
+	var data bytes.Buffer
+	switch opts.shapesExport {
+	case "csv":
+		err = morphological.WriteShapesCSV(&data, shapes)
+	case "json":
+		err = morphological.WriteShapesJSON(&data, shapes)
+	default:
+		err = fmt.Errorf("unknown export format %q, expected csv or json", opts.shapesExport)
+	}
+	if err != nil {
 		return "", err
 	}
 
-	return "Thinning applied successfully", nil
+	if err := imageio.SaveDataFile(data.Bytes(), fmt.Sprintf("%s_shapes.%s", pureImgName, opts.shapesExport)); err != nil {
+		return "", err
+	}
+
+	if opts.withOverlay {
+		overlayResult := cmd.BasicImgResult{
+			Img:  morphological.DrawShapesOverlay(binaryImg, shapes),
+			Name: fmt.Sprintf("%s_shapes_overlay.bmp", pureImgName),
+		}
+
+		if err := saveFilteringResults([]cmd.ResultImage{overlayResult}); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("Measured %d components successfully", len(shapes)), nil
+}
+
+func handleBoundaryExtractionCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := loadStructureElementsFile(opts.structureElementsFile); err != nil {
+		return "", err
+	}
+
+	structuringElement, err := morphological.GetStructureElement(opts.structureElementName)
+	if err != nil {
+		return "", err
+	}
+
+	boundary := morphological.Boundary(morphological.ConvertIntoBinaryImage(img), structuringElement)
+
+	pureImgName := imageio.GetPureFileName(opts.imgPath)
+	result := cmd.BasicImgResult{
+		Img:  morphological.ConvertIntoImage(boundary),
+		Name: fmt.Sprintf("%s_boundary_with_%s.bmp", pureImgName, morphological.StructureElementLabel(opts.structureElementName)),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("Boundary extracted successfully with %s structural element", opts.structureElementName)
+	return msg, nil
+}
+
+func handleContourTracingCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	binaryImg := morphological.ConvertIntoBinaryImage(img)
+	contours, err := morphological.TraceContours(binaryImg, opts.contourMethod)
+	if err != nil {
+		return "", err
+	}
+	pureImgName := imageio.GetPureFileName(opts.imgPath)
+
+	var data bytes.Buffer
+	switch opts.contoursExport {
+	case "json":
+		err = morphological.WriteContoursJSON(&data, contours, opts.contourEpsilon)
+	case "svg":
+		err = morphological.WriteContoursSVG(&data, contours, img.Bounds().Dx(), img.Bounds().Dy(), opts.contourEpsilon)
+	default:
+		err = fmt.Errorf("unknown export format %q, expected json or svg", opts.contoursExport)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := imageio.SaveDataFile(data.Bytes(), fmt.Sprintf("%s_contours_%s.%s", pureImgName, opts.contourMethod, opts.contoursExport)); err != nil {
+		return "", err
+	}
+
+	if opts.withOverlay {
+		overlayResult := cmd.BasicImgResult{
+			Img:  morphological.DrawContoursOverlay(binaryImg, contours, opts.contourEpsilon),
+			Name: fmt.Sprintf("%s_contours_%s_overlay.bmp", pureImgName, opts.contourMethod),
+		}
+
+		if err := saveFilteringResults([]cmd.ResultImage{overlayResult}); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("Traced %d contours successfully", len(contours)), nil
+}
+
+// collectSeeds gathers the region growing seeds given as points, in a seed file and by a seeding strategy.
+func collectSeeds(img image.Image, opts handlingCommandOptions) ([]morphological.Point, error) {
+	var seeds []morphological.Point
+
+	if strings.TrimSpace(opts.seedPointsStr) != "" {
+		points, err := morphological.ParseSeedPoints(opts.seedPointsStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse seed points: %w", err)
+		}
+		seeds = append(seeds, points...)
+	}
+
+	if opts.seedFile != "" {
+		points, err := morphological.LoadSeedPoints(opts.seedFile)
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, points...)
+	}
+
+	if opts.seeding.Strategy != "" {
+		points, err := morphological.GenerateSeeds(img, opts.seeding)
+		if err != nil {
+			return nil, err
+		}
+		seeds = append(seeds, points...)
+	}
+
+	if len(seeds) == 0 {
+		return nil, errors.New("region growing needs seed points, a seed file or a seeding strategy")
+	}
+	return seeds, nil
 }
 
 func handleRegionGrowCommand(opts handlingCommandOptions) (successMsgString string, err error) {
@@ -704,9 +1379,9 @@ func handleRegionGrowCommand(opts handlingCommandOptions) (successMsgString stri
 		return "", err
 	}
 
-	seeds, err := morphological.ParseSeedPoints(opts.seedPointsStr)
+	seeds, err := collectSeeds(img, opts)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse seed points: %w", err)
+		return "", err
 	}
 
 	metric := morphological.DistanceCriterion(opts.distanceMetric)
@@ -720,24 +1395,122 @@ func handleRegionGrowCommand(opts handlingCommandOptions) (successMsgString stri
 		return "", errors.New("threshold must be positive number")
 	}
 
-	_, imgWithMarkedRegions := morphological.RegionGrowing(img, seeds, metric, threshold)
+	_, imgWithMarkedRegions, err := morphological.GrowRegions(img, seeds, morphological.RegionGrowingOptions{
+		Criterion:    metric,
+		Threshold:    threshold,
+		Reference:    opts.growthReference,
+		Deviations:   opts.deviations,
+		Connectivity: opts.connectivity,
+	})
+	if err != nil {
+		return "", err
+	}
 
 	imgName := imageio.GetFileName(opts.imgPath)
-	outputFileName := fmt.Sprintf("%s_region_grown.bmp", imgName)
+	outputFileName := fmt.Sprintf("%s_region_grown", imgName)
+	if opts.growthReference != morphological.ReferenceSeed {
+		outputFileName += "_reference_" + string(opts.growthReference)
+	}
+	if opts.connectivity != morphological.Connectivity8 {
+		outputFileName += fmt.Sprintf("_c%d", opts.connectivity)
+	}
 
 	result := cmd.BasicImgResult{
 		Img:  imgWithMarkedRegions,
-		Name: outputFileName,
+		Name: outputFileName + ".bmp",
 	}
 
 	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
 		return "", err
 	}
 
-	msg := fmt.Sprintf("Region growing applied successfully with metric: %d and threshold: %.2f", metric, threshold)
+	msg := fmt.Sprintf("Region growing applied successfully from %d seeds with metric: %d and threshold: %.2f", len(seeds), metric, threshold)
 	return msg, nil
 }
 
+func handleRegionMergeCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	seeds, err := collectSeeds(img, opts)
+	if err != nil {
+		return "", err
+	}
+
+	metric := morphological.DistanceCriterion(opts.distanceMetric)
+	if metric < 0 || metric > 2 {
+		return "", errors.New("metric must be between 0 and 2")
+	}
+
+	grown, _, err := morphological.GrowRegions(img, seeds, morphological.RegionGrowingOptions{
+		Criterion:    metric,
+		Threshold:    opts.thresholdValue,
+		Reference:    morphological.ReferenceMean,
+		Connectivity: opts.connectivity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	merged, imgWithMarkedRegions, err := morphological.MergeRegions(img, grown, morphological.MergeOptions{
+		Criterion:    metric,
+		Threshold:    opts.mergeThreshold,
+		MinArea:      opts.minArea,
+		Connectivity: opts.connectivity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result := cmd.BasicImgResult{
+		Img:  imgWithMarkedRegions,
+		Name: fmt.Sprintf("%s_region_merged.bmp", imageio.GetPureFileName(opts.imgPath)),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Merged %d grown regions into %d successfully", morphological.RegionCount(grown), morphological.RegionCount(merged)), nil
+}
+
+func handleSplitAndMergeCommand(opts handlingCommandOptions) (successMsgString string, err error) {
+	img, err := imageio.OpenBmpImage(opts.imgPath)
+	if err != nil {
+		return "", err
+	}
+
+	metric := morphological.DistanceCriterion(opts.distanceMetric)
+	if metric < 0 || metric > 2 {
+		return "", errors.New("metric must be between 0 and 2")
+	}
+
+	labels, imgWithMarkedRegions, err := morphological.SplitAndMerge(img, morphological.SplitMergeOptions{
+		Criterion:    metric,
+		Predicate:    opts.homogeneityPredicate,
+		Threshold:    opts.thresholdValue,
+		MinSize:      opts.minBlockSize,
+		MinArea:      opts.minArea,
+		Connectivity: opts.connectivity,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	result := cmd.BasicImgResult{
+		Img:  imgWithMarkedRegions,
+		Name: fmt.Sprintf("%s_split_merge_%s.bmp", imageio.GetPureFileName(opts.imgPath), opts.homogeneityPredicate),
+	}
+
+	if err := saveFilteringResults([]cmd.ResultImage{result}); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Split and merged into %d regions successfully", morphological.RegionCount(labels)), nil
+}
+
 func handleBandpassCommand(opts handlingCommandOptions) (successMsgString string, err error) {
 	img, err := imageio.OpenBmpImage(opts.imgPath)
 	if err != nil {
@@ -19,22 +19,44 @@ var CommandDefinitions = []CommandDefinition{
 	{"flip_diagonally", "Flip the image diagonally.", []string{}},
 	{"shrink", "Shrink the image by given factor.", []string{"shrinkFactor"}},
 	{"enlarge", "Enlarge the image by given factor.", []string{"enlargeFactor"}},
-	{"adaptive_filter_denoising", "Apply adaptive median noise removal filter to the image.", []string{"minWindowSize", "maxWindowSize"}},
-	{"min_filter_denoising", "Apply min noise removal filter to the image.", []string{"minWindowSize"}},
-	{"max_filter_denoising", "Apply max noise removal filter to the image.", []string{"maxWindowSize"}},
-	{"img_comparison_commands", "Compare the image with another image.", []string{"dummy"}},
-	{"generate_img_histogram", "Generate and save a graphical representation of the histogram of the image.", []string{"dummy"}},
-	{"histogram_img_characteristics", "Calculate image characteristics based on it's histogram", []string{"dummy"}},
+	{"adaptive_filter_denoising", "Apply adaptive median noise removal filter to the image.", []string{"minWindowSize", "maxWindowSize", "borderMode", "withStats", "withDiagnostics"}},
+	{"min_filter_denoising", "Apply min noise removal filter to the image.", []string{"minWindowSize", "borderMode"}},
+	{"max_filter_denoising", "Apply max noise removal filter to the image.", []string{"maxWindowSize", "borderMode"}},
+	{"img_comparison_commands", "Compare the image with another image.", []string{"comparisonImagePath", "selectedComparisonCommands", "perChannel", "withDifferenceImages", "alignment", "ssimWindowSize", "ssimSigma", "ssimK1", "ssimK2"}},
+	{"generate_img_histogram", "Generate and save a graphical representation of the histogram of the image.", []string{"histogramChannel", "histogramScale", "withCumulative", "histogramMarkers", "histogramExport"}},
+	{"histogram_img_characteristics", "Calculate image characteristics based on it's histogram", []string{"selectedHistogramCharacteristicsCommands", "histogramChannel"}},
+	{"img_statistics", "Describe the image: dimensions, color model, per channel distribution, unique colors and saturated pixels.", []string{"percentiles", "grayscaleTolerance"}},
+	{"texture_features", "Calculate Haralick texture features from gray-level co-occurrence matrices.", []string{"glcmDistance", "glcmAngles", "glcmLevels"}},
 	{"rayleigh_transform", "Apply Rayleigh transform to the image.", []string{"lowCut", "highCut", "alphaValue"}},
-	{"mask_edge_sharpening", "Apply edge sharpening mask to the image.", []string{"maskName"}},
-	{"kirsh_edge_detection", "Apply Kirsh edge detection to the image.", []string{"dummy"}},
-	{"dilation", "Apply dilation operation using the chosen structural element.", []string{"structureElementName"}},
-	{"erosion", "Apply erosion operation using the chosen structural element.", []string{"structureElementName"}},
-	{"opening", "Apply opening operation using the chosen structural element.", []string{"structureElementName"}},
-	{"closing", "Apply closing operation using the chosen structural element.", []string{"structureElementName"}},
-	{"hit_or_miss", "Apply hit-or-miss operations with 2 chosen structural elements.", []string{"foregroundStructureElementName", "backgroundStructureElementName"}},
-	{"thinning", "Apply thinning aka skeletonization operation to the image.", []string{"dummy"}},
-	{"region_grow", "Perform region growing segmentation on the image.", []string{"dummy"}},
+	{"mask_edge_sharpening", "Apply edge sharpening mask to the image.", []string{"maskName", "borderMode"}},
+	{"kirsh_edge_detection", "Apply Kirsh edge detection to the image.", []string{"borderMode"}},
+	{"dilation", "Apply dilation operation using the chosen structural element.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"erosion", "Apply erosion operation using the chosen structural element.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"opening", "Apply opening operation using the chosen structural element.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"closing", "Apply closing operation using the chosen structural element.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"morphological_gradient", "Compute the morphological gradient, the dilation minus the erosion.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"white_top_hat", "Compute the white top-hat, the image minus its opening.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"black_top_hat", "Compute the black top-hat, the closing minus the image.", []string{"structureElementName", "morphologyMode", "iterations", "borderMode", "structureElementsFile"}},
+	{"alternating_sequential_filter", "Smooth the image with openings and closings of growing size.", []string{"structureElementName", "asfSize", "asfOrder", "morphologyMode", "structureElementsFile"}},
+	{"morphology_expression", "Evaluate a morphology expression such as close(disk3) | open(iv) - erode(iii) on the binary image.", []string{"expression", "structureElementsFile"}},
+	{"reconstruction", "Reconstruct the image from a marker image by dilation or erosion, optionally stopping after a number of geodesic steps.", []string{"markerImagePath", "reconstructionMethod", "geodesicSteps", "morphologyMode", "connectivity"}},
+	{"fill_holes", "Fill the holes not connected to the image border, or to the marker image when given.", []string{"markerImagePath", "morphologyMode", "connectivity"}},
+	{"clear_border", "Remove the objects touching the image border, or the marker image when given.", []string{"markerImagePath", "morphologyMode", "connectivity"}},
+	{"h_extrema", "Suppress the regional maxima or minima not exceeding their surroundings by h.", []string{"extrema", "hValue", "connectivity"}},
+	{"regional_maxima", "Mark the regional maxima of the image luma as a binary image.", []string{"connectivity"}},
+	{"distance_transform", "Calculate the Euclidean, city-block or chessboard distance of every foreground pixel to the background.", []string{"distanceMetric"}},
+	{"hit_or_miss", "Apply hit-or-miss operations with 2 chosen structural elements.", []string{"foregroundStructureElementName", "backgroundStructureElementName", "hitOrMissTemplate", "rotations", "borderMode", "structureElementsFile"}},
+	{"thinning", "Apply thinning aka skeletonization operation to the image.", []string{"skeletonAlgorithm", "maxIterations", "pruneLength", "borderMode"}},
+	{"convex_hull", "Fill the convex hull of the objects by iterating hit-or-miss, for every component or for the whole foreground.", []string{"perComponent", "connectivity", "maxIterations"}},
+	{"thickening", "Thicken the binary image with the thinning series with foreground and background swapped.", []string{"maxIterations"}},
+	{"pruning", "Remove the spurs of a skeleton by thinning its end points and growing the remaining ones back.", []string{"pruneLength"}},
+	{"component_labeling", "Label the connected components of the binary image and save them in distinct colors.", []string{"connectivity", "minArea"}},
+	{"shape_descriptors", "Measure the connected components of the binary image and save them as a table.", []string{"shapesExport", "withOverlay"}},
+	{"boundary_extraction", "Extract the inner boundary of the objects, the binary image minus its erosion.", []string{"structureElementName", "structureElementsFile"}},
+	{"contour_tracing", "Trace the outer and hole contours of the binary image and save them with their chain codes as JSON or SVG.", []string{"contourMethod", "contourEpsilon", "contoursExport", "withOverlay"}},
+	{"region_grow", "Perform region growing segmentation on the image.", []string{"seedPoints", "seedFile", "seedStrategy", "seedCount", "randomSeed", "distanceMetric", "threshold", "growthReference", "deviations", "connectivity"}},
+	{"region_merge", "Grow regions from seeds and merge the adjacent ones with similar means through a region adjacency graph.", []string{"seedStrategy", "seedCount", "randomSeed", "distanceMetric", "threshold", "mergeThreshold", "minArea", "connectivity"}},
+	{"split_and_merge", "Split the image into homogeneous quadtree blocks and merge the adjacent regions whose union stays homogeneous.", []string{"homogeneityPredicate", "threshold", "minBlockSize", "minArea", "distanceMetric", "connectivity"}},
 	{"bandpass", "Apply bandpass filtering to the image.", []string{"lowCut", "highCut", "withSpectrumImgGenerated"}},
 	{"lowpass", "Apply lowpass filtering to the image.", []string{"cutoff", "withSpectrumImgGenerated"}},
 	{"highpass", "Apply highpass filtering to the image.", []string{"cutoff", "withSpectrumImgGenerated"}},
@@ -2,7 +2,11 @@ package executioner
 
 import (
 	"fmt"
+	"imagio/analysis"
+	"imagio/manipulations"
+	"imagio/morphological"
 	"strconv"
+	"strings"
 )
 
 func parseIntArg(args map[string]string, key string) (int, error) {
@@ -28,3 +32,51 @@ func parseFloatArg(args map[string]string, key string) (float64, error) {
 	}
 	return strconv.ParseFloat(value, 64)
 }
+
+// parseBorderArg parses an optional border policy argument, a missing value falls back to the default policy.
+func parseBorderArg(args map[string]string, key string) (manipulations.BorderPolicy, error) {
+	return manipulations.ParseBorderPolicy(args[key])
+}
+
+// parseAlignmentArg parses an optional image alignment argument, a missing value disables alignment.
+func parseAlignmentArg(args map[string]string, key string) (analysis.AlignmentMode, error) {
+	return analysis.ParseAlignmentMode(args[key])
+}
+
+// parseHistogramChannelsArg parses an optional histogram channel argument, a missing value selects HSV value.
+func parseHistogramChannelsArg(args map[string]string, key string) ([]manipulations.HistogramChannel, error) {
+	return manipulations.ParseHistogramChannels(args[key])
+}
+
+// parseHistogramExportArg parses an optional histogram export argument, a missing value or "none" disables the export.
+func parseHistogramExportArg(args map[string]string, key string) (analysis.HistogramExportFormat, error) {
+	if value := args[key]; value != "" && value != "none" {
+		return analysis.ParseHistogramExportFormat(value)
+	}
+	return "", nil
+}
+
+// parsePercentilesArg parses an optional comma separated percentiles argument, a missing value selects the defaults.
+func parsePercentilesArg(args map[string]string, key string) ([]float64, error) {
+	return analysis.ParsePercentiles(args[key])
+}
+
+// parseCleanupArgs reads the mode and connectivity shared by hole filling and border clearing.
+func parseCleanupArgs(imgPath string, args map[string]string) (handlingCommandOptions, error) {
+	mode, err := morphological.ParseMode(args["morphologyMode"])
+	if err != nil {
+		return handlingCommandOptions{}, err
+	}
+
+	connectivity, err := morphological.ParseConnectivity(args["connectivity"])
+	if err != nil {
+		return handlingCommandOptions{}, err
+	}
+
+	return handlingCommandOptions{
+		imgPath:         imgPath,
+		markerImagePath: strings.TrimSpace(args["markerImagePath"]),
+		morphologyMode:  mode,
+		connectivity:    connectivity,
+	}, nil
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"imagio/analysis"
 	"imagio/manipulations"
 	"imagio/morphological"
 	"imagio/orthogonal_transforms"
@@ -17,10 +18,62 @@ func (m *Model) buildCommandForm() error {
 	var (
 		lowCut, highCut, cutoff, k, l, maskName, brightness, contrast, shrinkFactor, enlargeFactor, minWindowSize, maxWindowSize, comparisonImagePath, alpha, structureElementName, foregroundStructureElementName, backgroundStructureElementName, seedPointsStr, thresholdStr string
 		selectedComparisonCommands, selectedHistogramCharacteristicsCommands                                                                                                                                                                                                    []string
-		withSpectrum                                                                                                                                                                                                                                                            bool
+		withSpectrum, withStats, withDiagnostics, perChannel, withDifferenceImages, withOverlay                                                                                                                                                                                 bool
 		distanceMetric                                                                                                                                                                                                                                                          int
 	)
 
+	borderMode := manipulations.DefaultBorderPolicy.String()
+	alignment := analysis.AlignNone.String()
+	ssimWindowSize := strconv.Itoa(analysis.DefaultSSIMOptions.WindowSize)
+	ssimSigma := strconv.FormatFloat(analysis.DefaultSSIMOptions.Sigma, 'g', -1, 64)
+	ssimK1 := strconv.FormatFloat(analysis.DefaultSSIMOptions.K1, 'g', -1, 64)
+	ssimK2 := strconv.FormatFloat(analysis.DefaultSSIMOptions.K2, 'g', -1, 64)
+	histogramChannel := manipulations.HistogramValue.String()
+	histogramScale := manipulations.HistogramLinearScale.String()
+	histogramMarkers := "mean,median"
+	histogramExport := "none"
+	percentiles := "1,5,25,75,95,99"
+	grayscaleTolerance := "0"
+	glcmDistance := "1"
+	glcmAngles := []string{"0", "45", "90", "135"}
+	glcmLevels := "8"
+	shapesExport := "csv"
+	connectivity := "8"
+	minArea := "0"
+	morphologyMode := string(morphological.ModeBinary)
+	reconstructionMethod := string(morphological.ReconstructionByDilation)
+	geodesicSteps := "0"
+	extrema := "maxima"
+	hValue := "10"
+	skeletonAlgorithm := string(morphological.SkeletonHitOrMiss)
+	maxIterations := "0"
+	pruneLength := "0"
+	customStructureElement := ""
+	customForegroundStructureElement := ""
+	customBackgroundStructureElement := ""
+	hitOrMissTemplate := ""
+	rotations := "1"
+	structureElementsFile := ""
+	iterations := "1"
+	asfSize := "2"
+	asfOrder := string(morphological.ASFOpenClose)
+	morphologyExpression := ""
+	contourMethod := string(morphological.ContourSuzuki)
+	contourEpsilon := "0"
+	contoursExport := "json"
+	perComponent := true
+	seedStrategy := "none"
+	seedFile := ""
+	markerImagePath := ""
+	seedCount := "16"
+	randomSeed := "1"
+	growthReference := string(morphological.ReferenceSeed)
+	deviations := "2"
+	mergeThreshold := "25"
+	homogeneityPredicate := string(morphological.HomogeneityDeviation)
+	minBlockSize := "2"
+	withCumulative := false
+
 	customKM := huh.NewDefaultKeyMap()
 	customKM.Input.Next = key.NewBinding(key.WithKeys("down", "j"), key.WithHelp("↓/j", "Next field"))
 	customKM.Input.Prev = key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "Previous field"))
@@ -111,7 +164,19 @@ func (m *Model) buildCommandForm() error {
 			Placeholder("Enter maximal size of window size for filter").
 			Value(&maxWindowSize)
 
-		form = huh.NewForm(huh.NewGroup(inputMinWindowSize, inputMaxWindowSize)).WithTheme(huh.ThemeCatppuccin())
+		confirmStats := huh.NewConfirm().
+			Title("Report replaced pixels and window sizes?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withStats)
+
+		confirmDiagnostics := huh.NewConfirm().
+			Title("Save window size heat-map and modified pixels mask?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withDiagnostics)
+
+		form = huh.NewForm(huh.NewGroup(inputMinWindowSize, inputMaxWindowSize, newBorderModeSelect(&borderMode), confirmStats, confirmDiagnostics)).WithTheme(huh.ThemeCatppuccin())
 
 	case "min_filter_denoising":
 
@@ -120,7 +185,7 @@ func (m *Model) buildCommandForm() error {
 			Placeholder("Enter minimal size of window size for filter").
 			Value(&minWindowSize)
 
-		form = huh.NewForm(huh.NewGroup(inputMinWindowSize)).WithTheme(huh.ThemeCatppuccin())
+		form = huh.NewForm(huh.NewGroup(inputMinWindowSize, newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
 
 	case "max_filter_denoising":
 
@@ -129,7 +194,7 @@ func (m *Model) buildCommandForm() error {
 			Placeholder("Enter maximal size of window size for filter").
 			Value(&maxWindowSize)
 
-		form = huh.NewForm(huh.NewGroup(inputMaxWindowSize)).WithTheme(huh.ThemeCatppuccin())
+		form = huh.NewForm(huh.NewGroup(inputMaxWindowSize, newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
 
 	case "img_comparison_commands":
 		wd, _ := os.Getwd()
@@ -146,6 +211,12 @@ func (m *Model) buildCommandForm() error {
 			huh.NewOption("SNR (Signal-to-Noise Ratio)", "SNR"),
 			huh.NewOption("PSNR (Peak Signal-to-Noise Ratio)", "PSNR"),
 			huh.NewOption("MD (Max Difference)", "MD"),
+			huh.NewOption("SSIM (Structural Similarity)", "SSIM"),
+			huh.NewOption("MS-SSIM (Multi-Scale Structural Similarity)", "MS-SSIM"),
+			huh.NewOption("PSNR-RGB (Peak Signal-to-Noise Ratio per channel)", "PSNR-RGB"),
+			huh.NewOption("MAE (Mean Absolute Error)", "MAE"),
+			huh.NewOption("NCC (Normalized Cross-Correlation)", "NCC"),
+			huh.NewOption("CIEDE2000 (Mean Color Difference)", "CIEDE2000"),
 		}
 
 		msComparison := huh.NewMultiSelect[string]().
@@ -153,13 +224,101 @@ func (m *Model) buildCommandForm() error {
 			Options(comparisonOptions...).
 			Value(&selectedComparisonCommands)
 
-		form = huh.NewForm(huh.NewGroup(fpComparison, msComparison)).WithTheme(huh.ThemeCatppuccin())
+		confirmPerChannel := huh.NewConfirm().
+			Title("Report values of every color channel?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&perChannel)
+
+		confirmDifferenceImages := huh.NewConfirm().
+			Title("Save absolute difference image and difference heat-map?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withDifferenceImages)
+
+		selectAlignment := huh.NewSelect[string]().
+			Title("Alignment").
+			Description("How images of different size or with shifted content are matched").
+			Options(huh.NewOptions(analysis.AvailableAlignmentModes()...)...).
+			Value(&alignment)
+
+		ssimWindowInput := huh.NewInput().
+			Title("SSIM window size").
+			Description("Side length of the gaussian window used by SSIM and MS-SSIM").
+			Placeholder("11").
+			Value(&ssimWindowSize).
+			Validate(func(s string) error {
+				if _, err := strconv.Atoi(s); err != nil {
+					return fmt.Errorf("failed to parse window size: %w", err)
+				}
+				return nil
+			})
+
+		ssimSigmaInput := huh.NewInput().
+			Title("SSIM sigma").
+			Description("Standard deviation of the window, negative for a uniform window").
+			Placeholder("1.5").
+			Value(&ssimSigma).
+			Validate(func(s string) error {
+				if _, err := strconv.ParseFloat(s, 64); err != nil {
+					return fmt.Errorf("failed to parse sigma: %w", err)
+				}
+				return nil
+			})
+
+		ssimK1Input := huh.NewInput().
+			Title("SSIM K1").
+			Description("Luminance stabilizing constant").
+			Placeholder("0.01").
+			Value(&ssimK1).
+			Validate(func(s string) error {
+				if _, err := strconv.ParseFloat(s, 64); err != nil {
+					return fmt.Errorf("failed to parse K1: %w", err)
+				}
+				return nil
+			})
+
+		ssimK2Input := huh.NewInput().
+			Title("SSIM K2").
+			Description("Contrast stabilizing constant").
+			Placeholder("0.03").
+			Value(&ssimK2).
+			Validate(func(s string) error {
+				if _, err := strconv.ParseFloat(s, 64); err != nil {
+					return fmt.Errorf("failed to parse K2: %w", err)
+				}
+				return nil
+			})
+
+		form = huh.NewForm(
+			huh.NewGroup(fpComparison, msComparison, confirmPerChannel, confirmDifferenceImages, selectAlignment),
+			huh.NewGroup(ssimWindowInput, ssimSigmaInput, ssimK1Input, ssimK2Input),
+		).WithTheme(huh.ThemeCatppuccin())
 
 	case "generate_img_histogram":
 
-		dummyNote := huh.NewNote().Title("No arguments required for this command")
+		selectScale := huh.NewSelect[string]().
+			Title("Y-axis scale").
+			Options(huh.NewOptions(manipulations.HistogramLinearScale.String(), manipulations.HistogramLogScale.String())...).
+			Value(&histogramScale)
 
-		form = huh.NewForm(huh.NewGroup(dummyNote)).WithTheme(huh.ThemeCatppuccin())
+		confirmCumulative := huh.NewConfirm().
+			Title("Overlay cumulative distribution?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withCumulative)
+
+		inputMarkers := huh.NewInput().
+			Title("Markers").
+			Placeholder("mean,median,p5,p95").
+			Value(&histogramMarkers)
+
+		selectExport := huh.NewSelect[string]().
+			Title("Export raw counts").
+			Options(huh.NewOptions("none", string(analysis.HistogramExportCSV), string(analysis.HistogramExportJSON))...).
+			Value(&histogramExport)
+
+		form = huh.NewForm(huh.NewGroup(newHistogramChannelSelect(&histogramChannel), selectScale, confirmCumulative, inputMarkers, selectExport)).WithTheme(huh.ThemeCatppuccin())
 
 	case "histogram_img_characteristics":
 
@@ -179,7 +338,40 @@ func (m *Model) buildCommandForm() error {
 			Options(characteristicsOptions...).
 			Value(&selectedHistogramCharacteristicsCommands)
 
-		form = huh.NewForm(huh.NewGroup(msCharacteristics)).WithTheme(huh.ThemeCatppuccin())
+		form = huh.NewForm(huh.NewGroup(msCharacteristics, newHistogramChannelSelect(&histogramChannel))).WithTheme(huh.ThemeCatppuccin())
+
+	case "img_statistics":
+
+		inputPercentiles := huh.NewInput().
+			Title("Percentiles to report").
+			Placeholder("1,5,25,75,95,99").
+			Value(&percentiles)
+
+		inputTolerance := huh.NewInput().
+			Title("Largest channel spread of a pixel that still counts as gray").
+			Placeholder("0").
+			Value(&grayscaleTolerance)
+
+		form = huh.NewForm(huh.NewGroup(inputPercentiles, inputTolerance)).WithTheme(huh.ThemeCatppuccin())
+
+	case "texture_features":
+
+		inputDistance := huh.NewInput().
+			Title("Neighbour distance in pixels").
+			Placeholder("1").
+			Value(&glcmDistance)
+
+		msAngles := huh.NewMultiSelect[string]().
+			Title("Angles").
+			Options(huh.NewOptions("0", "45", "90", "135")...).
+			Value(&glcmAngles)
+
+		inputLevels := huh.NewInput().
+			Title("Number of gray levels (2 to 256)").
+			Placeholder("8").
+			Value(&glcmLevels)
+
+		form = huh.NewForm(huh.NewGroup(inputDistance, msAngles, inputLevels)).WithTheme(huh.ThemeCatppuccin())
 
 	case "rayleigh_transform":
 
@@ -200,11 +392,144 @@ func (m *Model) buildCommandForm() error {
 
 		form = huh.NewForm(huh.NewGroup(inputMinBrightness, inputMaxBrightness, inputAlpha)).WithTheme(huh.ThemeCatppuccin())
 
-	case "kirsh_edge_detection", "thinning":
+	case "kirsh_edge_detection":
 
-		dummyNote := huh.NewNote().Title("No arguments required for this command")
+		form = huh.NewForm(huh.NewGroup(newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
 
-		form = huh.NewForm(huh.NewGroup(dummyNote)).WithTheme(huh.ThemeCatppuccin())
+	case "thinning":
+
+		selectAlgorithm := huh.NewSelect[string]().
+			Title("Algorithm").
+			Options(huh.NewOptions(
+				string(morphological.SkeletonHitOrMiss),
+				string(morphological.SkeletonZhangSuen),
+				string(morphological.SkeletonGuoHall),
+				string(morphological.SkeletonMedialAxis),
+			)...).
+			Value(&skeletonAlgorithm)
+
+		inputMaxIterations := huh.NewInput().
+			Title("Maximum iterations, 0 runs until no pixel changes").
+			Placeholder("0").
+			Value(&maxIterations)
+
+		inputPruneLength := huh.NewInput().
+			Title("Prune spurs up to this length, 0 keeps them").
+			Placeholder("0").
+			Value(&pruneLength)
+
+		form = huh.NewForm(huh.NewGroup(selectAlgorithm, inputMaxIterations, inputPruneLength, newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
+
+	case "convex_hull":
+
+		confirmPerComponent := huh.NewConfirm().
+			Title("Fill the hull of every component on its own?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&perComponent)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		inputMaxIterations := huh.NewInput().
+			Title("Maximum iterations, 0 runs until no pixel changes").
+			Placeholder("0").
+			Value(&maxIterations)
+
+		form = huh.NewForm(huh.NewGroup(confirmPerComponent, selectConnectivity, inputMaxIterations)).WithTheme(huh.ThemeCatppuccin())
+
+	case "thickening":
+
+		inputMaxIterations := huh.NewInput().
+			Title("Maximum iterations, 0 runs until no pixel changes").
+			Placeholder("0").
+			Value(&maxIterations)
+
+		form = huh.NewForm(huh.NewGroup(inputMaxIterations)).WithTheme(huh.ThemeCatppuccin())
+
+	case "pruning":
+
+		pruneLength = "3"
+		inputPruneLength := huh.NewInput().
+			Title("Longest spur to remove in pixels").
+			Placeholder("3").
+			Value(&pruneLength)
+
+		form = huh.NewForm(huh.NewGroup(inputPruneLength)).WithTheme(huh.ThemeCatppuccin())
+
+	case "component_labeling":
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		inputMinArea := huh.NewInput().
+			Title("Minimum component area in pixels").
+			Placeholder("0").
+			Value(&minArea)
+
+		form = huh.NewForm(huh.NewGroup(selectConnectivity, inputMinArea)).WithTheme(huh.ThemeCatppuccin())
+
+	case "shape_descriptors":
+
+		selectShapesExport := huh.NewSelect[string]().
+			Title("Table format").
+			Options(huh.NewOptions("csv", "json")...).
+			Value(&shapesExport)
+
+		confirmOverlay := huh.NewConfirm().
+			Title("Save an annotated overlay?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withOverlay)
+
+		form = huh.NewForm(huh.NewGroup(selectShapesExport, confirmOverlay)).WithTheme(huh.ThemeCatppuccin())
+
+	case "boundary_extraction":
+
+		availableStructuringElements, err := morphological.GetAvailableStructureElementsNames()
+		if err != nil {
+			return fmt.Errorf("failed to get available structuring elements: %w", err)
+		}
+
+		structureElementName = "iii"
+		selectSE := huh.NewSelect[string]().
+			Title("Structuring Element Name, iii gives a 4-connected and iv an 8-connected boundary").
+			Options(huh.NewOptions(availableStructuringElements...)...).
+			Value(&structureElementName)
+
+		form = huh.NewForm(huh.NewGroup(selectSE, newCustomStructureElementInput(&customStructureElement), newStructureElementsFileInput(&structureElementsFile))).WithTheme(huh.ThemeCatppuccin())
+
+	case "contour_tracing":
+
+		selectMethod := huh.NewSelect[string]().
+			Title("Method").
+			Options(
+				huh.NewOption("Suzuki, outer and hole borders", string(morphological.ContourSuzuki)),
+				huh.NewOption("Moore, outer borders only", string(morphological.ContourMoore)),
+			).
+			Value(&contourMethod)
+
+		inputEpsilon := huh.NewInput().
+			Title("Polygon approximation epsilon in pixels, 0 keeps every point").
+			Placeholder("0").
+			Value(&contourEpsilon)
+
+		selectContoursExport := huh.NewSelect[string]().
+			Title("Export format").
+			Options(huh.NewOptions("json", "svg")...).
+			Value(&contoursExport)
+
+		confirmOverlay := huh.NewConfirm().
+			Title("Save an overlay with the contours?").
+			Affirmative("Yes").
+			Negative("No").
+			Value(&withOverlay)
+
+		form = huh.NewForm(huh.NewGroup(selectMethod, inputEpsilon, selectContoursExport, confirmOverlay)).WithTheme(huh.ThemeCatppuccin())
 
 	case "mask_edge_sharpening":
 
@@ -220,9 +545,9 @@ func (m *Model) buildCommandForm() error {
 			Options(maskOptions...).
 			Value(&maskName)
 
-		form = huh.NewForm(huh.NewGroup(selectMask)).WithTheme(huh.ThemeCatppuccin())
+		form = huh.NewForm(huh.NewGroup(selectMask, newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
 
-	case "dilation", "erosion", "opening", "closing":
+	case "dilation", "erosion", "opening", "closing", "morphological_gradient", "white_top_hat", "black_top_hat":
 
 		availableStructuringElements, err := morphological.GetAvailableStructureElementsNames()
 		if err != nil {
@@ -236,7 +561,156 @@ func (m *Model) buildCommandForm() error {
 			Options(seOptions...).
 			Value(&structureElementName)
 
-		form = huh.NewForm(huh.NewGroup(selectSE)).WithTheme(huh.ThemeCatppuccin())
+		selectMode := huh.NewSelect[string]().
+			Title("Mode").
+			Options(huh.NewOptions(string(morphological.ModeBinary), string(morphological.ModeGray))...).
+			Value(&morphologyMode)
+
+		inputIterations := huh.NewInput().
+			Title("Iterations, every dilation and erosion is repeated this many times").
+			Placeholder("1").
+			Value(&iterations)
+
+		form = huh.NewForm(huh.NewGroup(selectSE, newCustomStructureElementInput(&customStructureElement), newStructureElementsFileInput(&structureElementsFile), selectMode, inputIterations, newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
+
+	case "alternating_sequential_filter":
+
+		availableStructuringElements, err := morphological.GetAvailableStructureElementsNames()
+		if err != nil {
+			return fmt.Errorf("failed to get available structuring elements: %w", err)
+		}
+
+		selectSE := huh.NewSelect[string]().
+			Title("Structuring Element Name").
+			Options(huh.NewOptions(availableStructuringElements...)...).
+			Value(&structureElementName)
+
+		inputSize := huh.NewInput().
+			Title("Size, the number of opening and closing pairs of growing size").
+			Placeholder("2").
+			Value(&asfSize)
+
+		selectOrder := huh.NewSelect[string]().
+			Title("Order").
+			Options(
+				huh.NewOption("Open then close", string(morphological.ASFOpenClose)),
+				huh.NewOption("Close then open", string(morphological.ASFCloseOpen)),
+			).
+			Value(&asfOrder)
+
+		selectMode := huh.NewSelect[string]().
+			Title("Mode").
+			Options(huh.NewOptions(string(morphological.ModeBinary), string(morphological.ModeGray))...).
+			Value(&morphologyMode)
+
+		form = huh.NewForm(huh.NewGroup(selectSE, newCustomStructureElementInput(&customStructureElement), newStructureElementsFileInput(&structureElementsFile), inputSize, selectOrder, selectMode)).WithTheme(huh.ThemeCatppuccin())
+
+	case "morphology_expression":
+
+		inputExpression := huh.NewInput().
+			Title("Expression, e.g. close(disk3) | open(iv) - erode(iii)").
+			Placeholder("close(disk3) | open(iv) - erode(iii)").
+			Validate(func(s string) error {
+				if strings.TrimSpace(structureElementsFile) != "" {
+					return nil
+				}
+				_, err := morphological.ParseExpression(s)
+				return err
+			}).
+			Value(&morphologyExpression)
+
+		form = huh.NewForm(huh.NewGroup(newStructureElementsFileInput(&structureElementsFile), inputExpression)).WithTheme(huh.ThemeCatppuccin())
+
+	case "reconstruction":
+		wd, _ := os.Getwd()
+
+		fpMarker := huh.NewFilePicker().
+			Title("Select marker image").
+			AllowedTypes([]string{".bmp"}).
+			Value(&comparisonImagePath).
+			CurrentDirectory(wd)
+
+		selectMethod := huh.NewSelect[string]().
+			Title("Method").
+			Options(huh.NewOptions(string(morphological.ReconstructionByDilation), string(morphological.ReconstructionByErosion))...).
+			Value(&reconstructionMethod)
+
+		inputSteps := huh.NewInput().
+			Title("Geodesic steps, 0 runs until stability").
+			Placeholder("0").
+			Value(&geodesicSteps)
+
+		selectMode := huh.NewSelect[string]().
+			Title("Mode").
+			Options(huh.NewOptions(string(morphological.ModeBinary), string(morphological.ModeGray))...).
+			Value(&morphologyMode)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(huh.NewGroup(fpMarker, selectMethod, inputSteps, selectMode, selectConnectivity)).WithTheme(huh.ThemeCatppuccin())
+
+	case "fill_holes", "clear_border":
+
+		inputMarker := huh.NewInput().
+			Title("Marker image, optional").
+			Placeholder("leave empty to use the image border").
+			Value(&markerImagePath)
+
+		selectMode := huh.NewSelect[string]().
+			Title("Mode").
+			Options(huh.NewOptions(string(morphological.ModeBinary), string(morphological.ModeGray))...).
+			Value(&morphologyMode)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(huh.NewGroup(inputMarker, selectMode, selectConnectivity)).WithTheme(huh.ThemeCatppuccin())
+
+	case "h_extrema":
+
+		selectExtrema := huh.NewSelect[string]().
+			Title("Extrema").
+			Options(huh.NewOptions("maxima", "minima")...).
+			Value(&extrema)
+
+		inputH := huh.NewInput().
+			Title("h (0-255)").
+			Placeholder("10").
+			Value(&hValue)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(huh.NewGroup(selectExtrema, inputH, selectConnectivity)).WithTheme(huh.ThemeCatppuccin())
+
+	case "regional_maxima":
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(huh.NewGroup(selectConnectivity)).WithTheme(huh.ThemeCatppuccin())
+
+	case "distance_transform":
+
+		distanceMetricSelect := huh.NewSelect[int]().
+			Title("Distance metric").
+			Options(
+				huh.NewOption("Euclidean", int(morphological.Euclidean)),
+				huh.NewOption("City-block", int(morphological.Manhattan)),
+				huh.NewOption("Chessboard", int(morphological.Chebyshev)),
+			).
+			Value(&distanceMetric)
+
+		form = huh.NewForm(huh.NewGroup(distanceMetricSelect)).WithTheme(huh.ThemeCatppuccin())
 
 	case "hit_or_miss":
 
@@ -256,16 +730,82 @@ func (m *Model) buildCommandForm() error {
 			Options(seOptions...).
 			Value(&backgroundStructureElementName)
 
-		form = huh.NewForm(huh.NewGroup(selectForegroundSE, selectBackgroundSE)).WithTheme(huh.ThemeCatppuccin())
+		customForegroundSE := huh.NewInput().
+			Title("Custom Foreground Structuring Element").
+			Description("Inline matrix such as 1,1,1;x,1,x;x,x,x, path to a JSON file or shape, replaces the selected one, leave empty to skip").
+			Value(&customForegroundStructureElement).
+			Validate(validateCustomHitOrMissElement)
+
+		customBackgroundSE := huh.NewInput().
+			Title("Custom Background Structuring Element").
+			Description("Inline matrix, path to a JSON file or shape, replaces the selected one, leave empty to skip").
+			Value(&customBackgroundStructureElement).
+			Validate(validateCustomHitOrMissElement)
+
+		templateInput := huh.NewInput().
+			Title("Template").
+			Description("1 has to be foreground, 0 background and x does not matter, e.g. x,1,x;0,1,0;0,0,0, replaces both elements, leave empty to skip").
+			Value(&hitOrMissTemplate).
+			Validate(func(s string) error {
+				if strings.TrimSpace(s) == "" {
+					return nil
+				}
+				_, err := morphological.ParseHitOrMissTemplate(s)
+				return err
+			})
+
+		selectRotations := huh.NewSelect[string]().
+			Title("Rotations").
+			Description("Also match the elements turned in steps of 90 (4) or 45 (8) degrees").
+			Options(huh.NewOptions("1", "4", "8")...).
+			Value(&rotations)
+
+		form = huh.NewForm(huh.NewGroup(selectForegroundSE, customForegroundSE, selectBackgroundSE, customBackgroundSE, templateInput, selectRotations, newStructureElementsFileInput(&structureElementsFile), newBorderModeSelect(&borderMode))).WithTheme(huh.ThemeCatppuccin())
 
 	case "region_grow":
 
-		//TODO enhance this part of adding seed points
 		seedPointInput := huh.NewInput().
-			Title("Seed points").
+			Title("Seed points, optional with a seed file or automatic seeding").
 			Placeholder("Enter seeds as [x,y][x,y]...").
 			Value(&seedPointsStr)
 
+		seedFileInput := huh.NewInput().
+			Title("Seed file, optional").
+			Placeholder("JSON array of {\"x\":..,\"y\":..} or [x,y], or [x,y] text").
+			Value(&seedFile)
+
+		seedStrategyOptions := []huh.Option[string]{huh.NewOption("None", "none")}
+		for _, strategy := range morphological.SeedStrategies {
+			seedStrategyOptions = append(seedStrategyOptions, huh.NewOption(string(strategy), string(strategy)))
+		}
+
+		seedStrategySelect := huh.NewSelect[string]().
+			Title("Automatic seeding").
+			Options(seedStrategyOptions...).
+			Value(&seedStrategy)
+
+		seedCountInput := huh.NewInput().
+			Title("Number of automatic seeds").
+			Placeholder("16").
+			Value(&seedCount).
+			Validate(func(s string) error {
+				if _, err := strconv.Atoi(s); err != nil {
+					return fmt.Errorf("failed to parse seed count: %w", err)
+				}
+				return nil
+			})
+
+		randomSeedInput := huh.NewInput().
+			Title("Random generator seed").
+			Placeholder("1").
+			Value(&randomSeed).
+			Validate(func(s string) error {
+				if _, err := strconv.Atoi(s); err != nil {
+					return fmt.Errorf("failed to parse random seed: %w", err)
+				}
+				return nil
+			})
+
 		distanceMetricOptions := []huh.Option[int]{
 			huh.NewOption("Euclidean (0)", 0),
 			huh.NewOption("Manhattan (1)", 1),
@@ -292,7 +832,142 @@ func (m *Model) buildCommandForm() error {
 				return nil
 			})
 
-		form = huh.NewForm(huh.NewGroup(seedPointInput, distanceMetricSelect, thresholdInput)).WithTheme(huh.ThemeCatppuccin())
+		growthReferenceSelect := huh.NewSelect[string]().
+			Title("Compare neighbours to").
+			Options(
+				huh.NewOption("Seed value", string(morphological.ReferenceSeed)),
+				huh.NewOption("Running region mean", string(morphological.ReferenceMean)),
+				huh.NewOption("Region mean and deviation", string(morphological.ReferenceStatistics)),
+			).
+			Value(&growthReference)
+
+		deviationsInput := huh.NewInput().
+			Title("Standard deviations added to the threshold, used with the region deviation").
+			Placeholder("2").
+			Value(&deviations).
+			Validate(func(s string) error {
+				d, err := strconv.ParseFloat(s, 64)
+				if err != nil {
+					return fmt.Errorf("failed to parse deviations: %w", err)
+				}
+				if d < 0 {
+					return fmt.Errorf("deviations must not be negative")
+				}
+				return nil
+			})
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(
+			huh.NewGroup(seedPointInput, seedFileInput, seedStrategySelect, seedCountInput, randomSeedInput),
+			huh.NewGroup(distanceMetricSelect, thresholdInput, growthReferenceSelect, deviationsInput, selectConnectivity),
+		).WithTheme(huh.ThemeCatppuccin())
+
+	case "region_merge":
+
+		seedStrategy = string(morphological.SeedGrid)
+		seedCount = "256"
+		thresholdStr = "15"
+
+		seedStrategyOptions := []huh.Option[string]{}
+		for _, strategy := range morphological.SeedStrategies {
+			seedStrategyOptions = append(seedStrategyOptions, huh.NewOption(string(strategy), string(strategy)))
+		}
+
+		seedStrategySelect := huh.NewSelect[string]().
+			Title("Seeding of the grown regions").
+			Options(seedStrategyOptions...).
+			Value(&seedStrategy)
+
+		seedCountInput := huh.NewInput().
+			Title("Number of seeds").
+			Placeholder("256").
+			Value(&seedCount)
+
+		randomSeedInput := huh.NewInput().
+			Title("Random generator seed").
+			Placeholder("1").
+			Value(&randomSeed)
+
+		distanceMetricSelect := huh.NewSelect[int]().
+			Title("Distance metric").
+			Options(
+				huh.NewOption("Euclidean (0)", 0),
+				huh.NewOption("Manhattan (1)", 1),
+				huh.NewOption("Chebyshev (2)", 2),
+			).
+			Value(&distanceMetric)
+
+		thresholdInput := huh.NewInput().
+			Title("Growing threshold").
+			Placeholder("15").
+			Value(&thresholdStr)
+
+		mergeThresholdInput := huh.NewInput().
+			Title("Largest distance between the means of merged regions").
+			Placeholder("25").
+			Value(&mergeThreshold)
+
+		inputMinArea := huh.NewInput().
+			Title("Merge regions smaller than").
+			Placeholder("0").
+			Value(&minArea)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(
+			huh.NewGroup(seedStrategySelect, seedCountInput, randomSeedInput),
+			huh.NewGroup(distanceMetricSelect, thresholdInput, mergeThresholdInput, inputMinArea, selectConnectivity),
+		).WithTheme(huh.ThemeCatppuccin())
+
+	case "split_and_merge":
+
+		thresholdStr = "10"
+
+		predicateSelect := huh.NewSelect[string]().
+			Title("Homogeneity predicate").
+			Options(
+				huh.NewOption("Standard deviation", string(morphological.HomogeneityDeviation)),
+				huh.NewOption("Value range", string(morphological.HomogeneityRange)),
+			).
+			Value(&homogeneityPredicate)
+
+		thresholdInput := huh.NewInput().
+			Title("Largest deviation or range of a homogeneous region").
+			Placeholder("10").
+			Value(&thresholdStr)
+
+		inputMinBlockSize := huh.NewInput().
+			Title("Smallest block side that is split").
+			Placeholder("2").
+			Value(&minBlockSize)
+
+		inputMinArea := huh.NewInput().
+			Title("Merge regions smaller than").
+			Placeholder("0").
+			Value(&minArea)
+
+		distanceMetricSelect := huh.NewSelect[int]().
+			Title("Distance metric").
+			Options(
+				huh.NewOption("Euclidean (0)", 0),
+				huh.NewOption("Manhattan (1)", 1),
+				huh.NewOption("Chebyshev (2)", 2),
+			).
+			Value(&distanceMetric)
+
+		selectConnectivity := huh.NewSelect[string]().
+			Title("Connectivity").
+			Options(huh.NewOptions("8", "4")...).
+			Value(&connectivity)
+
+		form = huh.NewForm(huh.NewGroup(predicateSelect, thresholdInput, inputMinBlockSize, inputMinArea, distanceMetricSelect, selectConnectivity)).WithTheme(huh.ThemeCatppuccin())
 
 	case "bandpass", "bandcut":
 
@@ -392,37 +1067,178 @@ func (m *Model) buildCommandForm() error {
 		case "adaptive_filter_denoising":
 			args["minWindowSize"] = minWindowSize
 			args["maxWindowSize"] = maxWindowSize
+			args["borderMode"] = borderMode
+			args["withStats"] = strconv.FormatBool(withStats)
+			args["withDiagnostics"] = strconv.FormatBool(withDiagnostics)
 		case "min_filter_denoising":
 			args["minWindowSize"] = minWindowSize
+			args["borderMode"] = borderMode
 		case "max_filter_denoising":
 			args["maxWindowSize"] = maxWindowSize
+			args["borderMode"] = borderMode
 		case "img_comparison_commands":
 			args["comparisonImagePath"] = comparisonImagePath
 			// i love this totally not hacky type safe solution
 			args["selectedComparisonCommands"] = strings.Join(selectedComparisonCommands, "|")
+			args["perChannel"] = strconv.FormatBool(perChannel)
+			args["withDifferenceImages"] = strconv.FormatBool(withDifferenceImages)
+			args["alignment"] = alignment
+			args["ssimWindowSize"] = ssimWindowSize
+			args["ssimSigma"] = ssimSigma
+			args["ssimK1"] = ssimK1
+			args["ssimK2"] = ssimK2
 		case "generate_img_histogram":
-			args["dummy"] = "dummy"
+			args["histogramChannel"] = histogramChannel
+			args["histogramScale"] = histogramScale
+			args["withCumulative"] = strconv.FormatBool(withCumulative)
+			args["histogramMarkers"] = histogramMarkers
+			args["histogramExport"] = histogramExport
 		case "histogram_img_characteristics":
 			args["selectedHistogramCharacteristicsCommands"] = strings.Join(selectedHistogramCharacteristicsCommands, "|")
+			args["histogramChannel"] = histogramChannel
+		case "img_statistics":
+			args["percentiles"] = percentiles
+			args["grayscaleTolerance"] = grayscaleTolerance
+		case "texture_features":
+			args["glcmDistance"] = glcmDistance
+			args["glcmAngles"] = strings.Join(glcmAngles, ",")
+			args["glcmLevels"] = glcmLevels
 		case "rayleigh_transform":
 			args["lowCut"] = lowCut
 			args["highCut"] = highCut
 			args["alpha"] = alpha
 		case "mask_edge_sharpening":
 			args["maskName"] = maskName
+			args["borderMode"] = borderMode
 		case "kirsh_edge_detection":
-			args["dummy"] = "dummy"
-		case "dilation", "erosion", "opening", "closing":
+			args["borderMode"] = borderMode
+		case "dilation", "erosion", "opening", "closing", "morphological_gradient", "white_top_hat", "black_top_hat":
+			args["structureElementName"] = structureElementName
+			if strings.TrimSpace(customStructureElement) != "" {
+				args["structureElementName"] = customStructureElement
+			}
+			if strings.TrimSpace(structureElementsFile) != "" {
+				args["structureElementsFile"] = structureElementsFile
+			}
+			args["morphologyMode"] = morphologyMode
+			args["iterations"] = iterations
+			args["borderMode"] = borderMode
+		case "alternating_sequential_filter":
 			args["structureElementName"] = structureElementName
+			if strings.TrimSpace(customStructureElement) != "" {
+				args["structureElementName"] = customStructureElement
+			}
+			if strings.TrimSpace(structureElementsFile) != "" {
+				args["structureElementsFile"] = structureElementsFile
+			}
+			args["asfSize"] = asfSize
+			args["asfOrder"] = asfOrder
+			args["morphologyMode"] = morphologyMode
+		case "morphology_expression":
+			args["expression"] = morphologyExpression
+			if strings.TrimSpace(structureElementsFile) != "" {
+				args["structureElementsFile"] = structureElementsFile
+			}
+		case "reconstruction":
+			args["markerImagePath"] = comparisonImagePath
+			args["reconstructionMethod"] = reconstructionMethod
+			args["geodesicSteps"] = geodesicSteps
+			args["morphologyMode"] = morphologyMode
+			args["connectivity"] = connectivity
+		case "fill_holes", "clear_border":
+			if strings.TrimSpace(markerImagePath) != "" {
+				args["markerImagePath"] = markerImagePath
+			}
+			args["morphologyMode"] = morphologyMode
+			args["connectivity"] = connectivity
+		case "h_extrema":
+			args["extrema"] = extrema
+			args["hValue"] = hValue
+			args["connectivity"] = connectivity
+		case "regional_maxima":
+			args["connectivity"] = connectivity
+		case "distance_transform":
+			args["distanceMetric"] = strconv.Itoa(distanceMetric)
 		case "hit_or_miss":
 			args["foregroundStructureElementName"] = foregroundStructureElementName
+			if strings.TrimSpace(customForegroundStructureElement) != "" {
+				args["foregroundStructureElementName"] = customForegroundStructureElement
+			}
 			args["backgroundStructureElementName"] = backgroundStructureElementName
+			if strings.TrimSpace(customBackgroundStructureElement) != "" {
+				args["backgroundStructureElementName"] = customBackgroundStructureElement
+			}
+			if strings.TrimSpace(hitOrMissTemplate) != "" {
+				args["hitOrMissTemplate"] = hitOrMissTemplate
+			}
+			args["rotations"] = rotations
+			if strings.TrimSpace(structureElementsFile) != "" {
+				args["structureElementsFile"] = structureElementsFile
+			}
+			args["borderMode"] = borderMode
 		case "thinning":
-			args["dummy"] = "dummy"
+			args["borderMode"] = borderMode
+			args["skeletonAlgorithm"] = skeletonAlgorithm
+			args["maxIterations"] = maxIterations
+			args["pruneLength"] = pruneLength
+		case "convex_hull":
+			args["perComponent"] = strconv.FormatBool(perComponent)
+			args["connectivity"] = connectivity
+			args["maxIterations"] = maxIterations
+		case "thickening":
+			args["maxIterations"] = maxIterations
+		case "pruning":
+			args["pruneLength"] = pruneLength
+		case "component_labeling":
+			args["connectivity"] = connectivity
+			args["minArea"] = minArea
+		case "shape_descriptors":
+			args["shapesExport"] = shapesExport
+			args["withOverlay"] = strconv.FormatBool(withOverlay)
+		case "boundary_extraction":
+			args["structureElementName"] = structureElementName
+			if strings.TrimSpace(customStructureElement) != "" {
+				args["structureElementName"] = customStructureElement
+			}
+			if strings.TrimSpace(structureElementsFile) != "" {
+				args["structureElementsFile"] = structureElementsFile
+			}
+		case "contour_tracing":
+			args["contourMethod"] = contourMethod
+			args["contourEpsilon"] = contourEpsilon
+			args["contoursExport"] = contoursExport
+			args["withOverlay"] = strconv.FormatBool(withOverlay)
 		case "region_grow":
 			args["seedPoints"] = seedPointsStr
+			if seedFile != "" {
+				args["seedFile"] = seedFile
+			}
+			if seedStrategy != "none" {
+				args["seedStrategy"] = seedStrategy
+				args["seedCount"] = seedCount
+				args["randomSeed"] = randomSeed
+			}
 			args["distanceMetric"] = strconv.Itoa(distanceMetric)
 			args["threshold"] = thresholdStr
+			args["growthReference"] = growthReference
+			args["deviations"] = deviations
+			args["connectivity"] = connectivity
+		case "region_merge":
+			args["seedStrategy"] = seedStrategy
+			args["seedCount"] = seedCount
+			args["randomSeed"] = randomSeed
+			args["distanceMetric"] = strconv.Itoa(distanceMetric)
+			args["threshold"] = thresholdStr
+			args["mergeThreshold"] = mergeThreshold
+			args["minArea"] = minArea
+			args["connectivity"] = connectivity
+		case "split_and_merge":
+			args["homogeneityPredicate"] = homogeneityPredicate
+			args["threshold"] = thresholdStr
+			args["minBlockSize"] = minBlockSize
+			args["minArea"] = minArea
+			args["distanceMetric"] = strconv.Itoa(distanceMetric)
+			args["connectivity"] = connectivity
 		case "bandpass", "bandcut":
 			args["lowCut"] = lowCut
 			args["highCut"] = highCut
@@ -442,3 +1258,54 @@ func (m *Model) buildCommandForm() error {
 
 	return nil
 }
+
+func newBorderModeSelect(borderMode *string) *huh.Select[string] {
+	return huh.NewSelect[string]().
+		Title("Border handling").
+		Description("How pixels outside of the image are treated").
+		Options(huh.NewOptions(manipulations.AvailableBorderModes()...)...).
+		Value(borderMode)
+}
+
+func newHistogramChannelSelect(histogramChannel *string) *huh.Select[string] {
+	return huh.NewSelect[string]().
+		Title("Channel").
+		Description("v is the HSV value, all also saves an overlaid RGB histogram").
+		Options(huh.NewOptions(manipulations.AvailableHistogramChannels()...)...).
+		Value(histogramChannel)
+}
+
+func newCustomStructureElementInput(customStructureElement *string) *huh.Input {
+	return huh.NewInput().
+		Title("Custom Structuring Element").
+		Description("disk:5, diamond:4, rect:3x9, line:7:45 or a name from the elements file, replaces the selected one, leave empty to skip").
+		Value(customStructureElement).
+		Validate(func(s string) error {
+			if !morphological.IsStructureElementSpec(s) {
+				return nil
+			}
+			_, err := morphological.ParseStructureElement(s)
+			return err
+		})
+}
+
+func newStructureElementsFileInput(structureElementsFile *string) *huh.Input {
+	return huh.NewInput().
+		Title("Structuring Elements File").
+		Description("JSON file with additional elements, their names can be typed as a custom element, leave empty to skip").
+		Value(structureElementsFile)
+}
+
+// validateCustomHitOrMissElement checks inline matrices and shapes, names and files are resolved when the command runs.
+func validateCustomHitOrMissElement(s string) error {
+	switch {
+	case morphological.IsInlineStructureElement(s):
+		_, err := morphological.ParseInlineStructureElement(s)
+		return err
+	case morphological.IsStructureElementSpec(s):
+		_, err := morphological.ParseStructureElement(s)
+		return err
+	default:
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"imagio/analysis"
 	"strings"
 
@@ -38,20 +39,19 @@ func (m Model) renderComparisonResults(entries []analysis.CharacteristicsEntry)
 
 	var rows []string
 	for _, entry := range entries {
-		parts := strings.Split(entry.Result, ":")
-		var resultDisplay string
-		if len(parts) > 1 {
-			resultDisplay = strings.TrimSpace(parts[1])
-		} else {
-			resultDisplay = entry.Result
-		}
+		resultDisplay := entry.FormatValue()
 
 		img2 := entry.Img2Name
 		if strings.TrimSpace(img2) == "" {
 			img2 = "N/A"
 		}
 
-		metricCell := lipgloss.NewStyle().Width(metricWidth).MaxWidth(metricWidth).Render(entry.MetricMethod)
+		metric := entry.MetricMethod
+		if entry.Channel != analysis.ChannelCombined {
+			metric = fmt.Sprintf("%s [%s]", metric, entry.Channel)
+		}
+
+		metricCell := lipgloss.NewStyle().Width(metricWidth).MaxWidth(metricWidth).Render(metric)
 		resultCell := lipgloss.NewStyle().Width(resultWidth).MaxWidth(resultWidth).Render(resultDisplay)
 		img1Cell := lipgloss.NewStyle().Width(imgWidth).MaxWidth(imgWidth).Render(entry.Img1Name)
 		img2Cell := lipgloss.NewStyle().Width(imgWidth).MaxWidth(imgWidth).Render(img2)
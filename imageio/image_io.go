@@ -44,13 +44,21 @@ func OpenBmpImage(imagePath string) (image.Image, error) {
 	return img, nil
 }
 
-func SaveBmpImage(img *image.RGBA, filename string) error {
-	outputDir := "output"
+const outputDir = "output"
+
+func ensureOutputDir() error {
 	if _, err := os.Stat(outputDir); os.IsNotExist(err) {
 		if err := os.Mkdir(outputDir, os.ModePerm); err != nil {
 			return fmt.Errorf("error creating output directory: %v", err)
 		}
 	}
+	return nil
+}
+
+func SaveBmpImage(img *image.RGBA, filename string) error {
+	if err := ensureOutputDir(); err != nil {
+		return err
+	}
 
 	fullPath := filepath.Join(outputDir, filename)
 
@@ -68,6 +76,19 @@ func SaveBmpImage(img *image.RGBA, filename string) error {
 	return nil
 }
 
+// SaveDataFile writes non-image results, e.g. exported histograms, into the output directory next to the images.
+func SaveDataFile(data []byte, filename string) error {
+	if err := ensureOutputDir(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(outputDir, filename), data, 0644); err != nil {
+		return fmt.Errorf("error writing data file: %v", err)
+	}
+
+	return nil
+}
+
 func LoadMonochromeBMP(filePath string) (image.Image, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
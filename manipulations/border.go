@@ -0,0 +1,161 @@
+package manipulations
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// BorderMode describes how window-based operations treat pixels that fall outside of the image.
+type BorderMode int
+
+const (
+	// BorderSkip ignores out-of-range pixels, so windows shrink near the edges
+	// and operations that need a full window leave edge pixels unchanged.
+	BorderSkip BorderMode = iota
+	// BorderClamp replicates the nearest edge pixel (aaa|abcd|ddd).
+	BorderClamp
+	// BorderReflect mirrors the image including the edge pixel (cba|abcd|dcb).
+	BorderReflect
+	// BorderWrap tiles the image periodically (bcd|abcd|abc).
+	BorderWrap
+	// BorderConstant pads the image with a fixed value.
+	BorderConstant
+)
+
+var borderModeNames = map[BorderMode]string{
+	BorderSkip:     "skip",
+	BorderClamp:    "clamp",
+	BorderReflect:  "reflect",
+	BorderWrap:     "wrap",
+	BorderConstant: "constant",
+}
+
+func (m BorderMode) String() string {
+	if name, ok := borderModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("BorderMode(%d)", int(m))
+}
+
+// BorderPolicy is a border mode together with the padding value used by BorderConstant.
+type BorderPolicy struct {
+	Mode  BorderMode
+	Value uint8
+}
+
+// DefaultBorderPolicy keeps the historical behavior of every window-based operation.
+var DefaultBorderPolicy = BorderPolicy{Mode: BorderSkip}
+
+// AvailableBorderModes returns the names accepted by ParseBorderPolicy in a stable order.
+func AvailableBorderModes() []string {
+	return []string{"skip", "clamp", "reflect", "wrap", "constant"}
+}
+
+// ParseBorderPolicy parses a border policy from its textual form.
+//
+// Accepted values are "skip", "clamp" (alias "replicate"), "reflect", "wrap"
+// and "constant" optionally followed by a padding value, e.g. "constant:128".
+// An empty string yields DefaultBorderPolicy.
+func ParseBorderPolicy(value string) (BorderPolicy, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return DefaultBorderPolicy, nil
+	}
+
+	name, param, hasParam := strings.Cut(value, ":")
+
+	switch name {
+	case "skip":
+		return BorderPolicy{Mode: BorderSkip}, nil
+	case "clamp", "replicate":
+		return BorderPolicy{Mode: BorderClamp}, nil
+	case "reflect", "mirror":
+		return BorderPolicy{Mode: BorderReflect}, nil
+	case "wrap":
+		return BorderPolicy{Mode: BorderWrap}, nil
+	case "constant":
+		if !hasParam {
+			return BorderPolicy{Mode: BorderConstant}, nil
+		}
+
+		constant, err := strconv.Atoi(param)
+		if err != nil || constant < 0 || constant > 255 {
+			return BorderPolicy{}, fmt.Errorf("constant border value must be an int in range [0, 255], got %q", param)
+		}
+
+		return BorderPolicy{Mode: BorderConstant, Value: uint8(constant)}, nil
+	}
+
+	return BorderPolicy{}, fmt.Errorf("unknown border mode %q, expected one of: %s", value, strings.Join(AvailableBorderModes(), ", "))
+}
+
+func (p BorderPolicy) String() string {
+	if p.Mode == BorderConstant {
+		return fmt.Sprintf("constant:%d", p.Value)
+	}
+	return p.Mode.String()
+}
+
+// ResolveCoordinate maps a coordinate into the half-open range [lo, hi).
+//
+// The second return value is false when the coordinate lies outside of the range
+// and the policy does not map it onto an existing pixel (BorderSkip and BorderConstant).
+func (p BorderPolicy) ResolveCoordinate(coord, lo, hi int) (int, bool) {
+	if coord >= lo && coord < hi {
+		return coord, true
+	}
+
+	size := hi - lo
+	if size <= 0 {
+		return coord, false
+	}
+
+	switch p.Mode {
+	case BorderClamp:
+		if coord < lo {
+			return lo, true
+		}
+		return hi - 1, true
+
+	case BorderReflect:
+		period := 2 * size
+		offset := ((coord-lo)%period + period) % period
+		if offset >= size {
+			offset = period - 1 - offset
+		}
+		return lo + offset, true
+
+	case BorderWrap:
+		return lo + ((coord-lo)%size+size)%size, true
+	}
+
+	return coord, false
+}
+
+// HandlesFullWindow reports whether the policy provides a value for every position of a window,
+// meaning that operations may process edge pixels instead of leaving them untouched.
+func (p BorderPolicy) HandlesFullWindow() bool {
+	return p.Mode != BorderSkip
+}
+
+// At returns the color at (x, y) taking the policy into account.
+// The boolean result is false when the pixel should be skipped.
+func (p BorderPolicy) At(img image.Image, x, y int) (color.Color, bool) {
+	bounds := img.Bounds()
+
+	rx, okX := p.ResolveCoordinate(x, bounds.Min.X, bounds.Max.X)
+	ry, okY := p.ResolveCoordinate(y, bounds.Min.Y, bounds.Max.Y)
+
+	if okX && okY {
+		return img.At(rx, ry), true
+	}
+
+	if p.Mode == BorderConstant {
+		return color.RGBA{p.Value, p.Value, p.Value, 255}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,143 @@
+package manipulations
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestParseBorderPolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected BorderPolicy
+		wantErr  bool
+	}{
+		{"", BorderPolicy{Mode: BorderSkip}, false},
+		{"skip", BorderPolicy{Mode: BorderSkip}, false},
+		{"clamp", BorderPolicy{Mode: BorderClamp}, false},
+		{"Replicate", BorderPolicy{Mode: BorderClamp}, false},
+		{"reflect", BorderPolicy{Mode: BorderReflect}, false},
+		{"wrap", BorderPolicy{Mode: BorderWrap}, false},
+		{"constant", BorderPolicy{Mode: BorderConstant}, false},
+		{"constant:128", BorderPolicy{Mode: BorderConstant, Value: 128}, false},
+		{"constant:300", BorderPolicy{}, true},
+		{"unknown", BorderPolicy{}, true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseBorderPolicy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseBorderPolicy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && policy != tt.expected {
+			t.Errorf("ParseBorderPolicy(%q) = %+v, expected %+v", tt.input, policy, tt.expected)
+		}
+	}
+}
+
+func TestResolveCoordinate(t *testing.T) {
+	// Range [0, 4) represents pixels a b c d
+	tests := []struct {
+		mode     BorderMode
+		coord    int
+		expected int
+		ok       bool
+	}{
+		{BorderSkip, -1, -1, false},
+		{BorderSkip, 2, 2, true},
+		{BorderClamp, -3, 0, true},
+		{BorderClamp, 6, 3, true},
+		{BorderReflect, -1, 0, true},
+		{BorderReflect, -3, 2, true},
+		{BorderReflect, 4, 3, true},
+		{BorderReflect, 6, 1, true},
+		{BorderWrap, -1, 3, true},
+		{BorderWrap, 5, 1, true},
+		{BorderConstant, -1, -1, false},
+	}
+
+	for _, tt := range tests {
+		policy := BorderPolicy{Mode: tt.mode}
+		resolved, ok := policy.ResolveCoordinate(tt.coord, 0, 4)
+		if resolved != tt.expected || ok != tt.ok {
+			t.Errorf("%s.ResolveCoordinate(%d) = (%d, %v), expected (%d, %v)", tt.mode, tt.coord, resolved, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestApplyConvolutionUniversalBorder(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			img.Set(x, y, color.Gray{Y: 10})
+		}
+	}
+
+	identity := [][]int{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	}
+	boxSum := [][]int{
+		{1, 1, 1},
+		{1, 1, 1},
+		{1, 1, 1},
+	}
+
+	if got := ApplyConvolutionUniversal(img, identity, BorderPolicy{Mode: BorderConstant}).RGBAAt(0, 0).R; got != 10 {
+		t.Errorf("identity mask changed corner pixel to %d", got)
+	}
+
+	// The corner pixel sees only 4 of 9 image pixels when padded with zeros and all 9 when clamped.
+	tests := []struct {
+		policy   BorderPolicy
+		expected uint8
+	}{
+		{DefaultBorderPolicy, 10},
+		{BorderPolicy{Mode: BorderConstant}, 40},
+		{BorderPolicy{Mode: BorderConstant, Value: 20}, 140},
+		{BorderPolicy{Mode: BorderClamp}, 90},
+		{BorderPolicy{Mode: BorderReflect}, 90},
+		{BorderPolicy{Mode: BorderWrap}, 90},
+	}
+
+	for _, tt := range tests {
+		if got := ApplyConvolutionUniversal(img, boxSum, tt.policy).RGBAAt(0, 0).R; got != tt.expected {
+			t.Errorf("%s border corner = %d, expected %d", tt.policy, got, tt.expected)
+		}
+	}
+}
+
+func TestApplyConvolutionOptimizedMatchesUniversal(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 5, 4))
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 5; x++ {
+			img.Set(x, y, color.Gray{Y: uint8(20*x + 7*y)})
+		}
+	}
+	sharpen := [][]int{
+		{-1, -1, -1},
+		{-1, 9, -1},
+		{-1, -1, -1},
+	}
+
+	policies := []BorderPolicy{
+		DefaultBorderPolicy,
+		{Mode: BorderConstant, Value: 30},
+		{Mode: BorderClamp},
+		{Mode: BorderReflect},
+		{Mode: BorderWrap},
+	}
+	for _, policy := range policies {
+		optimized := ApplyConvolutionOptimized(img, policy)
+		universal := ApplyConvolutionUniversal(img, sharpen, policy)
+		for y := 0; y < 4; y++ {
+			for x := 0; x < 5; x++ {
+				if got, expected := optimized.RGBAAt(x, y), universal.RGBAAt(x, y); got != expected {
+					t.Errorf("%s border at (%d, %d) = %v, expected %v", policy, x, y, got, expected)
+				}
+			}
+		}
+	}
+}
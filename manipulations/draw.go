@@ -0,0 +1,63 @@
+package manipulations
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// DrawLine draws a one pixel wide line between two points using Bresenham's algorithm.
+func DrawLine(img *image.RGBA, x0, y0, x1, y1 int, lineColor color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+
+	for {
+		img.Set(x0, y0, lineColor)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// DrawRectangle draws the outline of rect, Max is exclusive like in image.Rectangle.
+func DrawRectangle(img *image.RGBA, rect image.Rectangle, lineColor color.Color) {
+	if rect.Empty() {
+		return
+	}
+
+	right, bottom := rect.Max.X-1, rect.Max.Y-1
+	DrawLine(img, rect.Min.X, rect.Min.Y, right, rect.Min.Y, lineColor)
+	DrawLine(img, rect.Min.X, bottom, right, bottom, lineColor)
+	DrawLine(img, rect.Min.X, rect.Min.Y, rect.Min.X, bottom, lineColor)
+	DrawLine(img, right, rect.Min.Y, right, bottom, lineColor)
+}
+
+// DrawText draws str with the 7x13 basic font, (x, y) is the left end of the baseline.
+func DrawText(img *image.RGBA, x, y int, str string, textColor color.Color) {
+	drawer := &font.Drawer{
+		Dst:  img,
+		Src:  &image.Uniform{textColor},
+		Face: basicfont.Face7x13,
+		Dot:  fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)},
+	}
+	drawer.DrawString(str)
+}
@@ -3,6 +3,8 @@ package manipulations
 import (
 	"fmt"
 	"image"
+	"image/color"
+	"math"
 )
 
 func HorizontalFlip(img image.Image) *image.RGBA {
@@ -88,3 +90,51 @@ func EnlargeImage(img image.Image, factor int) (*image.RGBA, error) {
 
 	return newImg, nil
 }
+
+// ResizeImage scales the image to the given dimensions using bilinear interpolation.
+func ResizeImage(img image.Image, width, height int) (*image.RGBA, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("target size must be greater than 0")
+	}
+
+	bounds := img.Bounds()
+	newImg := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	scaleX := float64(bounds.Dx()) / float64(width)
+	scaleY := float64(bounds.Dy()) / float64(height)
+
+	for y := 0; y < height; y++ {
+		// sample at pixel centers so that both images cover the same area
+		srcY := math.Max((float64(y)+0.5)*scaleY-0.5, 0)
+		y0 := min(int(srcY), bounds.Dy()-1)
+		y1 := min(y0+1, bounds.Dy()-1)
+		fy := srcY - float64(y0)
+
+		for x := 0; x < width; x++ {
+			srcX := math.Max((float64(x)+0.5)*scaleX-0.5, 0)
+			x0 := min(int(srcX), bounds.Dx()-1)
+			x1 := min(x0+1, bounds.Dx()-1)
+			fx := srcX - float64(x0)
+
+			c00 := color.RGBAModel.Convert(img.At(bounds.Min.X+x0, bounds.Min.Y+y0)).(color.RGBA)
+			c10 := color.RGBAModel.Convert(img.At(bounds.Min.X+x1, bounds.Min.Y+y0)).(color.RGBA)
+			c01 := color.RGBAModel.Convert(img.At(bounds.Min.X+x0, bounds.Min.Y+y1)).(color.RGBA)
+			c11 := color.RGBAModel.Convert(img.At(bounds.Min.X+x1, bounds.Min.Y+y1)).(color.RGBA)
+
+			interpolate := func(v00, v10, v01, v11 uint8) uint8 {
+				top := float64(v00)*(1-fx) + float64(v10)*fx
+				bottom := float64(v01)*(1-fx) + float64(v11)*fx
+				return uint8(math.Round(top*(1-fy) + bottom*fy))
+			}
+
+			newImg.SetRGBA(x, y, color.RGBA{
+				R: interpolate(c00.R, c10.R, c01.R, c11.R),
+				G: interpolate(c00.G, c10.G, c01.G, c11.G),
+				B: interpolate(c00.B, c10.B, c01.B, c11.B),
+				A: interpolate(c00.A, c10.A, c01.A, c11.A),
+			})
+		}
+	}
+
+	return newImg, nil
+}
@@ -0,0 +1,42 @@
+package manipulations
+
+import (
+	"image/color"
+	"math"
+)
+
+// heatMapStops is a blue -> cyan -> green -> yellow -> red color ramp.
+var heatMapStops = []color.RGBA{
+	{0, 0, 255, 255},
+	{0, 255, 255, 255},
+	{0, 255, 0, 255},
+	{255, 255, 0, 255},
+	{255, 0, 0, 255},
+}
+
+// HeatMapColor maps a value from the range [0, 1] onto a heat-map color ramp.
+// Values outside of the range are clamped, NaN is treated as 0.
+func HeatMapColor(value float64) color.RGBA {
+	if math.IsNaN(value) || value < 0 {
+		value = 0
+	}
+	if value > 1 {
+		value = 1
+	}
+
+	segments := float64(len(heatMapStops) - 1)
+	position := value * segments
+	index := int(position)
+	if index >= len(heatMapStops)-1 {
+		return heatMapStops[len(heatMapStops)-1]
+	}
+
+	frac := position - float64(index)
+	from, to := heatMapStops[index], heatMapStops[index+1]
+
+	lerp := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*frac))
+	}
+
+	return color.RGBA{lerp(from.R, to.R), lerp(from.G, to.G), lerp(from.B, to.B), 255}
+}
@@ -0,0 +1,29 @@
+package manipulations
+
+import (
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestHeatMapColor(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected color.RGBA
+	}{
+		{0, color.RGBA{0, 0, 255, 255}},
+		{0.25, color.RGBA{0, 255, 255, 255}},
+		{0.5, color.RGBA{0, 255, 0, 255}},
+		{0.75, color.RGBA{255, 255, 0, 255}},
+		{1, color.RGBA{255, 0, 0, 255}},
+		{-2, color.RGBA{0, 0, 255, 255}},
+		{3, color.RGBA{255, 0, 0, 255}},
+		{math.NaN(), color.RGBA{0, 0, 255, 255}},
+	}
+
+	for _, tt := range tests {
+		if got := HeatMapColor(tt.value); got != tt.expected {
+			t.Errorf("HeatMapColor(%v) = %v, expected %v", tt.value, got, tt.expected)
+		}
+	}
+}
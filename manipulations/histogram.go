@@ -4,124 +4,124 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
-
-	"golang.org/x/image/font"
-	"golang.org/x/image/font/basicfont"
-	"golang.org/x/image/math/fixed"
+	"slices"
+	"strings"
 )
 
+// HistogramChannel selects which component of a pixel is counted in a histogram.
+type HistogramChannel int
+
 const (
-	paddingLeft   = 60
-	paddingRight  = 20
-	paddingBottom = 30
-	paddingTop    = 20
-	yLabelOffset  = 10
-	isXAxis       = true
-	isYAxis       = false
+	// HistogramValue counts the HSV value, i.e. the brightest of the RGB components.
+	HistogramValue HistogramChannel = iota
+	HistogramRed
+	HistogramGreen
+	HistogramBlue
+	// HistogramLuma counts the ITU-R BT.601 luma.
+	HistogramLuma
 )
 
-func CalculateHistogram(img image.Image) [256]int {
-	var histogram [256]int
-	bounds := img.Bounds()
-
-	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			pixel := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
-
-			_, _, value := RGBToHSV(pixel.R, pixel.G, pixel.B)
-
-			intensity := uint8(value * 255)
-
-			//  Convert to grayscale
-			// intensity := uint8(0.299*float64(pixel.R) + 0.587*float64(pixel.G) + 0.114*float64(pixel.B))
-
-			histogram[intensity]++
-		}
+func (c HistogramChannel) String() string {
+	switch c {
+	case HistogramValue:
+		return "v"
+	case HistogramRed:
+		return "r"
+	case HistogramGreen:
+		return "g"
+	case HistogramBlue:
+		return "b"
+	case HistogramLuma:
+		return "luma"
+	default:
+		return fmt.Sprintf("HistogramChannel(%d)", int(c))
 	}
-
-	return histogram
 }
 
-func GenerateGraphicalRepresentationOfHistogram(histogram [256]int) *image.RGBA {
-	maxFrequency := 0
-	for _, freq := range histogram {
-		if freq > maxFrequency {
-			maxFrequency = freq
-		}
+// PlotColor is the color used to draw the histogram of the channel.
+func (c HistogramChannel) PlotColor() color.RGBA {
+	switch c {
+	case HistogramRed:
+		return color.RGBA{220, 40, 40, 255}
+	case HistogramGreen:
+		return color.RGBA{40, 170, 60, 255}
+	case HistogramBlue:
+		return color.RGBA{40, 70, 220, 255}
+	case HistogramLuma:
+		return color.RGBA{90, 90, 90, 255}
+	default:
+		return color.RGBA{50, 100, 245, 255}
 	}
+}
 
-	width, height := 500, 500
-	histImg := image.NewRGBA(image.Rect(0, 0, width, height))
-	draw.Draw(histImg, histImg.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+// AllHistogramChannels lists every channel in the order they are reported for "all".
+var AllHistogramChannels = []HistogramChannel{HistogramRed, HistogramGreen, HistogramBlue, HistogramValue, HistogramLuma}
 
-	// Draw histogram bars with scaling
-	barColor := color.RGBA{50, 100, 245, 255}
-	for x := 0; x < 256; x++ {
-		frequency := histogram[x]
-		scaleHeight := int(float64(frequency) / float64(maxFrequency) * float64(height-paddingBottom-paddingTop))
+// AvailableHistogramChannels lists the names accepted by ParseHistogramChannels.
+func AvailableHistogramChannels() []string {
+	names := make([]string, 0, len(AllHistogramChannels)+1)
+	for _, channel := range AllHistogramChannels {
+		names = append(names, channel.String())
+	}
+	return append(names, "all")
+}
 
-		for y := height - paddingBottom; y >= height-scaleHeight-paddingBottom; y-- {
-			histImg.Set(x+paddingLeft, y, barColor)
-		}
+// ParseHistogramChannels parses a channel name, "all" selects every channel and an empty string selects HSV value.
+func ParseHistogramChannels(value string) ([]HistogramChannel, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "v", "value":
+		return []HistogramChannel{HistogramValue}, nil
+	case "r", "red":
+		return []HistogramChannel{HistogramRed}, nil
+	case "g", "green":
+		return []HistogramChannel{HistogramGreen}, nil
+	case "b", "blue":
+		return []HistogramChannel{HistogramBlue}, nil
+	case "luma", "y", "gray":
+		return []HistogramChannel{HistogramLuma}, nil
+	case "all":
+		// a copy, so that callers reordering or trimming the result leave AllHistogramChannels intact
+		return slices.Clone(AllHistogramChannels), nil
+	default:
+		return nil, fmt.Errorf("unknown histogram channel %q, expected one of: %s", value, strings.Join(AvailableHistogramChannels(), ", "))
 	}
+}
 
-	drawAxes(histImg, maxFrequency)
-	return histImg
+// CalculateHistogram counts the HSV value of every pixel.
+func CalculateHistogram(img image.Image) [256]int {
+	return CalculateChannelHistogram(img, HistogramValue)
 }
 
-func drawAxes(img *image.RGBA, maxY int) {
-	black := color.RGBA{0, 0, 0, 255}
-	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+// CalculateChannelHistogram counts the selected channel of every pixel.
+func CalculateChannelHistogram(img image.Image, channel HistogramChannel) [256]int {
+	var histogram [256]int
+	bounds := img.Bounds()
 
-	// Draw x-axis (intensity) and y-axis (frequency)
-	for x := paddingLeft; x < width-paddingRight; x++ {
-		img.Set(x, height-paddingBottom, black)
-	}
-	for y := paddingTop; y < height-paddingBottom; y++ {
-		img.Set(paddingLeft, y, black)
-	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pixel := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
 
-	// Add tick marks and labels on x-axis (0-255 intensity values)
-	for x := 0; x <= 255; x += 50 {
-		for y := height - paddingBottom - 5; y < height-paddingBottom+5; y++ {
-			img.Set(x+paddingLeft, y, black)
-		}
-		labelValue(img, x, height-paddingBottom+15, black, isXAxis)
-	}
+			var intensity uint8
+
+			switch channel {
+			case HistogramRed:
+				intensity = pixel.R
+			case HistogramGreen:
+				intensity = pixel.G
+			case HistogramBlue:
+				intensity = pixel.B
+			case HistogramLuma:
+				intensity = uint8(0.299*float64(pixel.R) + 0.587*float64(pixel.G) + 0.114*float64(pixel.B))
+			default:
+				_, _, value := RGBToHSV(pixel.R, pixel.G, pixel.B)
+				intensity = uint8(value * 255)
+			}
 
-	// Add tick marks and labels on y-axis based on max frequency scaling
-	yTicks := 5
-	yStep := maxY / yTicks
-	yLabelOffset := basicfont.Face7x13.Ascent / 2
-	for i := 0; i <= yTicks; i++ {
-		yPos := height - paddingBottom - (i * (height - paddingBottom - paddingTop) / yTicks)
-		for x := paddingLeft - 5; x < paddingLeft+5; x++ {
-			img.Set(x, yPos, black)
+			histogram[intensity]++
 		}
-		labelValue(img, i*yStep, yPos+yLabelOffset, black, isYAxis)
-	}
-}
-
-func labelValue(img *image.RGBA, value int, y int, col color.Color, isXAxis bool) {
-	str := fmt.Sprintf("%d", value)
-
-	// Position text based on axis
-	var x int
-	if isXAxis {
-		x = value + paddingLeft - (len(str) * basicfont.Face7x13.Width / 2)
-	} else {
-		x = paddingLeft - yLabelOffset - (len(str) * basicfont.Face7x13.Width)
 	}
 
-	point := fixed.Point26_6{X: fixed.Int26_6(x * 64), Y: fixed.Int26_6(y * 64)}
-	drawer := &font.Drawer{
-		Dst:  img,
-		Src:  &image.Uniform{col},
-		Face: basicfont.Face7x13,
-		Dot:  point,
-	}
-	drawer.DrawString(str)
+	return histogram
 }
 
 // findMinMax calculates the minimum and maximum brightness levels in the histogram
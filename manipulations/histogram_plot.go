@@ -0,0 +1,456 @@
+package manipulations
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+const (
+	paddingLeft   = 60
+	paddingRight  = 20
+	paddingBottom = 30
+	paddingTop    = 20
+	yLabelOffset  = 10
+
+	minPlotWidth  = 200
+	minPlotHeight = 120
+)
+
+// HistogramScale decides how frequencies are mapped onto the y-axis.
+type HistogramScale int
+
+const (
+	HistogramLinearScale HistogramScale = iota
+	// HistogramLogScale maps log(1 + frequency), which keeps sparse bins visible next to dominant ones.
+	HistogramLogScale
+)
+
+func (s HistogramScale) String() string {
+	if s == HistogramLogScale {
+		return "log"
+	}
+	return "linear"
+}
+
+// ParseHistogramScale parses "linear" or "log", an empty string selects the linear scale.
+func ParseHistogramScale(value string) (HistogramScale, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "linear":
+		return HistogramLinearScale, nil
+	case "log":
+		return HistogramLogScale, nil
+	default:
+		return HistogramLinearScale, fmt.Errorf("unknown histogram scale %q, expected linear or log", value)
+	}
+}
+
+// HistogramMarker is a vertical line drawn at the mean or at a percentile of the histogram.
+type HistogramMarker struct {
+	// Mean selects the mean intensity, otherwise Percentile is used.
+	Mean       bool
+	Percentile float64
+}
+
+func (m HistogramMarker) String() string {
+	switch {
+	case m.Mean:
+		return "mean"
+	case m.Percentile == 50:
+		return "median"
+	default:
+		return "p" + strconv.FormatFloat(m.Percentile, 'f', -1, 64)
+	}
+}
+
+// ParseHistogramMarkers parses a comma separated list of markers, e.g. "mean,median,p5,p95".
+func ParseHistogramMarkers(value string) ([]HistogramMarker, error) {
+	var markers []HistogramMarker
+
+	for _, part := range strings.Split(value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+
+		switch {
+		case part == "":
+			continue
+		case part == "mean":
+			markers = append(markers, HistogramMarker{Mean: true})
+		case part == "median":
+			markers = append(markers, HistogramMarker{Percentile: 50})
+		case strings.HasPrefix(part, "p"):
+			percentile, err := strconv.ParseFloat(part[1:], 64)
+			if err != nil || percentile < 0 || percentile > 100 {
+				return nil, fmt.Errorf("invalid percentile marker %q, expected p0 to p100", part)
+			}
+			markers = append(markers, HistogramMarker{Percentile: percentile})
+		default:
+			return nil, fmt.Errorf("unknown histogram marker %q, expected mean, median or pN", part)
+		}
+	}
+
+	return markers, nil
+}
+
+// HistogramPlotOptions configures the graphical representation of histograms.
+type HistogramPlotOptions struct {
+	Width, Height int
+	Scale         HistogramScale
+	// Cumulative overlays the cumulative distribution, scaled so that 100% reaches the top of the plot.
+	Cumulative bool
+	Markers    []HistogramMarker
+}
+
+var DefaultHistogramPlotOptions = HistogramPlotOptions{Width: 500, Height: 500}
+
+// withDefaults replaces a zero width or height with the default one.
+func (o HistogramPlotOptions) withDefaults() HistogramPlotOptions {
+	if o.Width == 0 {
+		o.Width = DefaultHistogramPlotOptions.Width
+	}
+	if o.Height == 0 {
+		o.Height = DefaultHistogramPlotOptions.Height
+	}
+	return o
+}
+
+// Validate checks that the plot is large enough to hold the axes and labels,
+// a zero width or height stands for the default one.
+func (o HistogramPlotOptions) Validate() error {
+	if o.Width < 0 || o.Height < 0 {
+		return fmt.Errorf("histogram plot size must not be negative, got %dx%d", o.Width, o.Height)
+	}
+	o = o.withDefaults()
+	if o.Width < minPlotWidth || o.Height < minPlotHeight {
+		return fmt.Errorf("histogram plot must be at least %dx%d, got %dx%d", minPlotWidth, minPlotHeight, o.Width, o.Height)
+	}
+	return nil
+}
+
+// HistogramMean returns the mean intensity of the histogram.
+func HistogramMean(histogram [256]int) float64 {
+	var sum, count float64
+	for intensity, freq := range histogram {
+		sum += float64(intensity * freq)
+		count += float64(freq)
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / count
+}
+
+// HistogramPercentile returns the lowest intensity below or at which at least p percent of pixels lie.
+func HistogramPercentile(histogram [256]int, p float64) int {
+	total := 0
+	for _, freq := range histogram {
+		total += freq
+	}
+	if total == 0 {
+		return 0
+	}
+
+	threshold := p / 100 * float64(total)
+	cumulative := 0
+	for intensity, freq := range histogram {
+		cumulative += freq
+		if float64(cumulative) >= threshold && cumulative > 0 {
+			return intensity
+		}
+	}
+	return 255
+}
+
+type histogramPlot struct {
+	img          *image.RGBA
+	opts         HistogramPlotOptions
+	maxFrequency int
+}
+
+func newHistogramPlot(opts HistogramPlotOptions, histograms ...[256]int) (*histogramPlot, error) {
+	if opts.Width < 0 || opts.Height < 0 {
+		return nil, fmt.Errorf("histogram plot size must not be negative, got %dx%d", opts.Width, opts.Height)
+	}
+	opts = opts.withDefaults()
+
+	histImg := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	draw.Draw(histImg, histImg.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	maxFrequency := 0
+	for _, histogram := range histograms {
+		for _, freq := range histogram {
+			maxFrequency = max(maxFrequency, freq)
+		}
+	}
+
+	return &histogramPlot{img: histImg, opts: opts, maxFrequency: maxFrequency}, nil
+}
+
+func (p *histogramPlot) bottom() int     { return p.opts.Height - paddingBottom }
+func (p *histogramPlot) plotWidth() int  { return p.opts.Width - paddingLeft - paddingRight }
+func (p *histogramPlot) plotHeight() int { return p.opts.Height - paddingBottom - paddingTop }
+
+// binSpan returns the first and the last column covered by the bin.
+func (p *histogramPlot) binSpan(bin int) (int, int) {
+	x0 := paddingLeft + bin*p.plotWidth()/256
+	x1 := paddingLeft + (bin+1)*p.plotWidth()/256 - 1
+	return x0, max(x0, x1)
+}
+
+func (p *histogramPlot) binCenter(bin int) int {
+	x0, x1 := p.binSpan(bin)
+	return (x0 + x1) / 2
+}
+
+// scaledHeight returns the fraction of the plot height the frequency spans.
+func (p *histogramPlot) scaledHeight(frequency int) float64 {
+	if p.maxFrequency == 0 {
+		return 0
+	}
+	if p.opts.Scale == HistogramLogScale {
+		return math.Log1p(float64(frequency)) / math.Log1p(float64(p.maxFrequency))
+	}
+	return float64(frequency) / float64(p.maxFrequency)
+}
+
+func (p *histogramPlot) barTop(frequency int) int {
+	return p.bottom() - int(p.scaledHeight(frequency)*float64(p.plotHeight()))
+}
+
+func (p *histogramPlot) drawBars(histogram [256]int, barColor color.Color) {
+	for bin := 0; bin < 256; bin++ {
+		x0, x1 := p.binSpan(bin)
+		for x := x0; x <= x1; x++ {
+			for y := p.bottom(); y >= p.barTop(histogram[bin]); y-- {
+				p.img.Set(x, y, barColor)
+			}
+		}
+	}
+}
+
+// drawOutline draws the top edge of the bars as a step line.
+func (p *histogramPlot) drawOutline(histogram [256]int, lineColor color.Color) {
+	previousTop := p.bottom()
+	for bin := 0; bin < 256; bin++ {
+		x0, x1 := p.binSpan(bin)
+		top := p.barTop(histogram[bin])
+
+		for y := min(previousTop, top); y <= max(previousTop, top); y++ {
+			p.img.Set(x0, y, lineColor)
+		}
+		for x := x0; x <= x1; x++ {
+			p.img.Set(x, top, lineColor)
+		}
+		previousTop = top
+	}
+}
+
+func (p *histogramPlot) drawLine(x0, y0, x1, y1 int, lineColor color.Color) {
+	DrawLine(p.img, x0, y0, x1, y1, lineColor)
+}
+
+func (p *histogramPlot) drawCumulative(histogram [256]int) {
+	cdfColor := color.RGBA{230, 120, 0, 255}
+
+	total := 0
+	for _, freq := range histogram {
+		total += freq
+	}
+	if total == 0 {
+		return
+	}
+
+	cumulative := 0
+	previousX, previousY := paddingLeft, p.bottom()
+	for bin := 0; bin < 256; bin++ {
+		cumulative += histogram[bin]
+		x := p.binCenter(bin)
+		y := p.bottom() - int(float64(cumulative)/float64(total)*float64(p.plotHeight()))
+		p.drawLine(previousX, previousY, x, y, cdfColor)
+		previousX, previousY = x, y
+	}
+
+	p.drawText(p.opts.Width-paddingRight-3*basicfont.Face7x13.Width, paddingTop+basicfont.Face7x13.Ascent, "CDF", cdfColor)
+}
+
+func (p *histogramPlot) drawMarkers(histogram [256]int) {
+	for _, marker := range p.opts.Markers {
+		markerColor := color.RGBA{70, 70, 70, 255}
+		var x int
+		if marker.Mean {
+			markerColor = color.RGBA{200, 0, 0, 255}
+			x = paddingLeft + int(HistogramMean(histogram)*float64(p.plotWidth())/256)
+		} else {
+			x = p.binCenter(HistogramPercentile(histogram, marker.Percentile))
+		}
+
+		// dashed line so the bars stay visible below the marker
+		for y := paddingTop; y < p.bottom(); y++ {
+			if (y/3)%2 == 0 {
+				p.img.Set(x, y, markerColor)
+			}
+		}
+
+		label := marker.String()
+		p.drawText(x-len(label)*basicfont.Face7x13.Width/2, paddingTop-4, label, markerColor)
+	}
+}
+
+type legendEntry struct {
+	label string
+	col   color.Color
+}
+
+func (p *histogramPlot) drawLegend(entries []legendEntry) {
+	face := basicfont.Face7x13
+	for i, entry := range entries {
+		y := paddingTop + 4 + i*(face.Height+4)
+		x := p.opts.Width - paddingRight - len(entry.label)*face.Width - 14
+
+		for dy := 0; dy < 8; dy++ {
+			for dx := 0; dx < 8; dx++ {
+				p.img.Set(x+dx, y+dy, entry.col)
+			}
+		}
+		p.drawText(x+12, y+8, entry.label, color.Black)
+	}
+}
+
+func (p *histogramPlot) drawText(x, y int, str string, col color.Color) {
+	DrawText(p.img, x, y, str, col)
+}
+
+// tickValue returns the frequency shown at the given fraction of the y-axis.
+func (p *histogramPlot) tickValue(fraction float64) int {
+	if p.opts.Scale == HistogramLogScale {
+		return int(math.Round(math.Expm1(fraction * math.Log1p(float64(p.maxFrequency)))))
+	}
+	return int(math.Round(fraction * float64(p.maxFrequency)))
+}
+
+func (p *histogramPlot) drawAxes() {
+	black := color.RGBA{0, 0, 0, 255}
+	face := basicfont.Face7x13
+
+	// Draw x-axis (intensity) and y-axis (frequency)
+	for x := paddingLeft; x < p.opts.Width-paddingRight; x++ {
+		p.img.Set(x, p.bottom(), black)
+	}
+	for y := paddingTop; y < p.bottom(); y++ {
+		p.img.Set(paddingLeft, y, black)
+	}
+
+	// Add tick marks and labels on x-axis (0-255 intensity values)
+	for value := 0; value <= 255; value += 50 {
+		x, _ := p.binSpan(value)
+		for y := p.bottom() - 5; y < p.bottom()+5; y++ {
+			p.img.Set(x, y, black)
+		}
+		str := strconv.Itoa(value)
+		p.drawText(x-len(str)*face.Width/2, p.bottom()+15, str, black)
+	}
+
+	// Add tick marks and labels on y-axis based on max frequency scaling
+	yTicks := 5
+	for i := 0; i <= yTicks; i++ {
+		yPos := p.bottom() - i*p.plotHeight()/yTicks
+		for x := paddingLeft - 5; x < paddingLeft+5; x++ {
+			p.img.Set(x, yPos, black)
+		}
+		str := strconv.Itoa(p.tickValue(float64(i) / float64(yTicks)))
+		p.drawText(paddingLeft-yLabelOffset-len(str)*face.Width, yPos+face.Ascent/2, str, black)
+	}
+}
+
+func GenerateGraphicalRepresentationOfHistogram(histogram [256]int) *image.RGBA {
+	return GenerateGraphicalRepresentationOfChannelHistogram(histogram, HistogramValue)
+}
+
+// GenerateGraphicalRepresentationOfChannelHistogram draws the histogram with bars colored after the channel.
+func GenerateGraphicalRepresentationOfChannelHistogram(histogram [256]int, channel HistogramChannel) *image.RGBA {
+	// the default size is never negative
+	histImg, _ := PlotHistogram(histogram, channel, DefaultHistogramPlotOptions)
+	return histImg
+}
+
+// PlotHistogram draws the histogram of the channel according to the options.
+// A zero width or height is replaced with the default one, negative sizes are an error.
+func PlotHistogram(histogram [256]int, channel HistogramChannel, opts HistogramPlotOptions) (*image.RGBA, error) {
+	plot, err := newHistogramPlot(opts, histogram)
+	if err != nil {
+		return nil, err
+	}
+
+	plot.drawBars(histogram, channel.PlotColor())
+	if opts.Cumulative {
+		plot.drawCumulative(histogram)
+	}
+	plot.drawMarkers(histogram)
+	plot.drawAxes()
+
+	return plot.img, nil
+}
+
+// GenerateOverlaidRGBHistogram draws the red, green and blue histograms on shared axes.
+// Where bars overlap their colors are mixed, so the area under all three channels is gray.
+// Only the size and the scale of the options are applied.
+func GenerateOverlaidRGBHistogram(red, green, blue [256]int, opts HistogramPlotOptions) (*image.RGBA, error) {
+	plot, err := newHistogramPlot(opts, red, green, blue)
+	if err != nil {
+		return nil, err
+	}
+
+	component := func(covered bool) uint8 {
+		if covered {
+			return 220
+		}
+		return 40
+	}
+
+	for bin := 0; bin < 256; bin++ {
+		redTop, greenTop, blueTop := plot.barTop(red[bin]), plot.barTop(green[bin]), plot.barTop(blue[bin])
+		x0, x1 := plot.binSpan(bin)
+
+		for y := min(redTop, greenTop, blueTop); y <= plot.bottom(); y++ {
+			inRed, inGreen, inBlue := y >= redTop && red[bin] > 0, y >= greenTop && green[bin] > 0, y >= blueTop && blue[bin] > 0
+			if !inRed && !inGreen && !inBlue {
+				continue
+			}
+			for x := x0; x <= x1; x++ {
+				plot.img.Set(x, y, color.RGBA{component(inRed), component(inGreen), component(inBlue), 255})
+			}
+		}
+	}
+
+	plot.drawAxes()
+	return plot.img, nil
+}
+
+// GenerateHistogramComparison draws two histograms on shared axes, the first one as light bars
+// and the second one as an outline, e.g. to compare an image before and after a transformation.
+// Cumulative curves and markers are drawn for the second histogram.
+func GenerateHistogramComparison(first, second [256]int, firstLabel, secondLabel string, channel HistogramChannel, opts HistogramPlotOptions) (*image.RGBA, error) {
+	plot, err := newHistogramPlot(opts, first, second)
+	if err != nil {
+		return nil, err
+	}
+
+	firstColor := color.RGBA{190, 190, 190, 255}
+	secondColor := channel.PlotColor()
+
+	plot.drawBars(first, firstColor)
+	plot.drawOutline(second, secondColor)
+	if opts.Cumulative {
+		plot.drawCumulative(second)
+	}
+	plot.drawMarkers(second)
+	plot.drawAxes()
+	plot.drawLegend([]legendEntry{{firstLabel, firstColor}, {secondLabel, secondColor}})
+
+	return plot.img, nil
+}
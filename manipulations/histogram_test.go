@@ -0,0 +1,143 @@
+package manipulations
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestCalculateChannelHistogram(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.Set(0, 0, color.RGBA{200, 100, 50, 255})
+	img.Set(1, 0, color.RGBA{10, 20, 30, 255})
+
+	tests := []struct {
+		channel  HistogramChannel
+		expected [2]int
+	}{
+		{HistogramRed, [2]int{200, 10}},
+		{HistogramGreen, [2]int{100, 20}},
+		{HistogramBlue, [2]int{50, 30}},
+		{HistogramValue, [2]int{200, 30}},
+		{HistogramLuma, [2]int{124, 18}},
+	}
+
+	for _, tt := range tests {
+		histogram := CalculateChannelHistogram(img, tt.channel)
+		if histogram[tt.expected[0]] != 1 || histogram[tt.expected[1]] != 1 {
+			t.Errorf("%s histogram does not count bins %v", tt.channel, tt.expected)
+		}
+	}
+}
+
+func TestParseHistogramChannels(t *testing.T) {
+	if channels, err := ParseHistogramChannels(""); err != nil || len(channels) != 1 || channels[0] != HistogramValue {
+		t.Errorf("ParseHistogramChannels(\"\") = %v, %v, expected [v]", channels, err)
+	}
+	channels, err := ParseHistogramChannels("all")
+	if err != nil || len(channels) != len(AllHistogramChannels) {
+		t.Fatalf("ParseHistogramChannels(\"all\") = %v, %v, expected every channel", channels, err)
+	}
+	channels[0] = HistogramLuma
+	if AllHistogramChannels[0] != HistogramRed {
+		t.Error("modifying the result of ParseHistogramChannels(\"all\") changed AllHistogramChannels")
+	}
+	if _, err := ParseHistogramChannels("alpha"); err == nil {
+		t.Error("ParseHistogramChannels(\"alpha\") expected error")
+	}
+}
+
+func TestParseHistogramMarkers(t *testing.T) {
+	markers, err := ParseHistogramMarkers("mean, median,p5,p99.5")
+	if err != nil {
+		t.Fatalf("ParseHistogramMarkers returned error: %v", err)
+	}
+
+	expected := []string{"mean", "median", "p5", "p99.5"}
+	if len(markers) != len(expected) {
+		t.Fatalf("expected %d markers, got %d", len(expected), len(markers))
+	}
+	for i, marker := range markers {
+		if marker.String() != expected[i] {
+			t.Errorf("marker %d = %s, expected %s", i, marker, expected[i])
+		}
+	}
+
+	for _, value := range []string{"p101", "mode", "px"} {
+		if _, err := ParseHistogramMarkers(value); err == nil {
+			t.Errorf("ParseHistogramMarkers(%q) expected error", value)
+		}
+	}
+}
+
+func TestHistogramMeanAndPercentile(t *testing.T) {
+	var histogram [256]int
+	histogram[10] = 2
+	histogram[20] = 1
+	histogram[250] = 1
+
+	if mean := HistogramMean(histogram); mean != 72.5 {
+		t.Errorf("HistogramMean = %v, expected 72.5", mean)
+	}
+	if median := HistogramPercentile(histogram, 50); median != 10 {
+		t.Errorf("median = %d, expected 10", median)
+	}
+	if p75 := HistogramPercentile(histogram, 75); p75 != 20 {
+		t.Errorf("p75 = %d, expected 20", p75)
+	}
+	if p100 := HistogramPercentile(histogram, 100); p100 != 250 {
+		t.Errorf("p100 = %d, expected 250", p100)
+	}
+}
+
+func TestPlotHistogramSize(t *testing.T) {
+	var histogram [256]int
+	histogram[128] = 10
+
+	opts := HistogramPlotOptions{Width: 640, Height: 240, Scale: HistogramLogScale, Cumulative: true, Markers: []HistogramMarker{{Mean: true}}}
+	plot, err := PlotHistogram(histogram, HistogramValue, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := plot.Bounds(); bounds.Dx() != 640 || bounds.Dy() != 240 {
+		t.Errorf("plot size = %dx%d, expected 640x240", bounds.Dx(), bounds.Dy())
+	}
+
+	if err := (HistogramPlotOptions{Width: 100, Height: 100}).Validate(); err == nil {
+		t.Error("Validate expected error for a plot smaller than the minimum")
+	}
+
+	// every zero dimension falls back to its default on its own
+	sizes := []struct {
+		width, height                 int
+		expectedWidth, expectedHeight int
+	}{
+		{0, 0, 500, 500},
+		{640, 0, 640, 500},
+		{0, 240, 500, 240},
+	}
+	for _, size := range sizes {
+		opts := HistogramPlotOptions{Width: size.width, Height: size.height}
+		if err := opts.Validate(); err != nil {
+			t.Errorf("Validate(%dx%d) returned error: %v", size.width, size.height, err)
+		}
+		plot, err := PlotHistogram(histogram, HistogramValue, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bounds := plot.Bounds(); bounds.Dx() != size.expectedWidth || bounds.Dy() != size.expectedHeight {
+			t.Errorf("plot size for %dx%d = %dx%d, expected %dx%d", size.width, size.height, bounds.Dx(), bounds.Dy(), size.expectedWidth, size.expectedHeight)
+		}
+	}
+
+	negative := HistogramPlotOptions{Width: -640, Height: 240}
+	if err := negative.Validate(); err == nil {
+		t.Error("Validate expected error for a negative width")
+	}
+	if _, err := PlotHistogram(histogram, HistogramValue, negative); err == nil {
+		t.Error("PlotHistogram expected error for a negative width")
+	}
+	if _, err := GenerateOverlaidRGBHistogram(histogram, histogram, histogram, HistogramPlotOptions{Height: -1}); err == nil {
+		t.Error("GenerateOverlaidRGBHistogram expected error for a negative height")
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"math"
 )
 
-func ApplyKirshEdgeDetection(img image.Image) *image.RGBA {
+// ApplyKirshEdgeDetection computes the Kirsh compass gradient of the image.
+// With BorderSkip the outermost rows and columns are copied from the source image,
+// other border policies let the operator run on every pixel.
+func ApplyKirshEdgeDetection(img image.Image, border BorderPolicy) *image.RGBA {
 	bounds := img.Bounds()
 	width, height := bounds.Max.X, bounds.Max.Y
 
@@ -18,15 +21,21 @@ func ApplyKirshEdgeDetection(img image.Image) *image.RGBA {
 		{-1, 0}, {-1, 1}, {0, 1}, {1, 1}, {1, 0}, {1, -1}, {0, -1}, {-1, -1},
 	}
 
-	for y := 1; y < height-1; y++ {
-		for x := 1; x < width-1; x++ {
+	margin := 1
+	if border.HandlesFullWindow() {
+		margin = 0
+	}
+
+	for y := margin; y < height-margin; y++ {
+		for x := margin; x < width-margin; x++ {
 
 			maxGradient := 0.0
 			neighborValues := make([]float64, 8)
 
 			for i := 0; i < 8; i++ {
 				dx, dy := neighborhood[i][0], neighborhood[i][1]
-				pixel := color.RGBAModel.Convert(img.At(x+dx, y+dy)).(color.RGBA)
+				neighbor, _ := border.At(img, x+dx, y+dy)
+				pixel := color.RGBAModel.Convert(neighbor).(color.RGBA)
 				_, _, v := RGBToHSV(pixel.R, pixel.G, pixel.B)
 
 				neighborValues[i] = v
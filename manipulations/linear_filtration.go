@@ -6,7 +6,10 @@ import (
 	"image/draw"
 )
 
-func ApplyConvolutionUniversal(img image.Image, mask [][]int) *image.RGBA {
+// ApplyConvolutionUniversal convolves the grayscale version of the image with the given mask.
+// With BorderSkip the pixels closer to the edge than half of the mask stay unchanged,
+// any other border policy pads the image so that every pixel gets convolved.
+func ApplyConvolutionUniversal(img image.Image, mask [][]int, border BorderPolicy) *image.RGBA {
 	bounds := img.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
 	offset := len(mask) / 2
@@ -14,13 +17,23 @@ func ApplyConvolutionUniversal(img image.Image, mask [][]int) *image.RGBA {
 	grayImg := image.NewGray(bounds)
 	draw.Draw(grayImg, bounds, img, bounds.Min, draw.Src)
 
-	for y := offset; y < height-offset; y++ {
-		for x := offset; x < width-offset; x++ {
+	margin := offset
+	if border.HandlesFullWindow() {
+		margin = 0
+	}
+
+	for y := margin; y < height-margin; y++ {
+		for x := margin; x < width-margin; x++ {
 			var sum int
 
 			for i := -offset; i <= offset; i++ {
 				for j := -offset; j <= offset; j++ {
-					grayPixel := color.GrayModel.Convert(img.At(x+i, y+j)).(color.Gray)
+					pixel, ok := border.At(img, x+i, y+j)
+					if !ok {
+						continue
+					}
+
+					grayPixel := color.GrayModel.Convert(pixel).(color.Gray)
 					sum += int(grayPixel.Y) * mask[offset+i][offset+j]
 				}
 			}
@@ -36,7 +49,10 @@ func ApplyConvolutionUniversal(img image.Image, mask [][]int) *image.RGBA {
 
 }
 
-func ApplyConvolutionOptimized(img image.Image) *image.RGBA {
+// ApplyConvolutionOptimized sharpens the grayscale version of the image with the fixed 3x3 mask
+// of 9 surrounded by -1 using direct index arithmetic. The border policy applies like in
+// ApplyConvolutionUniversal, only the edge pixels go through it so the inner loop stays unchecked.
+func ApplyConvolutionOptimized(img image.Image, border BorderPolicy) *image.RGBA {
 	bounds := img.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
 	offset := 1
@@ -76,5 +92,38 @@ func ApplyConvolutionOptimized(img image.Image) *image.RGBA {
 		}
 	}
 
+	if border.HandlesFullWindow() {
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				if y >= offset && y < height-offset && x >= offset && x < width-offset {
+					continue
+				}
+
+				var sum int
+				for i := -offset; i <= offset; i++ {
+					for j := -offset; j <= offset; j++ {
+						pixel, ok := border.At(img, x+i, y+j)
+						if !ok {
+							continue
+						}
+
+						weight := -1
+						if i == 0 && j == 0 {
+							weight = 9
+						}
+						sum += int(color.GrayModel.Convert(pixel).(color.Gray).Y) * weight
+					}
+				}
+
+				clampedValue := ClampUint8(sum)
+				idx := (y*width + x) * 4
+				rgbaImg.Pix[idx] = clampedValue
+				rgbaImg.Pix[idx+1] = clampedValue
+				rgbaImg.Pix[idx+2] = clampedValue
+				rgbaImg.Pix[idx+3] = 255
+			}
+		}
+	}
+
 	return rgbaImg
 }
@@ -25,13 +25,13 @@ func BenchmarkApplyConvolutionUniversal(b *testing.B) {
 		{-1, -1, -1},
 	}
 	for i := 0; i < b.N; i++ {
-		ApplyConvolutionUniversal(img, mask)
+		ApplyConvolutionUniversal(img, mask, DefaultBorderPolicy)
 	}
 }
 
 func BenchmarkApplyConvolutionOptimized(b *testing.B) {
 	img := generateTestImage(512, 512)
 	for i := 0; i < b.N; i++ {
-		ApplyConvolutionOptimized(img)
+		ApplyConvolutionOptimized(img, DefaultBorderPolicy)
 	}
 }
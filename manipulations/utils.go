@@ -92,3 +92,10 @@ func GetAvailableEdgeSharpeningMasksNames() ([]string, error) {
 
 	return maskNames, nil
 }
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
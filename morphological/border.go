@@ -0,0 +1,119 @@
+package morphological
+
+import "imagio/manipulations"
+
+// margins returns how many rows and columns the window of se reaches past its origin in either direction.
+func (se StructuringElement) margins() (rows, cols int) {
+	for i := range se.Data {
+		for j := range se.Data[i] {
+			if se.Data[i][j] == 1 {
+				rows = max(rows, i-se.OriginX, se.OriginX-i)
+				cols = max(cols, j-se.OriginY, se.OriginY-j)
+			}
+		}
+	}
+	return rows, cols
+}
+
+// borderAt returns the pixel at the given row and column resolving positions outside of the image with the
+// border policy, a non-zero constant padding value is foreground, see binaryPixelAt.
+func (p *PackedImage) borderAt(row, col int, border manipulations.BorderPolicy) int {
+	r, okRow := border.ResolveCoordinate(row, 0, p.height)
+	c, okCol := border.ResolveCoordinate(col, 0, p.width)
+	if okRow && okCol {
+		return p.At(r, c)
+	}
+	if border.Mode == manipulations.BorderConstant && border.Value > 0 {
+		return 1
+	}
+	return 0
+}
+
+// padded returns the image grown by the given number of rows and columns on every side,
+// the added pixels are taken from the border policy.
+func (p *PackedImage) padded(rowMargin, colMargin int, border manipulations.BorderPolicy) *PackedImage {
+	result := NewPackedImage(p.width+2*colMargin, p.height+2*rowMargin)
+	for row := 0; row < result.height; row++ {
+		srcRow := row - rowMargin
+		if srcRow >= 0 && srcRow < p.height {
+			words := result.row(row)
+			srcWords := p.row(srcRow)
+			for w := range words {
+				words[w] = shiftedWord(srcWords, w, colMargin)
+			}
+			for col := 0; col < colMargin; col++ {
+				result.Set(row, col, p.borderAt(srcRow, col-colMargin, border))
+				result.Set(row, result.width-1-col, p.borderAt(srcRow, p.width+colMargin-1-col, border))
+			}
+			continue
+		}
+
+		for col := 0; col < result.width; col++ {
+			result.Set(row, col, p.borderAt(srcRow, col-colMargin, border))
+		}
+	}
+	result.clearTails()
+	return result
+}
+
+// cropped returns the width by height part of the image starting at the given row and column.
+func (p *PackedImage) cropped(row, col, width, height int) *PackedImage {
+	result := NewPackedImage(width, height)
+	for r := 0; r < height; r++ {
+		words := result.row(r)
+		srcWords := p.row(row + r)
+		for w := range words {
+			words[w] = shiftedWord(srcWords, w, -col)
+		}
+	}
+	result.clearTails()
+	return result
+}
+
+// withBorder runs op on the image padded far enough for the windows of the elements to stay inside of it
+// and crops the result back. BorderSkip runs op on the image itself, pixels outside of it are background.
+func (p *PackedImage) withBorder(border manipulations.BorderPolicy, op func(*PackedImage) *PackedImage, elements ...StructuringElement) *PackedImage {
+	if !border.HandlesFullWindow() {
+		return op(p)
+	}
+
+	rowMargin, colMargin := 0, 0
+	for _, se := range elements {
+		rows, cols := se.margins()
+		rowMargin, colMargin = max(rowMargin, rows), max(colMargin, cols)
+	}
+	return op(p.padded(rowMargin, colMargin, border)).cropped(rowMargin, colMargin, p.width, p.height)
+}
+
+// grayBorderAt returns the intensity at (x, y) resolving positions outside of the image with the border policy.
+func grayBorderAt(img GrayImage, x, y int, border manipulations.BorderPolicy) int {
+	rx, okX := border.ResolveCoordinate(x, 0, len(img))
+	ry, okY := border.ResolveCoordinate(y, 0, len(img[0]))
+	if okX && okY {
+		return img[rx][ry]
+	}
+	return int(border.Value)
+}
+
+// grayWithBorder is PackedImage.withBorder for intensities. BorderSkip runs op on the image itself,
+// which ignores the pixels outside of it.
+func grayWithBorder(img GrayImage, se StructuringElement, border manipulations.BorderPolicy, op func(GrayImage) GrayImage) GrayImage {
+	if !border.HandlesFullWindow() || len(img) == 0 {
+		return op(img)
+	}
+
+	rowMargin, colMargin := se.margins()
+	padded := newGrayImage(len(img)+2*rowMargin, len(img[0])+2*colMargin)
+	for x := range padded {
+		for y := range padded[x] {
+			padded[x][y] = grayBorderAt(img, x-rowMargin, y-colMargin, border)
+		}
+	}
+
+	result := op(padded)
+	output := newGrayImage(len(img), len(img[0]))
+	for x := range output {
+		copy(output[x], result[x+rowMargin][colMargin:])
+	}
+	return output
+}
@@ -0,0 +1,190 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"imagio/manipulations"
+	"strings"
+)
+
+// morphology holds the primitives the composite operations are built from, for one image representation.
+type morphology[T any] struct {
+	dilate     func(T, StructuringElement, manipulations.BorderPolicy) T
+	erode      func(T, StructuringElement, manipulations.BorderPolicy) T
+	difference func(T, T) T
+}
+
+var packedMorphology = morphology[*PackedImage]{
+	dilate:     (*PackedImage).Dilate,
+	erode:      (*PackedImage).Erode,
+	difference: (*PackedImage).Difference,
+}
+
+var grayMorphology = morphology[GrayImage]{
+	dilate:     GrayDilation,
+	erode:      GrayErosion,
+	difference: subtractGray,
+}
+
+func repeat[T any](img T, iterations int, step func(T) T) T {
+	for i := 0; i < iterations; i++ {
+		img = step(img)
+	}
+	return img
+}
+
+// apply runs op with every dilation and erosion it is made of repeated the given number of times,
+// so an opening with 3 iterations erodes 3 times and then dilates 3 times.
+func (m morphology[T]) apply(img T, op Operation, se StructuringElement, iterations int, border manipulations.BorderPolicy) (T, error) {
+	var zero T
+	if iterations < 1 {
+		return zero, fmt.Errorf("iterations must be positive, got %d", iterations)
+	}
+
+	dilate := func(x T) T { return repeat(x, iterations, func(y T) T { return m.dilate(y, se, border) }) }
+	erode := func(x T) T { return repeat(x, iterations, func(y T) T { return m.erode(y, se, border) }) }
+
+	switch op {
+	case OperationDilation:
+		return dilate(img), nil
+	case OperationErosion:
+		return erode(img), nil
+	case OperationOpening:
+		return dilate(erode(img)), nil
+	case OperationClosing:
+		return erode(dilate(img)), nil
+	case OperationGradient:
+		return m.difference(dilate(img), erode(img)), nil
+	case OperationWhiteTopHat:
+		return m.difference(img, dilate(erode(img))), nil
+	case OperationBlackTopHat:
+		return m.difference(erode(dilate(img)), img), nil
+	default:
+		return zero, fmt.Errorf("unknown morphological operation %q", op)
+	}
+}
+
+// IteratedBinaryOperation runs op with its dilations and erosions repeated the given number of times.
+func IteratedBinaryOperation(img BinaryImage, op Operation, se StructuringElement, iterations int, border manipulations.BorderPolicy) (BinaryImage, error) {
+	result, err := packedMorphology.apply(Pack(img), op, se, iterations, border)
+	if err != nil {
+		return nil, err
+	}
+	return result.Unpack(), nil
+}
+
+// IteratedGrayOperation runs op with its dilations and erosions repeated the given number of times.
+func IteratedGrayOperation(img GrayImage, op Operation, se StructuringElement, iterations int, border manipulations.BorderPolicy) (GrayImage, error) {
+	return grayMorphology.apply(img, op, se, iterations, border)
+}
+
+// ApplyIteratedOperation is ApplyOperation with the dilations and erosions of op repeated the given number of times.
+func ApplyIteratedOperation(img image.Image, operation Operation, se StructuringElement, mode Mode, iterations int, border manipulations.BorderPolicy) (*image.RGBA, error) {
+	switch mode {
+	case ModeBinary:
+		result, err := IteratedBinaryOperation(ConvertIntoBinaryImage(img), operation, se, iterations, border)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertIntoImage(result), nil
+	case ModeGray:
+		channels := SplitChannels(img)
+		for c := range channels {
+			result, err := IteratedGrayOperation(channels[c], operation, se, iterations, border)
+			if err != nil {
+				return nil, err
+			}
+			channels[c] = result
+		}
+		return MergeChannels(channels), nil
+	default:
+		return nil, fmt.Errorf("unknown morphology mode %q", mode)
+	}
+}
+
+// ASFOrder selects which filter of every size of an alternating sequential filter comes first.
+type ASFOrder string
+
+const (
+	// ASFOpenClose opens and then closes at every size, removing bright noise before dark noise.
+	ASFOpenClose ASFOrder = "oc"
+	// ASFCloseOpen closes and then opens at every size.
+	ASFCloseOpen ASFOrder = "co"
+)
+
+// ParseASFOrder parses oc or co, an empty string selects oc.
+func ParseASFOrder(value string) (ASFOrder, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", string(ASFOpenClose):
+		return ASFOpenClose, nil
+	case string(ASFCloseOpen):
+		return ASFCloseOpen, nil
+	default:
+		return "", fmt.Errorf("unknown alternating sequential filter order %q, expected oc or co", value)
+	}
+}
+
+// alternatingSequentialFilter opens and closes (or closes and opens) with the element grown to every size
+// from 1 to size, the element of size i being the original one applied i times.
+func (m morphology[T]) alternatingSequentialFilter(img T, se StructuringElement, size int, order ASFOrder) (T, error) {
+	var zero T
+	if size < 1 {
+		return zero, fmt.Errorf("alternating sequential filter size must be positive, got %d", size)
+	}
+
+	first, second := OperationOpening, OperationClosing
+	if order == ASFCloseOpen {
+		first, second = second, first
+	}
+
+	for i := 1; i <= size; i++ {
+		var err error
+		if img, err = m.apply(img, first, se, i, manipulations.DefaultBorderPolicy); err != nil {
+			return zero, err
+		}
+		if img, err = m.apply(img, second, se, i, manipulations.DefaultBorderPolicy); err != nil {
+			return zero, err
+		}
+	}
+	return img, nil
+}
+
+// AlternatingSequentialFilter smooths img with openings and closings of growing size, see ASFOrder.
+//
+// Reference: J. Serra - Image Analysis and Mathematical Morphology, Volume 2 (1988)
+func AlternatingSequentialFilter(img BinaryImage, se StructuringElement, size int, order ASFOrder) (BinaryImage, error) {
+	result, err := packedMorphology.alternatingSequentialFilter(Pack(img), se, size, order)
+	if err != nil {
+		return nil, err
+	}
+	return result.Unpack(), nil
+}
+
+// GrayAlternatingSequentialFilter is the grayscale alternating sequential filter.
+func GrayAlternatingSequentialFilter(img GrayImage, se StructuringElement, size int, order ASFOrder) (GrayImage, error) {
+	return grayMorphology.alternatingSequentialFilter(img, se, size, order)
+}
+
+// ApplyAlternatingSequentialFilter runs the alternating sequential filter on img in the given mode, see ApplyOperation.
+func ApplyAlternatingSequentialFilter(img image.Image, se StructuringElement, size int, order ASFOrder, mode Mode) (*image.RGBA, error) {
+	switch mode {
+	case ModeBinary:
+		result, err := AlternatingSequentialFilter(ConvertIntoBinaryImage(img), se, size, order)
+		if err != nil {
+			return nil, err
+		}
+		return ConvertIntoImage(result), nil
+	case ModeGray:
+		channels := SplitChannels(img)
+		for c := range channels {
+			result, err := GrayAlternatingSequentialFilter(channels[c], se, size, order)
+			if err != nil {
+				return nil, err
+			}
+			channels[c] = result
+		}
+		return MergeChannels(channels), nil
+	default:
+		return nil, fmt.Errorf("unknown morphology mode %q", mode)
+	}
+}
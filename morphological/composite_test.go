@@ -0,0 +1,123 @@
+package morphological
+
+import (
+	"imagio/manipulations"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestIteratedOperationRepeatsPrimitives(t *testing.T) {
+	img := randomBinaryImage(rand.New(rand.NewSource(9)), 30, 20, 0.55)
+	se, _ := GetStructureElement("iv")
+	skip := manipulations.DefaultBorderPolicy
+
+	dilated, err := IteratedBinaryOperation(img, OperationDilation, se, 3, skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Dilation(Dilation(Dilation(img, se, skip), se, skip), se, skip); !sameBinaryImage(dilated, want) {
+		t.Error("3 dilation iterations differ from dilating 3 times")
+	}
+
+	opened, err := IteratedBinaryOperation(img, OperationOpening, se, 2, skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := Dilation(Dilation(Erosion(Erosion(img, se, skip), se, skip), se, skip), se, skip); !sameBinaryImage(opened, want) {
+		t.Error("an opening with 2 iterations differs from eroding twice and dilating twice")
+	}
+
+	single, err := IteratedBinaryOperation(img, OperationClosing, se, 1, skip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sameBinaryImage(single, Closing(img, se, skip)) {
+		t.Error("a closing with 1 iteration differs from Closing()")
+	}
+
+	if _, err := IteratedBinaryOperation(img, OperationDilation, se, 0, skip); err == nil {
+		t.Error("expected an error for 0 iterations")
+	}
+}
+
+func TestAlternatingSequentialFilterRemovesNoise(t *testing.T) {
+	img := newBinaryImage(30, 30)
+	fillRect(img, 5, 5, 20, 20)
+	img[1][1] = 1   // bright speck in the background
+	img[14][14] = 0 // dark hole in the square
+
+	se, _ := GetStructureElement("iii")
+	for _, order := range []ASFOrder{ASFOpenClose, ASFCloseOpen} {
+		filtered, err := AlternatingSequentialFilter(img, se, 2, order)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filtered[1][1] != 0 || filtered[14][14] != 1 {
+			t.Errorf("%s: the speck or the hole survived", order)
+		}
+		if filtered[10][10] != 1 || filtered[0][29] != 0 {
+			t.Errorf("%s: the square or the background changed", order)
+		}
+	}
+
+	if _, err := ParseASFOrder("cc"); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+}
+
+func TestExpressionEvaluation(t *testing.T) {
+	img := randomBinaryImage(rand.New(rand.NewSource(4)), 40, 25, 0.5)
+	iii, _ := GetStructureElement("iii")
+	iv, _ := GetStructureElement("iv")
+	disk, _ := NewDisk(3)
+	skip := manipulations.DefaultBorderPolicy
+
+	expression, err := ParseExpression("close(disk3) | open(iv) - erode(iii)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := expression.Evaluate(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Difference(Union(Closing(img, disk, skip), Opening(img, iv, skip)), Erosion(img, iii, skip))
+	if !sameBinaryImage(got, want) {
+		t.Error("close(disk3) | open(iv) - erode(iii) evaluated incorrectly")
+	}
+
+	expression, err = ParseExpression("~(x ^ dilate(xi-l, 2, erode(rect:3x1)))")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err = expression.Evaluate(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xil, _ := GetStructureElement("xi-l")
+	rect, _ := NewRectangle(3, 1)
+	want = Complement(Xor(img, Dilation(Dilation(Erosion(img, rect, skip), xil, skip), xil, skip)))
+	if !sameBinaryImage(got, want) {
+		t.Error("~(x ^ dilate(xi-l, 2, erode(rect:3x1))) evaluated incorrectly")
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                  "expected an operation",
+		"open(iv":           `expected ')'`,
+		"shrink(iv)":        "unknown operation",
+		"open(nope)":        "not found",
+		"open(iv) +":        "unexpected",
+		"open(1,1;1,1)":     "inline",
+		"dilate(iv, 0)":     "at least one iteration",
+		"open(iv) | (x":     `expected ')'`,
+		"erode(iv, 2, x) x": "unexpected",
+	}
+	for source, message := range cases {
+		_, err := ParseExpression(source)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("ParseExpression(%q) error = %v, want one containing %q", source, err, message)
+		}
+	}
+}
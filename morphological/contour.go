@@ -0,0 +1,469 @@
+package morphological
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"io"
+	"math"
+	"strings"
+)
+
+// Boundary returns the inner boundary of the objects, the image minus its erosion by se. With iii the boundary
+// is 4-connected, with iv it is 8-connected and one pixel thinner on diagonals.
+func Boundary(img BinaryImage, se StructuringElement) BinaryImage {
+	return Difference(img, Erosion(img, se, manipulations.DefaultBorderPolicy))
+}
+
+// ContourMethod selects how the contours of a binary image are traced.
+type ContourMethod string
+
+const (
+	// ContourSuzuki follows the outer borders and the hole borders of the objects and records their nesting.
+	ContourSuzuki ContourMethod = "suzuki"
+	// ContourMoore walks around the outer border of every 8-connected object only.
+	ContourMoore ContourMethod = "moore"
+)
+
+// ParseContourMethod parses suzuki or moore, an empty string selects suzuki.
+func ParseContourMethod(value string) (ContourMethod, error) {
+	switch ContourMethod(strings.ToLower(strings.TrimSpace(value))) {
+	case "", ContourSuzuki:
+		return ContourSuzuki, nil
+	case ContourMoore:
+		return ContourMoore, nil
+	default:
+		return "", fmt.Errorf("unknown contour tracing method %q, expected suzuki or moore", value)
+	}
+}
+
+// Contour is a closed border of an object or of a hole in it, objects are 8-connected and holes 4-connected.
+type Contour struct {
+	// ID starts at 1, in the raster order of the first pixel of every border.
+	ID int `json:"id"`
+	// Parent is the ID of the innermost contour enclosing this one, 0 for the contours of top level objects.
+	Parent int  `json:"parent"`
+	Hole   bool `json:"hole"`
+	// Points are the border pixels in tracing order, neighbouring points are 8-neighbours
+	// and the last point is a neighbour of the first one. A pixel can occur more than once on thin parts.
+	Points []Point `json:"points"`
+}
+
+// contourDirections are the 8 neighbours in clockwise order as seen on screen, starting at east.
+var contourDirections = [8]Point{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// directionTo returns the index in contourDirections of the neighbour b of a.
+func directionTo(a, b Point) int {
+	for d, offset := range contourDirections {
+		if a.X+offset.X == b.X && a.Y+offset.Y == b.Y {
+			return d
+		}
+	}
+	return -1
+}
+
+// TraceContours returns the contours of the objects of the image with the given method.
+func TraceContours(img BinaryImage, method ContourMethod) ([]Contour, error) {
+	switch method {
+	case ContourSuzuki:
+		return SuzukiContours(img), nil
+	case ContourMoore:
+		return MooreContours(img), nil
+	default:
+		return nil, fmt.Errorf("unknown contour tracing method %q", method)
+	}
+}
+
+// SuzukiContours follows every outer and hole border of the image, outer borders are traced counter-clockwise
+// and hole borders clockwise as seen on screen.
+//
+// Reference: S. Suzuki, K. Abe - Topological structural analysis of digitized binary images by border following (1985)
+func SuzukiContours(img BinaryImage) []Contour {
+	rows := len(img)
+	if rows == 0 {
+		return nil
+	}
+	cols := len(img[0])
+
+	// f is the image framed by background, followed borders get the number of their contour (or its negation
+	// on pixels whose east neighbour is background) and the frame is the hole border number 1
+	f := make([][]int, rows+2)
+	for y := range f {
+		f[y] = make([]int, cols+2)
+	}
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if img[y][x] != 0 {
+				f[y+1][x+1] = 1
+			}
+		}
+	}
+	at := func(p Point) int { return f[p.Y][p.X] }
+
+	var contours []Contour
+	// border returns whether the border with the given number is a hole border and its parent number
+	border := func(number int) (hole bool, parent int) {
+		if number == 1 {
+			return true, 0
+		}
+		c := contours[number-2]
+		return c.Hole, c.Parent + 1
+	}
+
+	for y := 1; y <= rows; y++ {
+		lastBorder := 1
+		for x := 1; x <= cols; x++ {
+			var from Point
+			var hole bool
+			switch {
+			case f[y][x] == 1 && f[y][x-1] == 0:
+				from = Point{x - 1, y}
+			case f[y][x] >= 1 && f[y][x+1] == 0:
+				from, hole = Point{x + 1, y}, true
+				if f[y][x] > 1 {
+					lastBorder = f[y][x]
+				}
+			default:
+				if f[y][x] != 0 && f[y][x] != 1 {
+					lastBorder = abs(f[y][x])
+				}
+				continue
+			}
+
+			number := len(contours) + 2
+			lastHole, lastParent := border(lastBorder)
+			parent := lastBorder
+			if hole == lastHole {
+				parent = lastParent
+			}
+
+			start := Point{x, y}
+			points := []Point{start}
+
+			// the first foreground neighbour clockwise from the pixel the border was entered from
+			first := -1
+			d := directionTo(start, from)
+			for k := 0; k < 8; k++ {
+				if at(neighbour(start, (d+k)%8)) != 0 {
+					first = (d + k) % 8
+					break
+				}
+			}
+
+			if first == -1 {
+				f[y][x] = -number
+			} else {
+				previous := neighbour(start, first)
+				current := start
+				for {
+					// the next foreground neighbour counter-clockwise from the previous pixel
+					d := directionTo(current, previous)
+					var next Point
+					eastExamined := false
+					for k := 1; k <= 8; k++ {
+						direction := (d - k + 8) % 8
+						next = neighbour(current, direction)
+						if direction == 0 {
+							eastExamined = true
+						}
+						if at(next) != 0 {
+							break
+						}
+					}
+
+					if eastExamined && f[current.Y][current.X+1] == 0 {
+						f[current.Y][current.X] = -number
+					} else if f[current.Y][current.X] == 1 {
+						f[current.Y][current.X] = number
+					}
+
+					if next == start && current == neighbour(start, first) {
+						break
+					}
+					previous, current = current, next
+					points = append(points, current)
+				}
+			}
+
+			for i := range points {
+				points[i].X--
+				points[i].Y--
+			}
+
+			contours = append(contours, Contour{ID: number - 1, Parent: max(parent-1, 0), Hole: hole, Points: points})
+
+			if f[y][x] != 1 {
+				lastBorder = abs(f[y][x])
+			}
+		}
+	}
+
+	return contours
+}
+
+// neighbour returns the neighbour of p in the given direction of contourDirections.
+func neighbour(p Point, direction int) Point {
+	return Point{p.X + contourDirections[direction].X, p.Y + contourDirections[direction].Y}
+}
+
+func reversePoints(points []Point) {
+	// keep the start pixel first
+	for i, j := 1, len(points)-1; i < j; i, j = i+1, j-1 {
+		points[i], points[j] = points[j], points[i]
+	}
+}
+
+// MooreContours walks around the outer border of every 8-connected object counter-clockwise as seen on screen,
+// starting at its first pixel in raster order and stopping when it leaves that pixel towards the same neighbour again.
+//
+// Reference: https://en.wikipedia.org/wiki/Moore_neighborhood#Algorithm
+func MooreContours(img BinaryImage) []Contour {
+	labeling := LabelComponents(img, Connectivity8)
+	rows := len(labeling.Labels)
+
+	contours := make([]Contour, 0, len(labeling.Components))
+	for _, component := range labeling.Components {
+		label := component.Label
+		inside := func(p Point) bool {
+			return p.Y >= 0 && p.Y < rows && p.X >= 0 && p.X < len(labeling.Labels[p.Y]) && labeling.Labels[p.Y][p.X] == label
+		}
+
+		// the first pixel in raster order has background to its west
+		start := component.Pixels[0]
+		for _, p := range component.Pixels {
+			if p.Y < start.Y || (p.Y == start.Y && p.X < start.X) {
+				start = p
+			}
+		}
+
+		points := []Point{start}
+		current, second := start, start
+		backtrack := Point{start.X - 1, start.Y}
+		for {
+			d := directionTo(current, backtrack)
+			next := current
+			for k := 1; k <= 8; k++ {
+				candidate := neighbour(current, (d+k)%8)
+				if inside(candidate) {
+					next = candidate
+					break
+				}
+				backtrack = candidate
+			}
+
+			if next == current {
+				break // isolated pixel
+			}
+			if len(points) == 1 {
+				second = next
+			} else if current == start && next == second {
+				// the walk is back on the start pixel, which is already the first point
+				points = points[:len(points)-1]
+				break
+			}
+			current = next
+			points = append(points, current)
+		}
+
+		// the search is clockwise on screen, so is the walk around the object
+		reversePoints(points)
+
+		contours = append(contours, Contour{ID: len(contours) + 1, Points: points})
+	}
+
+	return contours
+}
+
+// ChainCode returns the Freeman chain code of the contour, one digit per step from a point to the next one and
+// from the last point back to the first. 0 is east and the codes go counter-clockwise on screen, 2 is north.
+func (c Contour) ChainCode() []int {
+	if len(c.Points) < 2 {
+		return nil
+	}
+
+	codes := make([]int, len(c.Points))
+	for i, p := range c.Points {
+		next := c.Points[(i+1)%len(c.Points)]
+		// contourDirections go clockwise on screen from east, the chain code counter-clockwise
+		codes[i] = (8 - directionTo(p, next)) % 8
+	}
+	return codes
+}
+
+// ChainCodeString returns the chain code as a string of digits.
+func (c Contour) ChainCodeString() string {
+	var sb strings.Builder
+	for _, code := range c.ChainCode() {
+		sb.WriteByte(byte('0' + code))
+	}
+	return sb.String()
+}
+
+// Length is the length of the chain code, 1 for every horizontal or vertical step and sqrt(2) for every diagonal one.
+func (c Contour) Length() float64 {
+	var length float64
+	for _, code := range c.ChainCode() {
+		if code%2 == 0 {
+			length++
+		} else {
+			length += math.Sqrt2
+		}
+	}
+	return length
+}
+
+// Simplify approximates the contour with a polygon whose vertices are contour points no further than epsilon
+// from the contour. The contour is split at its first point and the point furthest from it, then every half
+// is simplified with the Douglas-Peucker algorithm. An epsilon of 0 or less keeps every point.
+//
+// Reference: D. Douglas, T. Peucker - Algorithms for the reduction of the number of points
+// required to represent a digitized line or its caricature (1973)
+func (c Contour) Simplify(epsilon float64) []Point {
+	points := c.Points
+	if epsilon <= 0 || len(points) < 3 {
+		return append([]Point(nil), points...)
+	}
+
+	furthest, furthestDistance := 0, -1.0
+	for i, p := range points {
+		if distance := pointDistance(points[0], p); distance > furthestDistance {
+			furthest, furthestDistance = i, distance
+		}
+	}
+
+	closed := append(append([]Point(nil), points...), points[0])
+	first := douglasPeucker(closed[:furthest+1], epsilon)
+	second := douglasPeucker(closed[furthest:], epsilon)
+
+	// both halves share the furthest point and the second one ends at the first point
+	polygon := append(first, second[1:len(second)-1]...)
+	return polygon
+}
+
+func pointDistance(a, b Point) float64 {
+	return math.Hypot(float64(a.X-b.X), float64(a.Y-b.Y))
+}
+
+// segmentDistance returns the distance of p to the segment from a to b.
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := float64(b.X-a.X), float64(b.Y-a.Y)
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return pointDistance(p, a)
+	}
+
+	t := (float64(p.X-a.X)*dx + float64(p.Y-a.Y)*dy) / lengthSquared
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(float64(p.X-a.X)-t*dx, float64(p.Y-a.Y)-t*dy)
+}
+
+// douglasPeucker simplifies the open polyline, keeping its end points.
+func douglasPeucker(points []Point, epsilon float64) []Point {
+	if len(points) < 3 {
+		return append([]Point(nil), points...)
+	}
+
+	last := len(points) - 1
+	furthest, furthestDistance := 0, 0.0
+	for i := 1; i < last; i++ {
+		if distance := segmentDistance(points[i], points[0], points[last]); distance > furthestDistance {
+			furthest, furthestDistance = i, distance
+		}
+	}
+
+	if furthestDistance <= epsilon {
+		return []Point{points[0], points[last]}
+	}
+
+	left := douglasPeucker(points[:furthest+1], epsilon)
+	right := douglasPeucker(points[furthest:], epsilon)
+	return append(left, right[1:]...)
+}
+
+// contourExport is a contour with its measurements as written by WriteContoursJSON.
+type contourExport struct {
+	Contour
+	Length    float64 `json:"length"`
+	ChainCode string  `json:"chain_code"`
+	Polygon   []Point `json:"polygon,omitempty"`
+}
+
+// WriteContoursJSON writes the contours as an indented JSON array with their length, chain code and,
+// for an epsilon above 0, their polygon approximation.
+func WriteContoursJSON(w io.Writer, contours []Contour, epsilon float64) error {
+	exports := make([]contourExport, 0, len(contours))
+	for _, c := range contours {
+		export := contourExport{Contour: c, Length: c.Length(), ChainCode: c.ChainCodeString()}
+		if epsilon > 0 {
+			export.Polygon = c.Simplify(epsilon)
+		}
+		exports = append(exports, export)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(exports)
+}
+
+// WriteContoursSVG writes the contours as closed paths through the pixel centers of an image of the given size,
+// outer borders in red and holes in blue. With an epsilon above 0 the paths are the polygon approximations.
+func WriteContoursSVG(w io.Writer, contours []Contour, width, height int, epsilon float64) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	sb.WriteString(`<rect width="100%" height="100%" fill="black"/>` + "\n")
+
+	for _, c := range contours {
+		points := c.Simplify(epsilon)
+		if len(points) == 0 {
+			continue
+		}
+
+		stroke := "red"
+		if c.Hole {
+			stroke = "blue"
+		}
+
+		fmt.Fprintf(&sb, `<path id="contour-%d" d="M%g %g`, c.ID, float64(points[0].X)+0.5, float64(points[0].Y)+0.5)
+		for _, p := range points[1:] {
+			fmt.Fprintf(&sb, " L%g %g", float64(p.X)+0.5, float64(p.Y)+0.5)
+		}
+		fmt.Fprintf(&sb, ` Z" fill="none" stroke="%s" stroke-width="0.5"/>`+"\n", stroke)
+	}
+
+	sb.WriteString("</svg>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+var (
+	overlayOuterColor = color.RGBA{255, 64, 64, 255}
+	overlayHoleColor  = color.RGBA{64, 160, 255, 255}
+)
+
+// DrawContoursOverlay draws the contours, or their polygon approximations for an epsilon above 0, over the binary image.
+func DrawContoursOverlay(img BinaryImage, contours []Contour, epsilon float64) *image.RGBA {
+	overlay := ConvertIntoImage(img)
+	// dim the foreground so that the contours stand out
+	for i := 0; i < len(overlay.Pix); i += 4 {
+		if overlay.Pix[i] == 255 {
+			overlay.Pix[i], overlay.Pix[i+1], overlay.Pix[i+2] = 110, 110, 110
+		}
+	}
+
+	for _, c := range contours {
+		lineColor := overlayOuterColor
+		if c.Hole {
+			lineColor = overlayHoleColor
+		}
+
+		points := c.Simplify(epsilon)
+		for i, p := range points {
+			next := points[(i+1)%len(points)]
+			manipulations.DrawLine(overlay, p.X, p.Y, next.X, next.Y, lineColor)
+		}
+	}
+
+	return overlay
+}
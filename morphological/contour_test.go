@@ -0,0 +1,138 @@
+package morphological
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBoundary(t *testing.T) {
+	img := newBinaryImage(10, 8)
+	fillRect(img, 2, 2, 5, 4)
+
+	iii, _ := GetStructureElement("iii")
+	boundary := Boundary(img, iii)
+	if got := foregroundCount(boundary); got != 14 {
+		t.Errorf("boundary of a 5x4 rectangle has %d pixels, want 14", got)
+	}
+	if boundary[3][3] != 0 || boundary[2][2] != 1 {
+		t.Error("the boundary holds an inner pixel or misses a corner")
+	}
+}
+
+func TestSuzukiContoursHierarchy(t *testing.T) {
+	img := binaryFromRows(
+		"........",
+		".######.",
+		".#....#.",
+		".#.##.#.",
+		".#....#.",
+		".######.",
+		"........",
+		"...#....",
+	)
+
+	contours := SuzukiContours(img)
+	if len(contours) != 4 {
+		t.Fatalf("got %d contours, want 4", len(contours))
+	}
+
+	want := []struct {
+		parent int
+		hole   bool
+		chain  string
+	}{
+		{0, false, "666600000222244444"},
+		{1, true, "10007665444322"},
+		{2, false, "04"},
+		{0, false, ""},
+	}
+	for i, w := range want {
+		c := contours[i]
+		if c.ID != i+1 || c.Parent != w.parent || c.Hole != w.hole || c.ChainCodeString() != w.chain {
+			t.Errorf("contour %d = id %d, parent %d, hole %v, chain %q, want parent %d, hole %v, chain %q",
+				i, c.ID, c.Parent, c.Hole, c.ChainCodeString(), w.parent, w.hole, w.chain)
+		}
+	}
+
+	if length := contours[0].Length(); length != 18 {
+		t.Errorf("outer length = %v, want 18", length)
+	}
+}
+
+func TestMooreContoursMatchSuzukiOuterBorders(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 100; i++ {
+		img := randomBinaryImage(random, 1+random.Intn(20), 1+random.Intn(20), random.Float64())
+
+		var outer []Contour
+		for _, c := range SuzukiContours(img) {
+			for j, p := range c.Points {
+				if next := c.Points[(j+1)%len(c.Points)]; len(c.Points) > 1 && directionTo(p, next) < 0 {
+					t.Fatalf("contour %d jumps from %v to %v", c.ID, p, next)
+				}
+			}
+			if !c.Hole {
+				outer = append(outer, c)
+			}
+		}
+
+		moore := MooreContours(img)
+		if len(moore) != len(outer) {
+			t.Fatalf("Moore traced %d contours, Suzuki %d outer borders", len(moore), len(outer))
+		}
+		for j := range moore {
+			if !reflect.DeepEqual(moore[j].Points, outer[j].Points) {
+				t.Fatalf("contour %d: Moore %v, Suzuki %v", j+1, moore[j].Points, outer[j].Points)
+			}
+		}
+	}
+}
+
+func TestContourSimplify(t *testing.T) {
+	img := newBinaryImage(20, 12)
+	fillRect(img, 2, 3, 15, 6)
+	contour := SuzukiContours(img)[0]
+
+	corners := []Point{{2, 3}, {2, 8}, {16, 8}, {16, 3}}
+	if got := contour.Simplify(1); !reflect.DeepEqual(got, corners) {
+		t.Errorf("Simplify(1) = %v, want %v", got, corners)
+	}
+	if got := contour.Simplify(0); !reflect.DeepEqual(got, contour.Points) {
+		t.Error("Simplify(0) dropped points")
+	}
+}
+
+func TestWriteContours(t *testing.T) {
+	contours := SuzukiContours(binaryFromRows("###", "#.#", "###"))
+
+	var data bytes.Buffer
+	if err := WriteContoursJSON(&data, contours, 1); err != nil {
+		t.Fatal(err)
+	}
+	var decoded []struct {
+		ID        int     `json:"id"`
+		Hole      bool    `json:"hole"`
+		ChainCode string  `json:"chain_code"`
+		Polygon   []Point `json:"polygon"`
+	}
+	if err := json.Unmarshal(data.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 2 || !decoded[1].Hole || decoded[0].ChainCode != "66002244" || len(decoded[0].Polygon) != 4 {
+		t.Errorf("unexpected JSON export %s", data.String())
+	}
+
+	data.Reset()
+	if err := WriteContoursSVG(&data, contours, 3, 3, 0); err != nil {
+		t.Fatal(err)
+	}
+	svg := data.String()
+	if !strings.Contains(svg, `viewBox="0 0 3 3"`) || strings.Count(svg, "<path") != 2 || !strings.Contains(svg, `d="M0.5 0.5 L0.5 1.5`) {
+		t.Errorf("unexpected SVG export %s", svg)
+	}
+}
@@ -0,0 +1,88 @@
+package morphological
+
+import "imagio/manipulations"
+
+// ConvexHull fills the convex deficiency of the foreground, taken as a single set. Starting from the image,
+// every element of SeriesXISE adds the background pixels it matches until no pixel changes or maxIterations
+// steps ran, 0 or less is unlimited, and the hull is the union of the four results. The growth is limited to
+// the bounding box of the foreground, so the hull approximates the convex hull with edges at multiples of 45 degrees.
+// It returns the largest number of steps an element needed.
+//
+// Reference: R. Gonzalez, R. Woods - Digital Image Processing, 9.5.4 Convex hull
+func ConvexHull(img BinaryImage, maxIterations int) (BinaryImage, int) {
+	hull, iterations := convexHull(Pack(img), maxIterations)
+	return hull.Unpack(), iterations
+}
+
+func convexHull(img *PackedImage, maxIterations int) (*PackedImage, int) {
+	box := NewPackedImage(img.Width(), img.Height())
+	minRow, minCol, maxRow, maxCol := img.Height(), img.Width(), -1, -1
+	for row := 0; row < img.Height(); row++ {
+		for col := 0; col < img.Width(); col++ {
+			if img.At(row, col) == 1 {
+				minRow, maxRow = min(minRow, row), max(maxRow, row)
+				minCol, maxCol = min(minCol, col), max(maxCol, col)
+			}
+		}
+	}
+	for row := minRow; row <= maxRow; row++ {
+		for col := minCol; col <= maxCol; col++ {
+			box.Set(row, col, 1)
+		}
+	}
+
+	hull := img.Clone()
+	iterations := 0
+	for _, pair := range seriesPairs(SeriesXISE) {
+		grown := img
+		steps := 0
+		for maxIterations <= 0 || steps < maxIterations {
+			added := grown.HitOrMiss(pair.Foreground, pair.Background, manipulations.DefaultBorderPolicy).Intersection(box)
+			if added.Count() == 0 {
+				break
+			}
+			grown = grown.Union(added)
+			steps++
+		}
+		hull = hull.Union(grown)
+		iterations = max(iterations, steps)
+	}
+
+	return hull, iterations
+}
+
+// ComponentConvexHulls fills the convex hull of every connected component on its own, see ConvexHull,
+// so that the hulls of objects far apart do not join. Overlapping hulls are merged.
+func ComponentConvexHulls(img BinaryImage, connectivity Connectivity, maxIterations int) (BinaryImage, int) {
+	rows := len(img)
+	if rows == 0 {
+		return BinaryImage{}, 0
+	}
+
+	output := make(BinaryImage, rows)
+	for y := range output {
+		output[y] = make([]int, len(img[y]))
+	}
+
+	iterations := 0
+	for _, component := range LabelComponents(img, connectivity).Components {
+		// work on the bounding box of the component only
+		crop := NewPackedImage(component.Box.Width, component.Box.Height)
+		for _, p := range component.Pixels {
+			crop.Set(p.Y-component.Box.Y, p.X-component.Box.X, 1)
+		}
+
+		hull, steps := convexHull(crop, maxIterations)
+		iterations = max(iterations, steps)
+
+		for row := 0; row < hull.Height(); row++ {
+			for col := 0; col < hull.Width(); col++ {
+				if hull.At(row, col) == 1 {
+					output[row+component.Box.Y][col+component.Box.X] = 1
+				}
+			}
+		}
+	}
+
+	return output, iterations
+}
@@ -0,0 +1,61 @@
+package morphological
+
+import "testing"
+
+func TestConvexHullFillsConcavities(t *testing.T) {
+	u := binaryFromRows(
+		"..........",
+		".##....##.",
+		".##....##.",
+		".##....##.",
+		".########.",
+		"..........",
+	)
+	want := binaryFromRows(
+		"..........",
+		".########.",
+		".########.",
+		".########.",
+		".########.",
+		"..........",
+	)
+
+	hull, iterations := ConvexHull(u, 0)
+	if !sameBinaryImage(hull, want) {
+		t.Errorf("ConvexHull() = %v, want %v", hull, want)
+	}
+	if iterations != 3 {
+		t.Errorf("iterations = %d, want 3", iterations)
+	}
+
+	limited, iterations := ConvexHull(u, 1)
+	if iterations != 1 || foregroundCount(limited) <= foregroundCount(u) || foregroundCount(limited) >= foregroundCount(want) {
+		t.Errorf("a single iteration filled %d pixels in %d iterations", foregroundCount(limited), iterations)
+	}
+
+	if empty, _ := ConvexHull(newBinaryImage(4, 3), 0); foregroundCount(empty) != 0 {
+		t.Error("the hull of an empty image is not empty")
+	}
+}
+
+func TestComponentConvexHullsKeepObjectsApart(t *testing.T) {
+	img := binaryFromRows(
+		"#.#......",
+		"###......",
+		".........",
+		"......#.#",
+		"......###",
+	)
+	want := binaryFromRows(
+		"###......",
+		"###......",
+		".........",
+		"......###",
+		"......###",
+	)
+
+	hulls, _ := ComponentConvexHulls(img, Connectivity8, 0)
+	if !sameBinaryImage(hulls, want) {
+		t.Errorf("ComponentConvexHulls() = %v, want %v", hulls, want)
+	}
+}
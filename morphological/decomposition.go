@@ -0,0 +1,118 @@
+package morphological
+
+// Decompose splits a rectangle of ones with the origin inside of it into a horizontal and a vertical line,
+// dilating or eroding by both in turn equals dilating or eroding by the rectangle. Heights are not considered.
+func (se StructuringElement) Decompose() (horizontal, vertical StructuringElement, ok bool) {
+	if len(se.Data) == 0 || len(se.Data[0]) == 0 || (len(se.Data) == 1 && len(se.Data[0]) == 1) {
+		return StructuringElement{}, StructuringElement{}, false
+	}
+
+	rows, cols := len(se.Data), len(se.Data[0])
+	if se.OriginX < 0 || se.OriginX >= rows || se.OriginY < 0 || se.OriginY >= cols {
+		return StructuringElement{}, StructuringElement{}, false
+	}
+	for _, row := range se.Data {
+		if len(row) != cols {
+			return StructuringElement{}, StructuringElement{}, false
+		}
+		for _, value := range row {
+			if value != 1 {
+				return StructuringElement{}, StructuringElement{}, false
+			}
+		}
+	}
+
+	horizontal = StructuringElement{Data: [][]int{make([]int, cols)}, OriginX: 0, OriginY: se.OriginY}
+	for j := range horizontal.Data[0] {
+		horizontal.Data[0][j] = 1
+	}
+
+	vertical = StructuringElement{Data: make([][]int, rows), OriginX: se.OriginX, OriginY: 0}
+	for i := range vertical.Data {
+		vertical.Data[i] = []int{1}
+	}
+
+	return horizontal, vertical, true
+}
+
+// isFlat reports whether the element adds no height to any of its pixels.
+func (se StructuringElement) isFlat() bool {
+	for _, row := range se.Heights {
+		for _, value := range row {
+			if value != 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// slidingExtremum returns for every i the best of values[i-before] to values[i+after] in a constant number
+// of comparisons per value, whatever the window length. Positions outside of the slice are ignored.
+//
+// Reference: M. van Herk - A fast algorithm for local minimum and maximum filters on rectangular and octagonal kernels (1992)
+func slidingExtremum(values []int, before, after int, better func(a, b int) int, identity int) []int {
+	size := before + after + 1
+	padded := make([]int, len(values)+size-1)
+	for k := range padded {
+		padded[k] = identity
+		if k >= before && k-before < len(values) {
+			padded[k] = values[k-before]
+		}
+	}
+
+	// forward and backward running extrema restarting at every block of the window length
+	forward := make([]int, len(padded))
+	backward := make([]int, len(padded))
+	for k := range padded {
+		if k%size == 0 {
+			forward[k] = padded[k]
+		} else {
+			forward[k] = better(forward[k-1], padded[k])
+		}
+	}
+	for k := len(padded) - 1; k >= 0; k-- {
+		if k == len(padded)-1 || (k+1)%size == 0 {
+			backward[k] = padded[k]
+		} else {
+			backward[k] = better(backward[k+1], padded[k])
+		}
+	}
+
+	output := make([]int, len(values))
+	for i := range output {
+		output[i] = better(backward[i], forward[i+size-1])
+	}
+	return output
+}
+
+// grayRectangleFilter dilates or erodes by a flat rectangle as a row and a column van Herk filter.
+func grayRectangleFilter(img GrayImage, se StructuringElement, dilate bool) GrayImage {
+	rows, cols := len(se.Data), len(se.Data[0])
+
+	better, identity := func(a, b int) int { return min(a, b) }, 256
+	// the dilation reads f(p-d), so the window is the reflected element
+	left, right, up, down := se.OriginY, cols-1-se.OriginY, se.OriginX, rows-1-se.OriginX
+	if dilate {
+		better, identity = func(a, b int) int { return max(a, b) }, -1
+		left, right, up, down = right, left, down, up
+	}
+
+	output := make(GrayImage, len(img))
+	for x := range img {
+		output[x] = slidingExtremum(img[x], left, right, better, identity)
+	}
+
+	column := make([]int, len(img))
+	for y := range img[0] {
+		for x := range img {
+			column[x] = output[x][y]
+		}
+		filtered := slidingExtremum(column, up, down, better, identity)
+		for x := range img {
+			output[x][y] = filtered[x]
+		}
+	}
+
+	return output
+}
@@ -0,0 +1,249 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+func (c DistanceCriterion) String() string {
+	switch c {
+	case Euclidean:
+		return "euclidean"
+	case Manhattan:
+		return "cityblock"
+	case Chebyshev:
+		return "chessboard"
+	default:
+		return fmt.Sprintf("DistanceCriterion(%d)", int(c))
+	}
+}
+
+// ParseDistanceCriterion parses euclidean, cityblock (manhattan) or chessboard (chebyshev),
+// the numbers 0 to 2 used by region growing are accepted too. An empty string selects euclidean.
+func ParseDistanceCriterion(value string) (DistanceCriterion, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "euclidean", "0":
+		return Euclidean, nil
+	case "cityblock", "manhattan", "1":
+		return Manhattan, nil
+	case "chessboard", "chebyshev", "2":
+		return Chebyshev, nil
+	default:
+		return 0, fmt.Errorf("unknown distance metric %q, expected euclidean, cityblock or chessboard", value)
+	}
+}
+
+// edtInfinity stands for the distance of pixels not reached yet, it is finite so that the
+// parabola intersections of the Euclidean transform stay well defined.
+const edtInfinity = 1e20
+
+// squaredEDT1D computes the squared Euclidean distance transform of the sampled function f in place.
+//
+// Reference: P. Felzenszwalb, D. Huttenlocher - Distance transforms of sampled functions (2012)
+func squaredEDT1D(f []float64, v []int, z []float64, d []float64) {
+	n := len(f)
+	k := 0
+	v[0] = 0
+	z[0] = math.Inf(-1)
+	z[1] = math.Inf(1)
+
+	// lower envelope of the parabolas rooted at every sample
+	for q := 1; q < n; q++ {
+		s := ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		for s <= z[k] {
+			k--
+			s = ((f[q] + float64(q*q)) - (f[v[k]] + float64(v[k]*v[k]))) / float64(2*q-2*v[k])
+		}
+		k++
+		v[k] = q
+		z[k] = s
+		z[k+1] = math.Inf(1)
+	}
+
+	k = 0
+	for q := 0; q < n; q++ {
+		for z[k+1] < float64(q) {
+			k++
+		}
+		diff := float64(q - v[k])
+		d[q] = diff*diff + f[v[k]]
+	}
+
+	copy(f, d)
+}
+
+func euclideanDistanceTransform(img BinaryImage) [][]float64 {
+	rows := len(img)
+	cols := len(img[0])
+	size := max(rows, cols)
+
+	v := make([]int, size)
+	z := make([]float64, size+1)
+	d := make([]float64, size)
+	column := make([]float64, rows)
+
+	dist := make([][]float64, rows)
+	for y := range dist {
+		dist[y] = make([]float64, cols)
+		for x := range dist[y] {
+			if img[y][x] == 1 {
+				dist[y][x] = edtInfinity
+			}
+		}
+	}
+
+	for x := 0; x < cols; x++ {
+		for y := 0; y < rows; y++ {
+			column[y] = dist[y][x]
+		}
+		squaredEDT1D(column, v, z, d[:rows])
+		for y := 0; y < rows; y++ {
+			dist[y][x] = column[y]
+		}
+	}
+
+	for y := 0; y < rows; y++ {
+		squaredEDT1D(dist[y], v, z, d[:cols])
+		for x := range dist[y] {
+			if dist[y][x] >= edtInfinity {
+				dist[y][x] = math.Inf(1)
+			} else {
+				dist[y][x] = math.Sqrt(dist[y][x])
+			}
+		}
+	}
+
+	return dist
+}
+
+// chamferDistanceTransform runs a forward and a backward raster scan, which is exact for the
+// city-block distance with edge neighbours and for the chessboard distance with all 8 neighbours.
+func chamferDistanceTransform(img BinaryImage, connectivity Connectivity) [][]float64 {
+	rows := len(img)
+	cols := len(img[0])
+
+	dist := make([][]float64, rows)
+	for y := range dist {
+		dist[y] = make([]float64, cols)
+		for x := range dist[y] {
+			if img[y][x] == 1 {
+				dist[y][x] = math.Inf(1)
+			}
+		}
+	}
+
+	scan := func(neighbours []Point, x, y int) {
+		for _, d := range neighbours {
+			nx, ny := x+d.X, y+d.Y
+			if nx >= 0 && nx < cols && ny >= 0 && ny < rows {
+				dist[y][x] = min(dist[y][x], dist[ny][nx]+1)
+			}
+		}
+	}
+
+	previous := connectivity.previousNeighbours()
+	next := connectivity.nextNeighbours()
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if dist[y][x] > 0 {
+				scan(previous, x, y)
+			}
+		}
+	}
+	for y := rows - 1; y >= 0; y-- {
+		for x := cols - 1; x >= 0; x-- {
+			if dist[y][x] > 0 {
+				scan(next, x, y)
+			}
+		}
+	}
+
+	return dist
+}
+
+// DistanceTransform returns the distance of every foreground pixel to the nearest background pixel,
+// background pixels get 0. Pixels outside of the image are not background, so an image without
+// any background pixel has an infinite distance everywhere.
+func DistanceTransform(img BinaryImage, metric DistanceCriterion) ([][]float64, error) {
+	if len(img) == 0 || len(img[0]) == 0 {
+		return nil, nil
+	}
+
+	switch metric {
+	case Euclidean:
+		return euclideanDistanceTransform(img), nil
+	case Manhattan:
+		return chamferDistanceTransform(img, Connectivity4), nil
+	case Chebyshev:
+		return chamferDistanceTransform(img, Connectivity8), nil
+	default:
+		return nil, fmt.Errorf("unsupported distance metric %v", metric)
+	}
+}
+
+// distanceToFrame is the Euclidean distance transform treating pixels outside of the image as background.
+func distanceToFrame(img BinaryImage) [][]float64 {
+	rows := len(img)
+	cols := len(img[0])
+
+	padded := make(BinaryImage, rows+2)
+	padded[0] = make([]int, cols+2)
+	padded[rows+1] = make([]int, cols+2)
+	for y := range img {
+		padded[y+1] = make([]int, cols+2)
+		copy(padded[y+1][1:], img[y])
+	}
+
+	dist := euclideanDistanceTransform(padded)
+
+	cropped := make([][]float64, rows)
+	for y := range cropped {
+		cropped[y] = dist[y+1][1 : cols+1]
+	}
+	return cropped
+}
+
+// MaxDistance returns the largest finite distance.
+func MaxDistance(dist [][]float64) float64 {
+	peak := 0.0
+	for _, row := range dist {
+		for _, value := range row {
+			if !math.IsInf(value, 1) {
+				peak = max(peak, value)
+			}
+		}
+	}
+	return peak
+}
+
+// DistanceImage renders the distances as gray levels scaled so that the largest finite distance is white,
+// infinite distances are white as well.
+func DistanceImage(dist [][]float64) *image.RGBA {
+	height := len(dist)
+	width := 0
+	if height > 0 {
+		width = len(dist[0])
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	peak := MaxDistance(dist)
+
+	for y, row := range dist {
+		for x, value := range row {
+			level := uint8(255)
+			if !math.IsInf(value, 1) {
+				level = 0
+				if peak > 0 {
+					level = uint8(math.Round(255 * value / peak))
+				}
+			}
+			img.SetRGBA(x, y, color.RGBA{level, level, level, 255})
+		}
+	}
+
+	return img
+}
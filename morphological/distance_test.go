@@ -0,0 +1,87 @@
+package morphological
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func bruteForceDistance(img BinaryImage, metric DistanceCriterion) [][]float64 {
+	dist := make([][]float64, len(img))
+	for y := range img {
+		dist[y] = make([]float64, len(img[y]))
+		for x := range img[y] {
+			if img[y][x] == 0 {
+				continue
+			}
+
+			best := math.Inf(1)
+			for by := range img {
+				for bx := range img[by] {
+					if img[by][bx] == 0 {
+						best = math.Min(best, calculateDistance(metric, []float64{float64(x), float64(y)}, []float64{float64(bx), float64(by)}))
+					}
+				}
+			}
+			dist[y][x] = best
+		}
+	}
+	return dist
+}
+
+func TestDistanceTransformMatchesBruteForce(t *testing.T) {
+	random := rand.New(rand.NewSource(7))
+
+	img := newBinaryImage(23, 17)
+	for y := range img {
+		for x := range img[y] {
+			if random.Float64() < 0.85 {
+				img[y][x] = 1
+			}
+		}
+	}
+
+	for _, metric := range []DistanceCriterion{Euclidean, Manhattan, Chebyshev} {
+		got, err := DistanceTransform(img, metric)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := bruteForceDistance(img, metric)
+		for y := range want {
+			for x := range want[y] {
+				if math.Abs(got[y][x]-want[y][x]) > 1e-9 {
+					t.Fatalf("%s distance at (%d, %d) = %v, want %v", metric, x, y, got[y][x], want[y][x])
+				}
+			}
+		}
+	}
+}
+
+func TestDistanceTransformWithoutBackground(t *testing.T) {
+	img := newBinaryImage(4, 3)
+	fillRect(img, 0, 0, 4, 3)
+
+	dist, err := DistanceTransform(img, Euclidean)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !math.IsInf(dist[1][1], 1) {
+		t.Errorf("distance = %v, want +Inf", dist[1][1])
+	}
+	if MaxDistance(dist) != 0 {
+		t.Errorf("MaxDistance() = %v, want 0", MaxDistance(dist))
+	}
+}
+
+func TestParseDistanceCriterion(t *testing.T) {
+	cases := map[string]DistanceCriterion{"": Euclidean, "cityblock": Manhattan, "Chessboard": Chebyshev, "1": Manhattan}
+	for value, want := range cases {
+		if got, err := ParseDistanceCriterion(value); err != nil || got != want {
+			t.Errorf("ParseDistanceCriterion(%q) = %v, %v, want %v", value, got, err, want)
+		}
+	}
+	if _, err := ParseDistanceCriterion("hamming"); err == nil {
+		t.Error("expected an error for an unknown metric")
+	}
+}
@@ -0,0 +1,317 @@
+package morphological
+
+import (
+	"fmt"
+	"imagio/manipulations"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a parsed morphology expression, a cleanup pipeline written as a single string such as
+//
+//	close(disk3) | open(iv) - erode(iii)
+//
+// Operations are written as name(se), name(se, iterations), name(se, expr) or name(se, iterations, expr)
+// where name is dilate, erode, open, close, gradient, tophat, blackhat or asf (whose iterations is its size)
+// and expr defaults to the input image, which can also be referred to as x. The element is anything
+// ResolveStructureElement accepts except inline matrices, disk3 and diamond3 are short for disk:3 and diamond:3.
+// Results are combined with | (union), & (intersection), - (difference) and ^ (xor), evaluated from left
+// to right unless grouped with parentheses, and ~ complements the following term.
+type Expression struct {
+	source string
+	root   expressionNode
+}
+
+type expressionNode interface {
+	evaluate(input *PackedImage) (*PackedImage, error)
+}
+
+type inputNode struct{}
+
+func (inputNode) evaluate(input *PackedImage) (*PackedImage, error) {
+	return input, nil
+}
+
+type complementNode struct {
+	operand expressionNode
+}
+
+func (n complementNode) evaluate(input *PackedImage) (*PackedImage, error) {
+	operand, err := n.operand.evaluate(input)
+	if err != nil {
+		return nil, err
+	}
+	return operand.Complement(), nil
+}
+
+type setOperationNode struct {
+	operator    byte
+	left, right expressionNode
+}
+
+func (n setOperationNode) evaluate(input *PackedImage) (*PackedImage, error) {
+	left, err := n.left.evaluate(input)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.evaluate(input)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.operator {
+	case '|':
+		return left.Union(right), nil
+	case '&':
+		return left.Intersection(right), nil
+	case '-':
+		return left.Difference(right), nil
+	default:
+		return left.Xor(right), nil
+	}
+}
+
+type operationNode struct {
+	operation  Operation
+	asf        bool
+	se         StructuringElement
+	iterations int
+	operand    expressionNode
+}
+
+func (n operationNode) evaluate(input *PackedImage) (*PackedImage, error) {
+	operand, err := n.operand.evaluate(input)
+	if err != nil {
+		return nil, err
+	}
+	if n.asf {
+		return packedMorphology.alternatingSequentialFilter(operand, n.se, n.iterations, ASFOpenClose)
+	}
+	return packedMorphology.apply(operand, n.operation, n.se, n.iterations, manipulations.DefaultBorderPolicy)
+}
+
+var expressionOperations = map[string]Operation{
+	"dilate":   OperationDilation,
+	"dilation": OperationDilation,
+	"erode":    OperationErosion,
+	"erosion":  OperationErosion,
+	"open":     OperationOpening,
+	"opening":  OperationOpening,
+	"close":    OperationClosing,
+	"closing":  OperationClosing,
+	"gradient": OperationGradient,
+	"tophat":   OperationWhiteTopHat,
+	"blackhat": OperationBlackTopHat,
+}
+
+var shortShapePattern = regexp.MustCompile(`^(disk|diamond)(\d+)$`)
+
+type expressionParser struct {
+	source string
+	pos    int
+}
+
+// ParseExpression parses a morphology expression and resolves its structuring elements.
+func ParseExpression(source string) (*Expression, error) {
+	parser := &expressionParser{source: source}
+	root, err := parser.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if parser.skipSpaces(); parser.pos < len(source) {
+		return nil, parser.errorf("unexpected %q", source[parser.pos])
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Evaluate runs the expression with img as its input, img is not modified.
+func (e *Expression) Evaluate(img BinaryImage) (BinaryImage, error) {
+	result, err := e.root.evaluate(Pack(img))
+	if err != nil {
+		return nil, err
+	}
+	return result.Unpack(), nil
+}
+
+func (p *expressionParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("morphology expression at position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.source) && unicode.IsSpace(rune(p.source[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume skips spaces and the given character if it comes next.
+func (p *expressionParser) consume(c byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.source) && p.source[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) expect(c byte) error {
+	if !p.consume(c) {
+		if p.pos >= len(p.source) {
+			return p.errorf("expected %q, got the end of the expression", c)
+		}
+		return p.errorf("expected %q, got %q", c, p.source[p.pos])
+	}
+	return nil
+}
+
+func (p *expressionParser) parseExpression() (expressionNode, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.source) || !strings.ContainsRune("|&-^", rune(p.source[p.pos])) {
+			return left, nil
+		}
+		operator := p.source[p.pos]
+		p.pos++
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = setOperationNode{operator: operator, left: left, right: right}
+	}
+}
+
+func (p *expressionParser) parseTerm() (expressionNode, error) {
+	if p.consume('~') {
+		operand, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		return complementNode{operand: operand}, nil
+	}
+
+	if p.consume('(') {
+		node, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		return node, p.expect(')')
+	}
+
+	name := p.parseName()
+	switch {
+	case name == "":
+		if p.pos >= len(p.source) {
+			return nil, p.errorf("expected an operation or x, got the end of the expression")
+		}
+		return nil, p.errorf("expected an operation or x, got %q", p.source[p.pos])
+	case name == "x" || name == "img":
+		return inputNode{}, nil
+	}
+	return p.parseOperation(name)
+}
+
+func (p *expressionParser) parseName() string {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.source) && (unicode.IsLetter(rune(p.source[p.pos])) || p.source[p.pos] == '_') {
+		p.pos++
+	}
+	return strings.ToLower(p.source[start:p.pos])
+}
+
+func (p *expressionParser) parseOperation(name string) (expressionNode, error) {
+	node := operationNode{iterations: 1, operand: inputNode{}}
+
+	if name == "asf" {
+		node.asf = true
+	} else if operation, ok := expressionOperations[name]; ok {
+		node.operation = operation
+	} else {
+		return nil, p.errorf("unknown operation %q", name)
+	}
+
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	se, err := p.parseStructureElement()
+	if err != nil {
+		return nil, err
+	}
+	node.se = se
+
+	if p.consume(',') {
+		p.skipSpaces()
+		start := p.pos
+		for p.pos < len(p.source) && unicode.IsDigit(rune(p.source[p.pos])) {
+			p.pos++
+		}
+
+		if start != p.pos {
+			node.iterations, _ = strconv.Atoi(p.source[start:p.pos])
+			if node.iterations < 1 {
+				return nil, p.errorf("%s needs at least one iteration", name)
+			}
+			if !p.consume(',') {
+				return node, p.expect(')')
+			}
+		}
+
+		if node.operand, err = p.parseExpression(); err != nil {
+			return nil, err
+		}
+	}
+
+	return node, p.expect(')')
+}
+
+// parseStructureElement reads the element up to the next ',' or ')', element names and shapes may contain '-' and ':'.
+func (p *expressionParser) parseStructureElement() (StructuringElement, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.source) && p.source[p.pos] != ',' && p.source[p.pos] != ')' {
+		p.pos++
+	}
+
+	name := strings.TrimSpace(p.source[start:p.pos])
+	if name == "" {
+		return StructuringElement{}, p.errorf("missing structuring element")
+	}
+	if IsInlineStructureElement(name) {
+		return StructuringElement{}, p.errorf("inline structuring elements are not supported in expressions, got %q", name)
+	}
+
+	if match := shortShapePattern.FindStringSubmatch(strings.ToLower(name)); match != nil {
+		if _, err := GetStructureElement(name); err != nil {
+			name = match[1] + ":" + match[2]
+		}
+	}
+
+	se, err := ResolveStructureElement(name)
+	if err != nil {
+		return StructuringElement{}, p.errorf("%v", err)
+	}
+	return se, nil
+}
+
+var nonLabelCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ExpressionLabel turns an expression into a string of at most 64 characters usable in file names.
+func ExpressionLabel(source string) string {
+	label := strings.Trim(nonLabelCharacters.ReplaceAllString(strings.ToLower(source), "_"), "_")
+	if len(label) > 64 {
+		label = strings.TrimRight(label[:64], "_")
+	}
+	return label
+}
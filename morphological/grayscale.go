@@ -0,0 +1,259 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+)
+
+// GrayImage holds intensities from 0 to 255 indexed as [row][col], like BinaryImage.
+type GrayImage [][]int
+
+// Mode selects whether the morphological operations work on the binarized image or on intensities.
+type Mode string
+
+const (
+	// ModeBinary binarizes the image first, every non-black pixel is foreground.
+	ModeBinary Mode = "binary"
+	// ModeGray applies the grayscale operations to every RGB channel separately.
+	ModeGray Mode = "gray"
+)
+
+// ParseMode parses "binary" or "gray", an empty string selects binary mode.
+func ParseMode(value string) (Mode, error) {
+	switch value {
+	case "", string(ModeBinary):
+		return ModeBinary, nil
+	case string(ModeGray):
+		return ModeGray, nil
+	default:
+		return "", fmt.Errorf("unknown morphology mode %q, expected binary or gray", value)
+	}
+}
+
+// Operation names a single structuring element operation.
+type Operation string
+
+const (
+	OperationDilation Operation = "dilation"
+	OperationErosion  Operation = "erosion"
+	OperationOpening  Operation = "opening"
+	OperationClosing  Operation = "closing"
+	// OperationGradient is the difference between the dilation and the erosion, highlighting edges.
+	OperationGradient Operation = "gradient"
+	// OperationWhiteTopHat is the difference between the image and its opening, keeping bright details smaller than the SE.
+	OperationWhiteTopHat Operation = "tophat"
+	// OperationBlackTopHat is the difference between the closing and the image, keeping dark details smaller than the SE.
+	OperationBlackTopHat Operation = "blackhat"
+)
+
+func newGrayImage(rows, cols int) GrayImage {
+	img := make(GrayImage, rows)
+	for i := range img {
+		img[i] = make([]int, cols)
+	}
+	return img
+}
+
+// ConvertIntoGrayImage converts img into luma intensities.
+func ConvertIntoGrayImage(img image.Image) GrayImage {
+	bounds := img.Bounds()
+	grayImage := newGrayImage(bounds.Dy(), bounds.Dx())
+
+	for y := range grayImage {
+		for x := range grayImage[y] {
+			gray := color.GrayModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+			grayImage[y][x] = int(gray.Y)
+		}
+	}
+
+	return grayImage
+}
+
+// ConvertGrayIntoImage converts intensities into a gray RGBA image.
+func ConvertGrayIntoImage(grayImage GrayImage) *image.RGBA {
+	return MergeChannels([3]GrayImage{grayImage, grayImage, grayImage})
+}
+
+// SplitChannels separates the red, green and blue channels of img.
+func SplitChannels(img image.Image) [3]GrayImage {
+	bounds := img.Bounds()
+
+	var channels [3]GrayImage
+	for c := range channels {
+		channels[c] = newGrayImage(bounds.Dy(), bounds.Dx())
+	}
+
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			channels[0][y][x] = int(r >> 8)
+			channels[1][y][x] = int(g >> 8)
+			channels[2][y][x] = int(b >> 8)
+		}
+	}
+
+	return channels
+}
+
+// MergeChannels joins the red, green and blue channels into an opaque image.
+func MergeChannels(channels [3]GrayImage) *image.RGBA {
+	height := len(channels[0])
+	width := 0
+	if height > 0 {
+		width = len(channels[0][0])
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(channels[0][y][x]), uint8(channels[1][y][x]), uint8(channels[2][y][x]), 255})
+		}
+	}
+
+	return img
+}
+
+// height returns the value the SE adds at (i, j), zero for flat elements.
+func (se StructuringElement) height(i, j int) int {
+	if i < len(se.Heights) && j < len(se.Heights[i]) {
+		return se.Heights[i][j]
+	}
+	return 0
+}
+
+func clampIntensity(value int) int {
+	return min(max(value, 0), 255)
+}
+
+// GrayDilation replaces every pixel by the maximum of f(p-d)+h(d) over the SE offsets d, pixels outside of
+// the image are taken from the border policy and ignored with BorderSkip. Without heights this is the maximum
+// filter over the reflected SE, computed for flat rectangles in a constant time per pixel.
+func GrayDilation(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return grayWithBorder(img, se, border, func(img GrayImage) GrayImage { return grayDilation(img, se) })
+}
+
+func grayDilation(img GrayImage, se StructuringElement) GrayImage {
+	if _, _, ok := se.Decompose(); ok && se.isFlat() && len(img) > 0 {
+		return grayRectangleFilter(img, se, true)
+	}
+
+	rows := len(img)
+	cols := len(img[0])
+	output := newGrayImage(rows, cols)
+
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			value := -1
+			for i := 0; i < len(se.Data); i++ {
+				for j := 0; j < len(se.Data[i]); j++ {
+					if se.Data[i][j] != 1 {
+						continue
+					}
+					srcX := x - (i - se.OriginX)
+					srcY := y - (j - se.OriginY)
+					if srcX >= 0 && srcX < rows && srcY >= 0 && srcY < cols {
+						value = max(value, img[srcX][srcY]+se.height(i, j))
+					}
+				}
+			}
+			output[x][y] = clampIntensity(value)
+		}
+	}
+
+	return output
+}
+
+// GrayErosion replaces every pixel by the minimum of f(p+d)-h(d) over the SE offsets d, pixels outside of
+// the image are taken from the border policy and ignored with BorderSkip. Without heights this is the minimum
+// filter over the SE, computed for flat rectangles in a constant time per pixel.
+func GrayErosion(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return grayWithBorder(img, se, border, func(img GrayImage) GrayImage { return grayErosion(img, se) })
+}
+
+func grayErosion(img GrayImage, se StructuringElement) GrayImage {
+	if _, _, ok := se.Decompose(); ok && se.isFlat() && len(img) > 0 {
+		return grayRectangleFilter(img, se, false)
+	}
+
+	rows := len(img)
+	cols := len(img[0])
+	output := newGrayImage(rows, cols)
+
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			value := 256
+			for i := 0; i < len(se.Data); i++ {
+				for j := 0; j < len(se.Data[i]); j++ {
+					if se.Data[i][j] != 1 {
+						continue
+					}
+					srcX := x + i - se.OriginX
+					srcY := y + j - se.OriginY
+					if srcX >= 0 && srcX < rows && srcY >= 0 && srcY < cols {
+						value = min(value, img[srcX][srcY]-se.height(i, j))
+					}
+				}
+			}
+			output[x][y] = clampIntensity(value)
+		}
+	}
+
+	return output
+}
+
+func GrayOpening(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return GrayDilation(GrayErosion(img, se, border), se, border)
+}
+
+func GrayClosing(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return GrayErosion(GrayDilation(img, se, border), se, border)
+}
+
+// subtractGray returns img1-img2 clamped to the intensity range.
+func subtractGray(img1, img2 GrayImage) GrayImage {
+	output := newGrayImage(len(img1), len(img1[0]))
+	for i := range output {
+		for j := range output[i] {
+			output[i][j] = clampIntensity(img1[i][j] - img2[i][j])
+		}
+	}
+	return output
+}
+
+// GrayGradient is the morphological gradient, the dilation minus the erosion.
+func GrayGradient(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return subtractGray(GrayDilation(img, se, border), GrayErosion(img, se, border))
+}
+
+// GrayWhiteTopHat is the image minus its opening.
+func GrayWhiteTopHat(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return subtractGray(img, GrayOpening(img, se, border))
+}
+
+// GrayBlackTopHat is the closing of the image minus the image.
+func GrayBlackTopHat(img GrayImage, se StructuringElement, border manipulations.BorderPolicy) GrayImage {
+	return subtractGray(GrayClosing(img, se, border), img)
+}
+
+// Gradient is the binary morphological gradient, the dilation minus the erosion.
+func Gradient(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Difference(Dilation(image, se, border), Erosion(image, se, border))
+}
+
+// WhiteTopHat keeps the foreground removed by the opening.
+func WhiteTopHat(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Difference(image, Opening(image, se, border))
+}
+
+// BlackTopHat keeps the background filled by the closing.
+func BlackTopHat(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Difference(Closing(image, se, border), image)
+}
+
+// ApplyOperation runs the operation on img in the given mode. Binary mode binarizes img first,
+// gray mode processes the red, green and blue channels independently, so gray inputs stay gray.
+func ApplyOperation(img image.Image, operation Operation, se StructuringElement, mode Mode, border manipulations.BorderPolicy) (*image.RGBA, error) {
+	return ApplyIteratedOperation(img, operation, se, mode, 1, border)
+}
@@ -0,0 +1,188 @@
+package morphological
+
+import (
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"reflect"
+	"testing"
+)
+
+var flatCross = StructuringElement{
+	Data:    [][]int{{0, 1, 0}, {1, 1, 1}, {0, 1, 0}},
+	OriginX: 1,
+	OriginY: 1,
+}
+
+func TestGrayDilationErosionFlat(t *testing.T) {
+	img := GrayImage{
+		{0, 0, 0, 0},
+		{0, 100, 0, 0},
+		{0, 0, 0, 50},
+	}
+
+	wantDilated := GrayImage{
+		{0, 100, 0, 0},
+		{100, 100, 100, 50},
+		{0, 100, 50, 50},
+	}
+	if got := GrayDilation(img, flatCross, manipulations.DefaultBorderPolicy); !reflect.DeepEqual(got, wantDilated) {
+		t.Errorf("GrayDilation() = %v, want %v", got, wantDilated)
+	}
+
+	bright := GrayImage{
+		{200, 200, 200},
+		{200, 10, 200},
+		{200, 200, 200},
+	}
+	wantEroded := GrayImage{
+		{200, 10, 200},
+		{10, 10, 10},
+		{200, 10, 200},
+	}
+	if got := GrayErosion(bright, flatCross, manipulations.DefaultBorderPolicy); !reflect.DeepEqual(got, wantEroded) {
+		t.Errorf("GrayErosion() = %v, want %v", got, wantEroded)
+	}
+}
+
+func TestGrayDilationNonFlatClamps(t *testing.T) {
+	se := StructuringElement{
+		Data:    [][]int{{1, 1, 1}},
+		Heights: [][]int{{5, 10, 5}},
+		OriginX: 0,
+		OriginY: 1,
+	}
+	img := GrayImage{{0, 250, 0}}
+
+	if got, want := GrayDilation(img, se, manipulations.DefaultBorderPolicy), (GrayImage{{255, 255, 255}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("GrayDilation() = %v, want %v", got, want)
+	}
+	if got, want := GrayErosion(img, se, manipulations.DefaultBorderPolicy), (GrayImage{{0, 0, 0}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("GrayErosion() = %v, want %v", got, want)
+	}
+}
+
+func TestGrayDilationErosionBorderPolicies(t *testing.T) {
+	img := GrayImage{{10, 20, 30}}
+	line := StructuringElement{Data: [][]int{{1, 1, 1}}, OriginX: 0, OriginY: 1}
+	// a trailing row of zeros keeps the offsets but takes the general loop instead of the rectangle filter
+	general := line
+	general.Data = [][]int{{1, 1, 1}, {0, 0, 0}}
+
+	tests := []struct {
+		policy                  string
+		wantDilated, wantEroded GrayImage
+	}{
+		{"skip", GrayImage{{20, 30, 30}}, GrayImage{{10, 10, 20}}},
+		{"clamp", GrayImage{{20, 30, 30}}, GrayImage{{10, 10, 20}}},
+		{"wrap", GrayImage{{30, 30, 30}}, GrayImage{{10, 10, 10}}},
+		{"constant:255", GrayImage{{255, 30, 255}}, GrayImage{{10, 10, 20}}},
+		{"constant:0", GrayImage{{20, 30, 30}}, GrayImage{{0, 10, 0}}},
+	}
+
+	for _, tt := range tests {
+		border, err := manipulations.ParseBorderPolicy(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, se := range []StructuringElement{line, general} {
+			if got := GrayDilation(img, se, border); !reflect.DeepEqual(got, tt.wantDilated) {
+				t.Errorf("%s: GrayDilation() = %v, want %v", tt.policy, got, tt.wantDilated)
+			}
+			if got := GrayErosion(img, se, border); !reflect.DeepEqual(got, tt.wantEroded) {
+				t.Errorf("%s: GrayErosion() = %v, want %v", tt.policy, got, tt.wantEroded)
+			}
+		}
+	}
+}
+
+func TestGrayTopHats(t *testing.T) {
+	// a single bright pixel is removed by the opening and a single dark one filled by the closing
+	img := GrayImage{
+		{50, 50, 50, 50, 50},
+		{50, 200, 50, 50, 50},
+		{50, 50, 50, 0, 50},
+		{50, 50, 50, 50, 50},
+	}
+
+	white := GrayWhiteTopHat(img, flatCross, manipulations.DefaultBorderPolicy)
+	black := GrayBlackTopHat(img, flatCross, manipulations.DefaultBorderPolicy)
+	gradient := GrayGradient(img, flatCross, manipulations.DefaultBorderPolicy)
+
+	for y := range img {
+		for x := range img[y] {
+			wantWhite, wantBlack := 0, 0
+			if x == 1 && y == 1 {
+				wantWhite = 150
+			}
+			if x == 3 && y == 2 {
+				wantBlack = 50
+			}
+			if white[y][x] != wantWhite {
+				t.Errorf("white top-hat at (%d, %d) = %d, want %d", x, y, white[y][x], wantWhite)
+			}
+			if black[y][x] != wantBlack {
+				t.Errorf("black top-hat at (%d, %d) = %d, want %d", x, y, black[y][x], wantBlack)
+			}
+		}
+	}
+
+	if gradient[1][1] != 150 || gradient[0][0] != 0 {
+		t.Errorf("gradient = %v, expected 150 at the bright pixel and 0 in flat areas", gradient)
+	}
+}
+
+func TestBinaryGradientKeepsInput(t *testing.T) {
+	img := binaryFromRows(
+		".....",
+		".###.",
+		".###.",
+		".###.",
+		".....",
+	)
+	input := binaryFromRows(
+		".....",
+		".###.",
+		".###.",
+		".###.",
+		".....",
+	)
+
+	want := binaryFromRows(
+		".###.",
+		"#####",
+		"##.##",
+		"#####",
+		".###.",
+	)
+	if got := Gradient(img, flatCross, manipulations.DefaultBorderPolicy); !reflect.DeepEqual(got, want) {
+		t.Errorf("Gradient() = %v, want %v", got, want)
+	}
+	if !reflect.DeepEqual(img, input) {
+		t.Error("Gradient() modified its input")
+	}
+}
+
+func TestApplyOperationGrayPerChannel(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 3))
+	img.SetRGBA(1, 1, color.RGBA{200, 0, 100, 255})
+
+	out, err := ApplyOperation(img, OperationDilation, flatCross, ModeGray, manipulations.DefaultBorderPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := out.RGBAAt(1, 0), (color.RGBA{200, 0, 100, 255}); got != want {
+		t.Errorf("pixel (1, 0) = %v, want %v", got, want)
+	}
+	if got, want := out.RGBAAt(0, 0), (color.RGBA{0, 0, 0, 255}); got != want {
+		t.Errorf("pixel (0, 0) = %v, want %v", got, want)
+	}
+
+	if _, err := ApplyOperation(img, Operation("unknown"), flatCross, ModeGray, manipulations.DefaultBorderPolicy); err == nil {
+		t.Error("expected an error for an unknown operation")
+	}
+	if _, err := ParseMode("color"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
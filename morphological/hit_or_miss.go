@@ -0,0 +1,298 @@
+package morphological
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"imagio/manipulations"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HitOrMissPair holds the element that has to fit the foreground and the one that has to fit the background.
+type HitOrMissPair struct {
+	Foreground StructuringElement
+	Background StructuringElement
+}
+
+const inlineElementCharacters = "01xX-.,;/ "
+
+// IsInlineStructureElement reports whether value is an inline matrix such as 1,1,1;x,1,x;0,0,0.
+func IsInlineStructureElement(value string) bool {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return false
+	}
+	for _, r := range value {
+		if !strings.ContainsRune(inlineElementCharacters, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseInlineMatrix reads rows separated by ';' or '/' of values separated by ',' or spaces,
+// 1 and 0 are kept and the don't care values x, - and . become -1.
+func parseInlineMatrix(value string) ([][]int, error) {
+	rows := strings.FieldsFunc(value, func(r rune) bool { return r == ';' || r == '/' })
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("inline structure element %q has no rows", value)
+	}
+
+	matrix := make([][]int, len(rows))
+	for i, row := range rows {
+		for _, token := range strings.FieldsFunc(row, func(r rune) bool { return r == ',' || r == ' ' }) {
+			switch strings.ToLower(token) {
+			case "1":
+				matrix[i] = append(matrix[i], 1)
+			case "0":
+				matrix[i] = append(matrix[i], 0)
+			case "x", "-", "-1", ".":
+				matrix[i] = append(matrix[i], -1)
+			default:
+				return nil, fmt.Errorf("invalid value %q in inline structure element %q, expected 1, 0 or x", token, value)
+			}
+		}
+		if len(matrix[i]) == 0 || len(matrix[i]) != len(matrix[0]) {
+			return nil, fmt.Errorf("rows of inline structure element %q must have the same, non-zero length", value)
+		}
+	}
+	return matrix, nil
+}
+
+// elementFromMatrix keeps the cells of the matrix equal to value, the origin is in the center.
+func elementFromMatrix(matrix [][]int, value int) StructuringElement {
+	se := StructuringElement{Data: make([][]int, len(matrix)), OriginX: (len(matrix) - 1) / 2, OriginY: (len(matrix[0]) - 1) / 2}
+	for i, row := range matrix {
+		se.Data[i] = make([]int, len(row))
+		for j, cell := range row {
+			if cell == value {
+				se.Data[i][j] = 1
+			}
+		}
+	}
+	return se
+}
+
+// ParseInlineStructureElement builds an element from an inline matrix with the origin in its center,
+// the cells set to 1 belong to the element while 0 and the don't care values do not.
+func ParseInlineStructureElement(value string) (StructuringElement, error) {
+	matrix, err := parseInlineMatrix(value)
+	if err != nil {
+		return StructuringElement{}, err
+	}
+	return elementFromMatrix(matrix, 1), nil
+}
+
+// ParseHitOrMissTemplate splits an inline template into the pair matched by the hit-or-miss transformation:
+// 1 has to be foreground, 0 has to be background and x, - or . does not matter.
+func ParseHitOrMissTemplate(value string) (HitOrMissPair, error) {
+	matrix, err := parseInlineMatrix(value)
+	if err != nil {
+		return HitOrMissPair{}, err
+	}
+	return HitOrMissPair{Foreground: elementFromMatrix(matrix, 1), Background: elementFromMatrix(matrix, 0)}, nil
+}
+
+// LoadStructureElementFromJSON reads a file holding either a single element or a map with exactly one element.
+func LoadStructureElementFromJSON(filenamePath string) (StructuringElement, error) {
+	bytes, err := os.ReadFile(filenamePath)
+	if err != nil {
+		return StructuringElement{}, fmt.Errorf("could not read structure element file: %w", err)
+	}
+
+	var single StructuringElement
+	if err := json.Unmarshal(bytes, &single); err == nil && single.Data != nil {
+		return single, single.Validate()
+	}
+
+	elements, err := LoadStructureElementsFromJSON(filenamePath)
+	if err != nil {
+		return StructuringElement{}, err
+	}
+	if len(elements) != 1 {
+		return StructuringElement{}, fmt.Errorf("%s holds %d structure elements, load it with -sefile and pick one by name", filenamePath, len(elements))
+	}
+	for _, se := range elements {
+		return se, nil
+	}
+	return StructuringElement{}, nil
+}
+
+func isStructureElementFile(value string) bool {
+	if strings.EqualFold(filepath.Ext(value), ".json") {
+		return true
+	}
+	info, err := os.Stat(value)
+	return err == nil && !info.IsDir()
+}
+
+// ResolveStructureElement accepts an inline matrix, a path to a JSON file, a shape specification or
+// the name of an available element.
+func ResolveStructureElement(value string) (StructuringElement, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case IsInlineStructureElement(value):
+		return ParseInlineStructureElement(value)
+	case isStructureElementFile(value):
+		return LoadStructureElementFromJSON(value)
+	default:
+		return GetStructureElement(value)
+	}
+}
+
+// rotateMatrix90 turns the matrix by 90 degrees clockwise.
+func rotateMatrix90(matrix [][]int) [][]int {
+	if matrix == nil {
+		return nil
+	}
+	rows, cols := len(matrix), len(matrix[0])
+	rotated := make([][]int, cols)
+	for i := range rotated {
+		rotated[i] = make([]int, rows)
+		for j := range rotated[i] {
+			rotated[i][j] = matrix[rows-1-j][i]
+		}
+	}
+	return rotated
+}
+
+// ringPositions lists the cells at chessboard distance k from the center of a square matrix, clockwise from the top-left corner.
+func ringPositions(center, k int) []Point {
+	positions := make([]Point, 0, 8*k)
+	for x := -k; x < k; x++ {
+		positions = append(positions, Point{X: center + x, Y: center - k})
+	}
+	for y := -k; y < k; y++ {
+		positions = append(positions, Point{X: center + k, Y: center + y})
+	}
+	for x := k; x > -k; x-- {
+		positions = append(positions, Point{X: center + x, Y: center + k})
+	}
+	for y := k; y > -k; y-- {
+		positions = append(positions, Point{X: center - k, Y: center + y})
+	}
+	return positions
+}
+
+// rotateMatrix45 turns a square matrix of odd size by 45 degrees clockwise, shifting every ring around
+// the center by its distance to it, which is exact for 3x3 matrices and an approximation otherwise.
+func rotateMatrix45(matrix [][]int) [][]int {
+	if matrix == nil {
+		return nil
+	}
+	size := len(matrix)
+	center := size / 2
+	rotated := make([][]int, size)
+	for i := range rotated {
+		rotated[i] = make([]int, size)
+	}
+	rotated[center][center] = matrix[center][center]
+
+	for k := 1; k <= center; k++ {
+		ring := ringPositions(center, k)
+		for index, from := range ring {
+			to := ring[(index+k)%len(ring)]
+			rotated[to.Y][to.X] = matrix[from.Y][from.X]
+		}
+	}
+	return rotated
+}
+
+// Rotate90 returns the element turned by 90 degrees clockwise around its origin.
+func (se StructuringElement) Rotate90() StructuringElement {
+	return StructuringElement{
+		Data:    rotateMatrix90(se.Data),
+		Heights: rotateMatrix90(se.Heights),
+		OriginX: se.OriginY,
+		OriginY: len(se.Data) - 1 - se.OriginX,
+	}
+}
+
+// Rotate45 returns the element turned by 45 degrees clockwise, it has to be square with the origin in the center.
+func (se StructuringElement) Rotate45() (StructuringElement, error) {
+	size := len(se.Data)
+	if size%2 == 0 || len(se.Data[0]) != size || se.OriginX != size/2 || se.OriginY != size/2 {
+		return StructuringElement{}, errors.New("45 degree rotations need a square element of odd size with the origin in its center")
+	}
+	return StructuringElement{Data: rotateMatrix45(se.Data), Heights: rotateMatrix45(se.Heights), OriginX: se.OriginX, OriginY: se.OriginY}, nil
+}
+
+func sameElement(a, b StructuringElement) bool {
+	if a.OriginX != b.OriginX || a.OriginY != b.OriginY || len(a.Data) != len(b.Data) {
+		return false
+	}
+	for i := range a.Data {
+		if len(a.Data[i]) != len(b.Data[i]) {
+			return false
+		}
+		for j := range a.Data[i] {
+			if a.Data[i][j] != b.Data[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Rotations returns the distinct pairs obtained by turning both elements together, count is 1, 4 (steps of 90 degrees)
+// or 8 (steps of 45 degrees, for square elements with the origin in the center).
+func (pair HitOrMissPair) Rotations(count int) ([]HitOrMissPair, error) {
+	var rotate func(se StructuringElement) (StructuringElement, error)
+	switch count {
+	case 1:
+		return []HitOrMissPair{pair}, nil
+	case 4:
+		rotate = func(se StructuringElement) (StructuringElement, error) { return se.Rotate90(), nil }
+	case 8:
+		rotate = StructuringElement.Rotate45
+	default:
+		return nil, fmt.Errorf("the number of rotations must be 1, 4 or 8, got %d", count)
+	}
+
+	rotations := []HitOrMissPair{pair}
+	current := pair
+	for step := 1; step < count; step++ {
+		foreground, err := rotate(current.Foreground)
+		if err != nil {
+			return nil, err
+		}
+		background, err := rotate(current.Background)
+		if err != nil {
+			return nil, err
+		}
+		current = HitOrMissPair{Foreground: foreground, Background: background}
+
+		duplicate := false
+		for _, existing := range rotations {
+			if sameElement(existing.Foreground, current.Foreground) && sameElement(existing.Background, current.Background) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			rotations = append(rotations, current)
+		}
+	}
+	return rotations, nil
+}
+
+// HitOrMissUnion marks the pixels matched by any of the pairs, the input image is left unchanged.
+// Pixels outside of the image are taken from the border policy, see HitOrMiss.
+func HitOrMissUnion(img BinaryImage, pairs []HitOrMissPair, border manipulations.BorderPolicy) BinaryImage {
+	output := make(BinaryImage, len(img))
+	for i := range output {
+		output[i] = make([]int, len(img[i]))
+	}
+
+	for _, pair := range pairs {
+		matched := HitOrMiss(img, pair.Foreground, pair.Background, border)
+		for y := range matched {
+			for x, value := range matched[y] {
+				output[y][x] |= value
+			}
+		}
+	}
+	return output
+}
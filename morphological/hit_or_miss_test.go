@@ -0,0 +1,136 @@
+package morphological
+
+import (
+	"imagio/manipulations"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseHitOrMissTemplate(t *testing.T) {
+	pair, err := ParseHitOrMissTemplate("x,1,x;0,1,0;0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := elementRows(pair.Foreground); got[0] != ".#." || got[1] != ".o." || got[2] != "..." {
+		t.Errorf("foreground = %v", got)
+	}
+	if got := elementRows(pair.Background); got[0] != "..." || got[1] != "#o#" || got[2] != "###" {
+		t.Errorf("background = %v", got)
+	}
+
+	for _, value := range []string{"1,1;1", "1,2,1", ";"} {
+		if _, err := ParseHitOrMissTemplate(value); err == nil {
+			t.Errorf("ParseHitOrMissTemplate(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestRotations(t *testing.T) {
+	pair, err := ParseHitOrMissTemplate("x,1,x/0,1,0/0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotations, err := pair.Rotations(8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotations) != 8 {
+		t.Fatalf("got %d rotations, want 8", len(rotations))
+	}
+	if got := elementRows(rotations[1].Foreground); got[0] != "..#" || got[1] != ".o." || got[2] != "..." {
+		t.Errorf("45 degree rotation = %v", got)
+	}
+	if got := elementRows(rotations[2].Foreground); got[0] != "..." || got[1] != ".o#" || got[2] != "..." {
+		t.Errorf("90 degree rotation = %v", got)
+	}
+
+	symmetric, err := ParseHitOrMissTemplate("0,0,0;0,1,0;0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotations, _ := symmetric.Rotations(8); len(rotations) != 1 {
+		t.Errorf("an isolated point template has %d distinct rotations, want 1", len(rotations))
+	}
+
+	if _, err := pair.Rotations(3); err == nil {
+		t.Error("expected an error for 3 rotations")
+	}
+}
+
+func TestRotate90KeepsOrigin(t *testing.T) {
+	se := StructuringElement{Data: [][]int{{1, 1, 1}}, OriginX: 0, OriginY: 0}
+
+	rotated := se.Rotate90()
+	if got := elementRows(rotated); len(got) != 3 || got[0] != "o" || got[2] != "#" {
+		t.Errorf("Rotate90() = %v", got)
+	}
+	if _, err := se.Rotate45(); err == nil {
+		t.Error("expected an error rotating a non-square element by 45 degrees")
+	}
+}
+
+func TestHitOrMissUnionFindsLineEnds(t *testing.T) {
+	img := binaryFromRows(
+		"..........",
+		".######...",
+		"..........",
+		"....#.....",
+		"....#.....",
+		"....#.....",
+		"..........",
+	)
+
+	pair, err := ParseHitOrMissTemplate("x,x,x;0,1,0;0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	single := HitOrMissUnion(img, []HitOrMissPair{pair}, manipulations.DefaultBorderPolicy)
+	if foregroundCount(single) != 1 || single[5][4] != 1 {
+		t.Errorf("template matched %d pixels, want only the lower end of the vertical line", foregroundCount(single))
+	}
+
+	rotations, err := pair.Rotations(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all := HitOrMissUnion(img, rotations, manipulations.DefaultBorderPolicy)
+	if foregroundCount(all) != 4 || all[1][1] != 1 || all[1][6] != 1 || all[3][4] != 1 || all[5][4] != 1 {
+		t.Errorf("rotated templates matched %d pixels, want the 4 line ends", foregroundCount(all))
+	}
+
+	if foregroundCount(img) != 9 {
+		t.Error("HitOrMissUnion() modified its input")
+	}
+}
+
+func TestResolveStructureElement(t *testing.T) {
+	inline, err := ResolveStructureElement("1,1;1,x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inline.Data[1][1] != 0 || inline.Data[1][0] != 1 {
+		t.Errorf("inline element = %v", inline.Data)
+	}
+
+	path := filepath.Join(t.TempDir(), "corner.json")
+	if err := os.WriteFile(path, []byte(`{"data": [[1, 1], [1, 0]], "originX": 0, "originY": 0}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := ResolveStructureElement(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromFile.OriginX != 0 || fromFile.Data[0][1] != 1 {
+		t.Errorf("element from file = %+v", fromFile)
+	}
+	if label := StructureElementLabel(path); label != "corner" {
+		t.Errorf("StructureElementLabel(%q) = %q, want corner", path, label)
+	}
+
+	if _, err := ResolveStructureElement("xi-l"); err != nil {
+		t.Errorf("named element: %v", err)
+	}
+}
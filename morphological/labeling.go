@@ -0,0 +1,241 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// Connectivity decides which neighbours of a pixel belong to the same component.
+type Connectivity int
+
+const (
+	// Connectivity4 joins pixels sharing an edge.
+	Connectivity4 Connectivity = 4
+	// Connectivity8 also joins pixels touching by a corner.
+	Connectivity8 Connectivity = 8
+)
+
+// ParseConnectivity parses "4" or "8", an empty string selects 8-connectivity.
+func ParseConnectivity(value string) (Connectivity, error) {
+	switch value {
+	case "", "8":
+		return Connectivity8, nil
+	case "4":
+		return Connectivity4, nil
+	default:
+		return 0, fmt.Errorf("unknown connectivity %q, expected 4 or 8", value)
+	}
+}
+
+// previousNeighbours are the neighbours visited before a pixel in row-major order.
+func (c Connectivity) previousNeighbours() []Point {
+	if c == Connectivity4 {
+		return []Point{{-1, 0}, {0, -1}}
+	}
+	return []Point{{-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+}
+
+// Component is a connected set of foreground pixels.
+type Component struct {
+	Label  int
+	Area   int
+	Box    BoundingBox
+	Pixels []Point
+}
+
+// Labeling is the result of connected-component labeling. Labels[y][x] is 0 for background
+// and the label of the component otherwise, Components[i] has label i+1.
+type Labeling struct {
+	Labels     [][]int
+	Components []Component
+}
+
+func find(parent []int, label int) int {
+	for parent[label] != label {
+		parent[label] = parent[parent[label]]
+		label = parent[label]
+	}
+	return label
+}
+
+// LabelComponents labels the foreground components of the image with the classic two-pass algorithm,
+// labels are assigned in row-major order of the first pixel of every component.
+//
+// Reference: https://en.wikipedia.org/wiki/Connected-component_labeling#Two-pass
+func LabelComponents(img BinaryImage, connectivity Connectivity) Labeling {
+	rows := len(img)
+	if rows == 0 {
+		return Labeling{}
+	}
+	cols := len(img[0])
+
+	labels := make([][]int, rows)
+	for y := range labels {
+		labels[y] = make([]int, cols)
+	}
+
+	// parent[0] is the background, provisional labels start at 1
+	parent := []int{0}
+	neighbours := connectivity.previousNeighbours()
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if img[y][x] != 1 {
+				continue
+			}
+
+			label := 0
+			for _, d := range neighbours {
+				nx, ny := x+d.X, y+d.Y
+				if nx < 0 || nx >= cols || ny < 0 || labels[ny][nx] == 0 {
+					continue
+				}
+
+				neighbourRoot := find(parent, labels[ny][nx])
+				if label == 0 {
+					label = neighbourRoot
+					continue
+				}
+
+				root := find(parent, label)
+				if root != neighbourRoot {
+					parent[max(root, neighbourRoot)] = min(root, neighbourRoot)
+				}
+			}
+
+			if label == 0 {
+				label = len(parent)
+				parent = append(parent, label)
+			}
+
+			labels[y][x] = label
+		}
+	}
+
+	// second pass, resolve provisional labels and number the components consecutively
+	final := make([]int, len(parent))
+	var components []Component
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if labels[y][x] == 0 {
+				continue
+			}
+
+			root := find(parent, labels[y][x])
+			if final[root] == 0 {
+				components = append(components, Component{Label: len(components) + 1, Box: BoundingBox{X: x, Y: y, Width: 1, Height: 1}})
+				final[root] = len(components)
+			}
+
+			label := final[root]
+			labels[y][x] = label
+
+			c := &components[label-1]
+			c.Area++
+			c.Pixels = append(c.Pixels, Point{X: x, Y: y})
+			c.Box = c.Box.extend(x, y)
+		}
+	}
+
+	return Labeling{Labels: labels, Components: components}
+}
+
+// extend grows the box to include the pixel at (x, y).
+func (b BoundingBox) extend(x, y int) BoundingBox {
+	minX, minY := min(b.X, x), min(b.Y, y)
+	maxX, maxY := max(b.X+b.Width-1, x), max(b.Y+b.Height-1, y)
+	return BoundingBox{X: minX, Y: minY, Width: maxX - minX + 1, Height: maxY - minY + 1}
+}
+
+// FilterByArea keeps components with an area from minArea to maxArea and relabels them consecutively.
+// A maxArea of 0 or less leaves the area unbounded from above.
+func (l Labeling) FilterByArea(minArea, maxArea int) Labeling {
+	filtered := Labeling{Labels: make([][]int, len(l.Labels))}
+	for y := range l.Labels {
+		filtered.Labels[y] = make([]int, len(l.Labels[y]))
+	}
+
+	for _, c := range l.Components {
+		if c.Area < minArea || (maxArea > 0 && c.Area > maxArea) {
+			continue
+		}
+
+		c.Label = len(filtered.Components) + 1
+		for _, p := range c.Pixels {
+			filtered.Labels[p.Y][p.X] = c.Label
+		}
+		filtered.Components = append(filtered.Components, c)
+	}
+
+	return filtered
+}
+
+// BinaryImage returns the foreground of the labeled components.
+func (l Labeling) BinaryImage() BinaryImage {
+	img := make(BinaryImage, len(l.Labels))
+	for y, row := range l.Labels {
+		img[y] = make([]int, len(row))
+		for x, label := range row {
+			if label > 0 {
+				img[y][x] = 1
+			}
+		}
+	}
+	return img
+}
+
+// labelColor spreads hues by the golden angle so that neighbouring labels get clearly different colors,
+// saturation and lightness are fixed so that every label is visible on black.
+func labelColor(label int) color.RGBA {
+	const saturation, lightness = 0.85, 0.55
+
+	hue := math.Mod(float64(label)*137.508, 360)
+	c := (1 - math.Abs(2*lightness-1)) * saturation
+	h := hue / 60
+	x := c * (1 - math.Abs(math.Mod(h, 2)-1))
+	m := lightness - c/2
+
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g, b = c, x, 0
+	case 1:
+		r, g, b = x, c, 0
+	case 2:
+		r, g, b = 0, c, x
+	case 3:
+		r, g, b = 0, x, c
+	case 4:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+// Colorize paints every component in its own color on a black background.
+func (l Labeling) Colorize() *image.RGBA {
+	height := len(l.Labels)
+	width := 0
+	if height > 0 {
+		width = len(l.Labels[0])
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := 3; i < len(img.Pix); i += 4 {
+		img.Pix[i] = 255
+	}
+
+	for _, c := range l.Components {
+		col := labelColor(c.Label)
+		for _, p := range c.Pixels {
+			img.SetRGBA(p.X, p.Y, col)
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,83 @@
+package morphological
+
+import "testing"
+
+func binaryFromRows(rows ...string) BinaryImage {
+	img := make(BinaryImage, len(rows))
+	for y, row := range rows {
+		img[y] = make([]int, len(row))
+		for x, c := range row {
+			if c == '#' {
+				img[y][x] = 1
+			}
+		}
+	}
+	return img
+}
+
+func TestLabelComponentsConnectivity(t *testing.T) {
+	img := binaryFromRows(
+		"##..#",
+		"##.#.",
+		"..#..",
+		"#....",
+	)
+
+	eight := LabelComponents(img, Connectivity8)
+	if len(eight.Components) != 2 {
+		t.Fatalf("expected 2 8-connected components, got %d", len(eight.Components))
+	}
+	if eight.Components[0].Area != 7 || eight.Components[1].Area != 1 {
+		t.Errorf("unexpected areas %d and %d", eight.Components[0].Area, eight.Components[1].Area)
+	}
+	if eight.Labels[2][2] != eight.Labels[0][0] || eight.Labels[0][4] != eight.Labels[0][0] {
+		t.Error("diagonal neighbours must share a label with 8-connectivity")
+	}
+
+	four := LabelComponents(img, Connectivity4)
+	if len(four.Components) != 5 {
+		t.Fatalf("expected 5 4-connected components, got %d", len(four.Components))
+	}
+	if four.Labels[0][4] != 2 || four.Labels[3][0] != 5 {
+		t.Errorf("labels must follow row-major order, got %d and %d", four.Labels[0][4], four.Labels[3][0])
+	}
+}
+
+func TestLabelComponentsMergesUShape(t *testing.T) {
+	// the two arms get different provisional labels that are only joined in the last row
+	img := binaryFromRows(
+		"#...#",
+		"#...#",
+		"#####",
+	)
+
+	labeling := LabelComponents(img, Connectivity4)
+	if len(labeling.Components) != 1 {
+		t.Fatalf("expected a single component, got %d", len(labeling.Components))
+	}
+
+	c := labeling.Components[0]
+	if c.Label != 1 || c.Area != 9 || c.Box != (BoundingBox{X: 0, Y: 0, Width: 5, Height: 3}) {
+		t.Errorf("unexpected component %+v", c)
+	}
+}
+
+func TestLabelingFilterByArea(t *testing.T) {
+	img := binaryFromRows(
+		"#.##.###",
+		"........",
+	)
+
+	labeling := LabelComponents(img, Connectivity8).FilterByArea(2, 2)
+	if len(labeling.Components) != 1 || labeling.Components[0].Label != 1 || labeling.Components[0].Area != 2 {
+		t.Fatalf("expected only the component of area 2 relabeled as 1, got %+v", labeling.Components)
+	}
+	if labeling.Labels[0][0] != 0 || labeling.Labels[0][2] != 1 || labeling.Labels[0][5] != 0 {
+		t.Errorf("unexpected label row %v", labeling.Labels[0])
+	}
+
+	unbounded := LabelComponents(img, Connectivity8).FilterByArea(2, 0)
+	if len(unbounded.Components) != 2 {
+		t.Errorf("expected 2 components without an upper bound, got %d", len(unbounded.Components))
+	}
+}
@@ -0,0 +1,104 @@
+package morphological
+
+import (
+	"imagio/imageio"
+	"imagio/manipulations"
+	"path/filepath"
+	"testing"
+)
+
+// loadBenchmarkImages opens the binary test images shipped in imgs, named *bw.bmp.
+func loadBenchmarkImages(b *testing.B) map[string]BinaryImage {
+	paths, err := filepath.Glob(filepath.Join("..", "imgs", "*bw.bmp"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if len(paths) == 0 {
+		b.Skip("no imgs/*bw.bmp images found")
+	}
+
+	images := make(map[string]BinaryImage, len(paths))
+	for _, path := range paths {
+		img, err := imageio.OpenBmpImage(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		images[imageio.GetPureFileName(path)] = ConvertIntoBinaryImage(img)
+	}
+	return images
+}
+
+func benchmarkBinaryOperation(b *testing.B, naive func(BinaryImage, StructuringElement) BinaryImage, packed func(BinaryImage, StructuringElement, manipulations.BorderPolicy) BinaryImage) {
+	images := loadBenchmarkImages(b)
+
+	for _, seName := range []string{"iv", "iii", "rect:15x15", "disk:5"} {
+		se, err := GetStructureElement(seName)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		for imgName, img := range images {
+			b.Run(imgName+"/"+StructureElementLabel(seName)+"/naive", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					naive(img, se)
+				}
+			})
+			b.Run(imgName+"/"+StructureElementLabel(seName)+"/packed", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					packed(img, se, manipulations.DefaultBorderPolicy)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDilation(b *testing.B) {
+	benchmarkBinaryOperation(b, naiveDilation, Dilation)
+}
+
+func BenchmarkErosion(b *testing.B) {
+	benchmarkBinaryOperation(b, naiveErosion, Erosion)
+}
+
+// BenchmarkPackedDilation leaves out the conversion from and into BinaryImage.
+func BenchmarkPackedDilation(b *testing.B) {
+	images := loadBenchmarkImages(b)
+	se, err := GetStructureElement("rect:15x15")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for imgName, img := range images {
+		packed := Pack(img)
+		b.Run(imgName, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				packed.Dilate(se, manipulations.DefaultBorderPolicy)
+			}
+		})
+	}
+}
+
+func BenchmarkGrayDilation(b *testing.B) {
+	images := loadBenchmarkImages(b)
+	rect, err := GetStructureElement("rect:15x15")
+	if err != nil {
+		b.Fatal(err)
+	}
+	// the same offsets with a row of zeros appended, which is not decomposed
+	general := rect
+	general.Data = append(append([][]int(nil), rect.Data...), make([]int, 15))
+
+	for imgName, img := range images {
+		gray := GrayImage(img)
+		b.Run(imgName+"/general", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				GrayDilation(gray, general, manipulations.DefaultBorderPolicy)
+			}
+		})
+		b.Run(imgName+"/van_herk", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				GrayDilation(gray, rect, manipulations.DefaultBorderPolicy)
+			}
+		})
+	}
+}
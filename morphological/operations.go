@@ -1,91 +1,78 @@
 package morphological
 
-func Dilation(image BinaryImage, se StructuringElement) BinaryImage {
-	rows := len(image)
-	cols := len(image[0])
-	output := make(BinaryImage, rows)
-	for i := range output {
-		output[i] = make([]int, cols)
-	}
-
-	for x := 0; x < rows; x++ {
-		for y := 0; y < cols; y++ {
-			if image[x][y] == 1 {
-				for i := 0; i < len(se.Data); i++ {
-					for j := 0; j < len(se.Data[i]); j++ {
-						if se.Data[i][j] == 1 {
-							newX := x + i - se.OriginX
-							newY := y + j - se.OriginY
-							if newX >= 0 && newX < rows && newY >= 0 && newY < cols {
-								output[newX][newY] = 1
-							}
-						}
-					}
-				}
-			}
-		}
-	}
+import "imagio/manipulations"
 
-	return output
+// Dilation sets every pixel reached by the SE placed with its origin on a foreground pixel,
+// it works on the bit-packed image, see PackedImage.Dilate. Pixels outside of the image are
+// taken from the border policy, BorderSkip treats them as background.
+func Dilation(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Pack(image).Dilate(se, border).Unpack()
 }
 
-func Erosion(image BinaryImage, se StructuringElement) BinaryImage {
-	rows := len(image)
-	cols := len(image[0])
-	output := make(BinaryImage, rows)
-	for i := range output {
-		output[i] = make([]int, cols)
-	}
-
-	for x := 0; x < rows; x++ {
-		for y := 0; y < cols; y++ {
-			if Fits(image, se, x, y) {
-				output[x][y] = 1
-			}
-		}
-	}
-
-	return output
+// Erosion keeps the pixels where the SE placed with its origin on them fits the foreground, see Fits,
+// it works on the bit-packed image, see PackedImage.Erode. With BorderSkip the SE never fits past the edge.
+func Erosion(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Pack(image).Erode(se, border).Unpack()
 }
 
-func Opening(image BinaryImage, se StructuringElement) BinaryImage {
-	eroded := Erosion(image, se)
-	return Dilation(eroded, se)
+func Opening(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Pack(image).Erode(se, border).Dilate(se, border).Unpack()
 }
 
 // Reference: https://www.geeksforgeeks.org/difference-between-opening-and-closing-in-digital-image-processing/
-func Closing(image BinaryImage, se StructuringElement) BinaryImage {
-	dilated := Dilation(image, se)
-	return Erosion(dilated, se)
+func Closing(image BinaryImage, se StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Pack(image).Dilate(se, border).Erode(se, border).Unpack()
 }
 
-func HitOrMiss(image BinaryImage, se1, se2 StructuringElement) BinaryImage {
-	foreground := Erosion(image, se1)
-	complement := Complement(image)
-	background := Erosion(complement, se2)
-	return Intersection(foreground, background)
+// HitOrMiss keeps the pixels where se1 fits the foreground and se2 fits the background, the image is not modified.
+func HitOrMiss(image BinaryImage, se1, se2 StructuringElement, border manipulations.BorderPolicy) BinaryImage {
+	return Pack(image).HitOrMiss(se1, se2, border).Unpack()
 }
 
+// combineBinary applies op pixel by pixel to two images of the same size and returns the result as a new image.
+func combineBinary(img1, img2 BinaryImage, op func(a, b bool) bool) BinaryImage {
+	output := make(BinaryImage, len(img1))
+	for i := range img1 {
+		output[i] = make([]int, len(img1[i]))
+		for j := range img1[i] {
+			if op(img1[i][j] != 0, img2[i][j] != 0) {
+				output[i][j] = 1
+			}
+		}
+	}
+	return output
+}
+
+// Complement returns a new image with foreground and background swapped.
 func Complement(img BinaryImage) BinaryImage {
+	output := make(BinaryImage, len(img))
 	for i := range img {
+		output[i] = make([]int, len(img[i]))
 		for j := range img[i] {
-			if img[i][j] == 1 {
-				img[i][j] = 0
-			} else {
-				img[i][j] = 1
+			if img[i][j] == 0 {
+				output[i][j] = 1
 			}
 		}
 	}
-	return img
+	return output
 }
 
+// Intersection returns the pixels set in both images without modifying either of them.
 func Intersection(img1, img2 BinaryImage) BinaryImage {
-	for i := range img1 {
-		for j := range img1[i] {
-			if img1[i][j] == 1 && img2[i][j] == 0 {
-				img1[i][j] = 0
-			}
-		}
-	}
-	return img1
+	return combineBinary(img1, img2, func(a, b bool) bool { return a && b })
+}
+
+// Union returns the pixels set in either image without modifying either of them.
+func Union(img1, img2 BinaryImage) BinaryImage {
+	return combineBinary(img1, img2, func(a, b bool) bool { return a || b })
+}
+
+// Difference returns the pixels set in img1 and not in img2 without modifying either image.
+func Difference(img1, img2 BinaryImage) BinaryImage {
+	return combineBinary(img1, img2, func(a, b bool) bool { return a && !b })
+}
+
+// Xor returns the pixels set in exactly one of the images without modifying either of them.
+func Xor(img1, img2 BinaryImage) BinaryImage {
+	return combineBinary(img1, img2, func(a, b bool) bool { return a != b })
 }
@@ -0,0 +1,257 @@
+package morphological
+
+import (
+	"imagio/manipulations"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// PackedImage is a binary image holding one bit per pixel, every row starts at a new 64 bit word
+// and column c of a row is bit c%64 of its word c/64. Bits past the width are always zero.
+type PackedImage struct {
+	width, height, stride int
+	words                 []uint64
+}
+
+// NewPackedImage returns an empty packed image of the given size.
+func NewPackedImage(width, height int) *PackedImage {
+	stride := (width + wordSize - 1) / wordSize
+	return &PackedImage{width: width, height: height, stride: stride, words: make([]uint64, stride*height)}
+}
+
+// Pack converts img into a packed image, every non-zero pixel is foreground.
+func Pack(img BinaryImage) *PackedImage {
+	width := 0
+	if len(img) > 0 {
+		width = len(img[0])
+	}
+
+	packed := NewPackedImage(width, len(img))
+	for row := range img {
+		words := packed.row(row)
+		for col, value := range img[row] {
+			if value != 0 {
+				words[col/wordSize] |= 1 << (col % wordSize)
+			}
+		}
+	}
+	return packed
+}
+
+// Unpack converts the packed image back into a BinaryImage of zeros and ones.
+func (p *PackedImage) Unpack() BinaryImage {
+	img := make(BinaryImage, p.height)
+	for row := range img {
+		img[row] = make([]int, p.width)
+		words := p.row(row)
+		for col := range img[row] {
+			img[row][col] = int(words[col/wordSize] >> (col % wordSize) & 1)
+		}
+	}
+	return img
+}
+
+func (p *PackedImage) Width() int {
+	return p.width
+}
+
+func (p *PackedImage) Height() int {
+	return p.height
+}
+
+// At returns the pixel at the given row and column, 0 outside of the image.
+func (p *PackedImage) At(row, col int) int {
+	if row < 0 || row >= p.height || col < 0 || col >= p.width {
+		return 0
+	}
+	return int(p.row(row)[col/wordSize] >> (col % wordSize) & 1)
+}
+
+// Set sets the pixel at the given row and column to foreground for a non-zero value and to background otherwise.
+func (p *PackedImage) Set(row, col, value int) {
+	if value != 0 {
+		p.row(row)[col/wordSize] |= 1 << (col % wordSize)
+	} else {
+		p.row(row)[col/wordSize] &^= 1 << (col % wordSize)
+	}
+}
+
+// Count returns the number of foreground pixels.
+func (p *PackedImage) Count() int {
+	count := 0
+	for _, word := range p.words {
+		count += bits.OnesCount64(word)
+	}
+	return count
+}
+
+func (p *PackedImage) Clone() *PackedImage {
+	clone := *p
+	clone.words = append([]uint64(nil), p.words...)
+	return &clone
+}
+
+func (p *PackedImage) row(row int) []uint64 {
+	return p.words[row*p.stride : (row+1)*p.stride]
+}
+
+// tailMask keeps the bits of the last word of a row that lie inside of the image.
+func (p *PackedImage) tailMask() uint64 {
+	if p.width%wordSize == 0 {
+		return ^uint64(0)
+	}
+	return 1<<(p.width%wordSize) - 1
+}
+
+// clearTails zeroes the bits past the width after an operation that may have set them.
+func (p *PackedImage) clearTails() {
+	if p.stride == 0 {
+		return
+	}
+	mask := p.tailMask()
+	for row := 0; row < p.height; row++ {
+		p.words[(row+1)*p.stride-1] &= mask
+	}
+}
+
+// combine applies op word by word to two images of the same size and returns the result as a new image.
+func (p *PackedImage) combine(other *PackedImage, op func(a, b uint64) uint64) *PackedImage {
+	result := NewPackedImage(p.width, p.height)
+	for i := range result.words {
+		result.words[i] = op(p.words[i], other.words[i])
+	}
+	return result
+}
+
+// Complement returns a new image with foreground and background swapped.
+func (p *PackedImage) Complement() *PackedImage {
+	result := NewPackedImage(p.width, p.height)
+	for i, word := range p.words {
+		result.words[i] = ^word
+	}
+	result.clearTails()
+	return result
+}
+
+// Union returns the pixels set in either image, both images must have the same size.
+func (p *PackedImage) Union(other *PackedImage) *PackedImage {
+	return p.combine(other, func(a, b uint64) uint64 { return a | b })
+}
+
+// Intersection returns the pixels set in both images, both images must have the same size.
+func (p *PackedImage) Intersection(other *PackedImage) *PackedImage {
+	return p.combine(other, func(a, b uint64) uint64 { return a & b })
+}
+
+// Difference returns the pixels set in p and not in other, both images must have the same size.
+func (p *PackedImage) Difference(other *PackedImage) *PackedImage {
+	return p.combine(other, func(a, b uint64) uint64 { return a &^ b })
+}
+
+// Xor returns the pixels set in exactly one of the images, both images must have the same size.
+func (p *PackedImage) Xor(other *PackedImage) *PackedImage {
+	return p.combine(other, func(a, b uint64) uint64 { return a ^ b })
+}
+
+// shiftedWord returns word w of a row moved by dc columns towards higher columns, negative dc moves it
+// towards lower ones. Bits shifted in from outside of the row are zero.
+func shiftedWord(words []uint64, w, dc int) uint64 {
+	at := func(index int) uint64 {
+		if index < 0 || index >= len(words) {
+			return 0
+		}
+		return words[index]
+	}
+
+	if dc >= 0 {
+		q, s := dc/wordSize, uint(dc%wordSize)
+		if s == 0 {
+			return at(w - q)
+		}
+		return at(w-q)<<s | at(w-q-1)>>(wordSize-s)
+	}
+
+	q, s := -dc/wordSize, uint(-dc%wordSize)
+	if s == 0 {
+		return at(w + q)
+	}
+	return at(w+q)>>s | at(w+q+1)<<(wordSize-s)
+}
+
+// accumulateShifted combines dst with src moved by dr rows and dc columns, so that dst(r, c) is merged
+// with src(r-dr, c-dc). Pixels moved in from outside of the image are background.
+func accumulateShifted(dst, src *PackedImage, dr, dc int, intersect bool) {
+	for row := 0; row < dst.height; row++ {
+		dstWords := dst.row(row)
+		srcRow := row - dr
+		if srcRow < 0 || srcRow >= src.height {
+			if intersect {
+				clear(dstWords)
+			}
+			continue
+		}
+
+		srcWords := src.row(srcRow)
+		for w := range dstWords {
+			if intersect {
+				dstWords[w] &= shiftedWord(srcWords, w, dc)
+			} else {
+				dstWords[w] |= shiftedWord(srcWords, w, dc)
+			}
+		}
+	}
+	dst.clearTails()
+}
+
+func (p *PackedImage) dilateBy(se StructuringElement) *PackedImage {
+	result := NewPackedImage(p.width, p.height)
+	for i := range se.Data {
+		for j := range se.Data[i] {
+			if se.Data[i][j] == 1 {
+				accumulateShifted(result, p, i-se.OriginX, j-se.OriginY, false)
+			}
+		}
+	}
+	return result
+}
+
+func (p *PackedImage) erodeBy(se StructuringElement) *PackedImage {
+	result := NewPackedImage(p.width, p.height).Complement()
+	for i := range se.Data {
+		for j := range se.Data[i] {
+			if se.Data[i][j] == 1 {
+				accumulateShifted(result, p, se.OriginX-i, se.OriginY-j, true)
+			}
+		}
+	}
+	return result
+}
+
+// Dilate returns the dilation by se with the same result as Dilation, a rectangle is applied
+// as a horizontal and a vertical line which needs width+height instead of width*height passes.
+func (p *PackedImage) Dilate(se StructuringElement, border manipulations.BorderPolicy) *PackedImage {
+	return p.withBorder(border, func(img *PackedImage) *PackedImage {
+		if horizontal, vertical, ok := se.Decompose(); ok {
+			return img.dilateBy(horizontal).dilateBy(vertical)
+		}
+		return img.dilateBy(se)
+	}, se)
+}
+
+// Erode returns the erosion by se with the same result as Erosion, with BorderSkip pixels outside of the image are background.
+func (p *PackedImage) Erode(se StructuringElement, border manipulations.BorderPolicy) *PackedImage {
+	return p.withBorder(border, func(img *PackedImage) *PackedImage {
+		if horizontal, vertical, ok := se.Decompose(); ok {
+			return img.erodeBy(horizontal).erodeBy(vertical)
+		}
+		return img.erodeBy(se)
+	}, se)
+}
+
+// HitOrMiss returns the pixels where se1 fits the foreground and se2 fits the background with the same result as HitOrMiss.
+func (p *PackedImage) HitOrMiss(se1, se2 StructuringElement, border manipulations.BorderPolicy) *PackedImage {
+	return p.withBorder(border, func(img *PackedImage) *PackedImage {
+		return img.Erode(se1, manipulations.DefaultBorderPolicy).Intersection(img.Complement().Erode(se2, manipulations.DefaultBorderPolicy))
+	}, se1, se2)
+}
@@ -0,0 +1,298 @@
+package morphological
+
+import (
+	"imagio/manipulations"
+	"math/rand"
+	"testing"
+)
+
+// naiveDilation and naiveErosion are the pixel by pixel loops the packed operations have to match.
+func naiveDilation(image BinaryImage, se StructuringElement) BinaryImage {
+	rows := len(image)
+	cols := len(image[0])
+	output := make(BinaryImage, rows)
+	for i := range output {
+		output[i] = make([]int, cols)
+	}
+
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			if image[x][y] == 1 {
+				for i := 0; i < len(se.Data); i++ {
+					for j := 0; j < len(se.Data[i]); j++ {
+						if se.Data[i][j] == 1 {
+							newX := x + i - se.OriginX
+							newY := y + j - se.OriginY
+							if newX >= 0 && newX < rows && newY >= 0 && newY < cols {
+								output[newX][newY] = 1
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return output
+}
+
+func naiveErosion(image BinaryImage, se StructuringElement) BinaryImage {
+	rows := len(image)
+	cols := len(image[0])
+	output := make(BinaryImage, rows)
+	for i := range output {
+		output[i] = make([]int, cols)
+	}
+
+	for x := 0; x < rows; x++ {
+		for y := 0; y < cols; y++ {
+			if Fits(image, se, x, y) {
+				output[x][y] = 1
+			}
+		}
+	}
+
+	return output
+}
+
+func randomBinaryImage(random *rand.Rand, width, height int, density float64) BinaryImage {
+	img := newBinaryImage(width, height)
+	for y := range img {
+		for x := range img[y] {
+			if random.Float64() < density {
+				img[y][x] = 1
+			}
+		}
+	}
+	return img
+}
+
+func sameBinaryImage(a, b BinaryImage) bool {
+	for y := range a {
+		for x := range a[y] {
+			if a[y][x] != b[y][x] {
+				return false
+			}
+		}
+	}
+	return len(a) == len(b)
+}
+
+func testStructureElements(t testing.TB) map[string]StructuringElement {
+	elements := map[string]StructuringElement{
+		"origin outside": {Data: [][]int{{1, 1}, {0, 1}}, OriginX: 3, OriginY: -2},
+		"wide line":      {Data: [][]int{make([]int, 70)}, OriginX: 0, OriginY: 5},
+	}
+	for j := range elements["wide line"].Data[0] {
+		elements["wide line"].Data[0][j] = 1
+	}
+
+	for _, name := range []string{"iii", "iv", "xi-l", "xi-c", "disk:3", "diamond:2", "rect:5x3", "rect:1x4", "line:9:30"} {
+		se, err := GetStructureElement(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		elements[name] = se
+	}
+
+	corner, _ := NewRectangle(4, 3)
+	corner.OriginX, corner.OriginY = 0, 3
+	elements["rect with corner origin"] = corner
+
+	return elements
+}
+
+func TestPackedOperationsMatchNaive(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+
+	// widths around the word size exercise the shifts across words
+	for _, width := range []int{1, 63, 64, 65, 130} {
+		img := randomBinaryImage(random, width, 17, 0.6)
+
+		for name, se := range testStructureElements(t) {
+			if !sameBinaryImage(Dilation(img, se, manipulations.DefaultBorderPolicy), naiveDilation(img, se)) {
+				t.Errorf("width %d, %s: Dilation() differs from the pixel loop", width, name)
+			}
+			if !sameBinaryImage(Erosion(img, se, manipulations.DefaultBorderPolicy), naiveErosion(img, se)) {
+				t.Errorf("width %d, %s: Erosion() differs from the pixel loop", width, name)
+			}
+		}
+	}
+}
+
+// paddedReference runs op on the image padded with the border policy by margin pixels and crops the result back,
+// op treating the pixels outside of the padded image as background.
+func paddedReference(img BinaryImage, margin int, border manipulations.BorderPolicy, op func(BinaryImage) BinaryImage) BinaryImage {
+	padded := newBinaryImage(len(img[0])+2*margin, len(img)+2*margin)
+	for y := range padded {
+		for x := range padded[y] {
+			padded[y][x] = binaryPixelAt(img, x-margin, y-margin, border)
+		}
+	}
+
+	result := op(padded)
+	output := newBinaryImage(len(img[0]), len(img))
+	for y := range output {
+		copy(output[y], result[y+margin][margin:])
+	}
+	return output
+}
+
+func TestPackedOperationsBorderPolicies(t *testing.T) {
+	random := rand.New(rand.NewSource(13))
+	pair, err := ParseHitOrMissTemplate("x,1,x;0,1,0;0,0,0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, policy := range []string{"clamp", "reflect", "wrap", "constant:0", "constant:255"} {
+		border, _ := manipulations.ParseBorderPolicy(policy)
+
+		for _, width := range []int{1, 65} {
+			img := randomBinaryImage(random, width, 11, 0.6)
+
+			for name, se := range testStructureElements(t) {
+				want := paddedReference(img, 72, border, func(padded BinaryImage) BinaryImage { return naiveDilation(padded, se) })
+				if !sameBinaryImage(Dilation(img, se, border), want) {
+					t.Errorf("%s, width %d, %s: Dilation() differs from the padded pixel loop", policy, width, name)
+				}
+				want = paddedReference(img, 72, border, func(padded BinaryImage) BinaryImage { return naiveErosion(padded, se) })
+				if !sameBinaryImage(Erosion(img, se, border), want) {
+					t.Errorf("%s, width %d, %s: Erosion() differs from the padded pixel loop", policy, width, name)
+				}
+			}
+
+			want := paddedReference(img, 1, border, func(padded BinaryImage) BinaryImage {
+				return Intersection(naiveErosion(padded, pair.Foreground), naiveErosion(Complement(padded), pair.Background))
+			})
+			if !sameBinaryImage(HitOrMiss(img, pair.Foreground, pair.Background, border), want) {
+				t.Errorf("%s, width %d: HitOrMiss() differs from the padded pixel loop", policy, width)
+			}
+		}
+	}
+}
+
+func TestErosionBorderPolicyKeepsEdges(t *testing.T) {
+	img := newBinaryImage(5, 4)
+	for y := range img {
+		for x := range img[y] {
+			img[y][x] = 1
+		}
+	}
+	se, _ := GetStructureElement("iv")
+
+	if eroded := Erosion(img, se, manipulations.DefaultBorderPolicy); foregroundCount(eroded) != 3*2 {
+		t.Errorf("Erosion() with skip kept %d pixels, want only the 3x2 interior", foregroundCount(eroded))
+	}
+	clamp := manipulations.BorderPolicy{Mode: manipulations.BorderClamp}
+	if eroded := Erosion(img, se, clamp); !sameBinaryImage(eroded, img) {
+		t.Errorf("Erosion() with clamp = %v, want the full image", eroded)
+	}
+}
+
+func TestPackRoundTrip(t *testing.T) {
+	img := randomBinaryImage(rand.New(rand.NewSource(5)), 77, 9, 0.5)
+	packed := Pack(img)
+
+	if !sameBinaryImage(packed.Unpack(), img) {
+		t.Fatal("Unpack(Pack()) differs from the image")
+	}
+	if packed.Count() != foregroundCount(img) {
+		t.Errorf("Count() = %d, want %d", packed.Count(), foregroundCount(img))
+	}
+	if packed.Complement().Count() != 77*9-foregroundCount(img) {
+		t.Error("Complement() set bits past the width")
+	}
+
+	clone := packed.Clone()
+	clone.Set(0, 70, 1-packed.At(0, 70))
+	if clone.At(0, 70) == packed.At(0, 70) {
+		t.Error("Clone() shares its pixels with the original")
+	}
+	if packed.At(-1, 0) != 0 || packed.At(0, 77) != 0 {
+		t.Error("At() outside of the image is not background")
+	}
+}
+
+func TestSetOperationsDoNotModifyInputs(t *testing.T) {
+	a := binaryFromRows("##..", "#.#.")
+	b := binaryFromRows("#.#.", "..##")
+
+	cases := map[string]struct {
+		got    BinaryImage
+		packed *PackedImage
+		want   BinaryImage
+	}{
+		"union":        {Union(a, b), Pack(a).Union(Pack(b)), binaryFromRows("###.", "#.##")},
+		"intersection": {Intersection(a, b), Pack(a).Intersection(Pack(b)), binaryFromRows("#...", "..#.")},
+		"difference":   {Difference(a, b), Pack(a).Difference(Pack(b)), binaryFromRows(".#..", "#...")},
+		"xor":          {Xor(a, b), Pack(a).Xor(Pack(b)), binaryFromRows(".##.", "#..#")},
+		"complement":   {Complement(a), Pack(a).Complement(), binaryFromRows("..##", ".#.#")},
+	}
+	for name, c := range cases {
+		if !sameBinaryImage(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", name, c.got, c.want)
+		}
+		if !sameBinaryImage(c.packed.Unpack(), c.want) {
+			t.Errorf("packed %s = %v, want %v", name, c.packed.Unpack(), c.want)
+		}
+	}
+
+	if !sameBinaryImage(a, binaryFromRows("##..", "#.#.")) || !sameBinaryImage(b, binaryFromRows("#.#.", "..##")) {
+		t.Error("set operations modified their inputs")
+	}
+}
+
+func TestDecompose(t *testing.T) {
+	rect, _ := NewRectangle(5, 3)
+	horizontal, vertical, ok := rect.Decompose()
+	if !ok {
+		t.Fatal("a rectangle was not decomposed")
+	}
+	if len(horizontal.Data) != 1 || len(horizontal.Data[0]) != 5 || horizontal.OriginY != 2 {
+		t.Errorf("horizontal line = %+v", horizontal)
+	}
+	if len(vertical.Data) != 3 || len(vertical.Data[0]) != 1 || vertical.OriginX != 1 {
+		t.Errorf("vertical line = %+v", vertical)
+	}
+
+	for _, name := range []string{"iv", "disk:2"} {
+		se, _ := GetStructureElement(name)
+		if _, _, ok := se.Decompose(); ok {
+			t.Errorf("%s is not a rectangle but was decomposed", name)
+		}
+	}
+}
+
+func TestGrayRectangleFilterMatchesGeneralLoop(t *testing.T) {
+	random := rand.New(rand.NewSource(11))
+	img := newGrayImage(13, 21)
+	for y := range img {
+		for x := range img[y] {
+			img[y][x] = random.Intn(256)
+		}
+	}
+
+	for _, se := range []StructuringElement{
+		{Data: [][]int{{1, 1, 1, 1}, {1, 1, 1, 1}}, OriginX: 1, OriginY: 0},
+		{Data: [][]int{{1}, {1}, {1}, {1}, {1}}, OriginX: 3, OriginY: 0},
+		{Data: [][]int{{1, 1, 1, 1, 1, 1, 1}}, OriginX: 0, OriginY: 2},
+	} {
+		// a trailing row of zeros keeps the same offsets but prevents the decomposition
+		general := se
+		general.Data = append(append([][]int(nil), se.Data...), make([]int, len(se.Data[0])))
+
+		for name, pair := range map[string][2]GrayImage{
+			"dilation": {GrayDilation(img, se, manipulations.DefaultBorderPolicy), GrayDilation(img, general, manipulations.DefaultBorderPolicy)},
+			"erosion":  {GrayErosion(img, se, manipulations.DefaultBorderPolicy), GrayErosion(img, general, manipulations.DefaultBorderPolicy)},
+		} {
+			for y := range img {
+				for x := range img[y] {
+					if pair[0][y][x] != pair[1][y][x] {
+						t.Fatalf("%s of %v at (%d, %d) = %d, want %d", name, se.Data, x, y, pair[0][y][x], pair[1][y][x])
+					}
+				}
+			}
+		}
+	}
+}
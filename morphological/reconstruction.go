@@ -0,0 +1,321 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"imagio/manipulations"
+)
+
+// The functions below work on GrayImage, a BinaryImage converted with GrayImage(img) holds only 0 and 1
+// and stays binary under every one of them except HMinima. Marker and mask must have the same size.
+//
+// Reference: L. Vincent - Morphological grayscale reconstruction in image analysis (1993)
+
+// ReconstructionMethod selects whether the marker grows under the mask or shrinks above it.
+type ReconstructionMethod string
+
+const (
+	ReconstructionByDilation ReconstructionMethod = "dilation"
+	ReconstructionByErosion  ReconstructionMethod = "erosion"
+)
+
+// ParseReconstructionMethod parses "dilation" or "erosion", an empty string selects dilation.
+func ParseReconstructionMethod(value string) (ReconstructionMethod, error) {
+	switch value {
+	case "", string(ReconstructionByDilation):
+		return ReconstructionByDilation, nil
+	case string(ReconstructionByErosion):
+		return ReconstructionByErosion, nil
+	default:
+		return "", fmt.Errorf("unknown reconstruction method %q, expected dilation or erosion", value)
+	}
+}
+
+// nextNeighbours are the neighbours visited after a pixel in row-major order.
+func (c Connectivity) nextNeighbours() []Point {
+	previous := c.previousNeighbours()
+	next := make([]Point, len(previous))
+	for i, d := range previous {
+		next[i] = Point{X: -d.X, Y: -d.Y}
+	}
+	return next
+}
+
+// neighbours are all the neighbours of a pixel.
+func (c Connectivity) neighbours() []Point {
+	return append(c.previousNeighbours(), c.nextNeighbours()...)
+}
+
+// element returns the elementary 3x3 structuring element of the connectivity.
+func (c Connectivity) element() StructuringElement {
+	if c == Connectivity4 {
+		return StructuringElement{Data: [][]int{{0, 1, 0}, {1, 1, 1}, {0, 1, 0}}, OriginX: 1, OriginY: 1}
+	}
+	return StructuringElement{Data: [][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}}, OriginX: 1, OriginY: 1}
+}
+
+func pointwise(img1, img2 GrayImage, combine func(a, b int) int) GrayImage {
+	output := newGrayImage(len(img1), len(img1[0]))
+	for y := range output {
+		for x := range output[y] {
+			output[y][x] = combine(img1[y][x], img2[y][x])
+		}
+	}
+	return output
+}
+
+func minimum(a, b int) int { return min(a, b) }
+func maximum(a, b int) int { return max(a, b) }
+
+// invert mirrors the intensities, which turns reconstruction by erosion into reconstruction by dilation.
+func invert(img GrayImage) GrayImage {
+	output := newGrayImage(len(img), len(img[0]))
+	for y := range output {
+		for x := range output[y] {
+			output[y][x] = 255 - img[y][x]
+		}
+	}
+	return output
+}
+
+// GeodesicDilation dilates the marker steps times with the elementary element of the connectivity,
+// never letting it grow above the mask.
+func GeodesicDilation(marker, mask GrayImage, connectivity Connectivity, steps int) GrayImage {
+	se := connectivity.element()
+	result := pointwise(marker, mask, minimum)
+	for i := 0; i < steps; i++ {
+		result = pointwise(GrayDilation(result, se, manipulations.DefaultBorderPolicy), mask, minimum)
+	}
+	return result
+}
+
+// GeodesicErosion erodes the marker steps times with the elementary element of the connectivity,
+// never letting it shrink below the mask.
+func GeodesicErosion(marker, mask GrayImage, connectivity Connectivity, steps int) GrayImage {
+	se := connectivity.element()
+	result := pointwise(marker, mask, maximum)
+	for i := 0; i < steps; i++ {
+		result = pointwise(GrayErosion(result, se, manipulations.DefaultBorderPolicy), mask, maximum)
+	}
+	return result
+}
+
+// ReconstructByDilation repeats the geodesic dilation of the marker under the mask until stability,
+// using the hybrid raster scan and queue algorithm so that every pixel is visited only a few times.
+func ReconstructByDilation(marker, mask GrayImage, connectivity Connectivity) GrayImage {
+	result := pointwise(marker, mask, minimum)
+	rows := len(result)
+	cols := len(result[0])
+
+	inside := func(x, y int) bool {
+		return x >= 0 && x < cols && y >= 0 && y < rows
+	}
+
+	previous := connectivity.previousNeighbours()
+	next := connectivity.nextNeighbours()
+
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			value := result[y][x]
+			for _, d := range previous {
+				if inside(x+d.X, y+d.Y) {
+					value = max(value, result[y+d.Y][x+d.X])
+				}
+			}
+			result[y][x] = min(value, mask[y][x])
+		}
+	}
+
+	var queue []Point
+	for y := rows - 1; y >= 0; y-- {
+		for x := cols - 1; x >= 0; x-- {
+			value := result[y][x]
+			for _, d := range next {
+				if inside(x+d.X, y+d.Y) {
+					value = max(value, result[y+d.Y][x+d.X])
+				}
+			}
+			result[y][x] = min(value, mask[y][x])
+
+			// the pixel may still raise a neighbour already passed by the backward scan
+			for _, d := range next {
+				nx, ny := x+d.X, y+d.Y
+				if inside(nx, ny) && result[ny][nx] < result[y][x] && result[ny][nx] < mask[ny][nx] {
+					queue = append(queue, Point{X: x, Y: y})
+					break
+				}
+			}
+		}
+	}
+
+	neighbours := append(previous, next...)
+	for head := 0; head < len(queue); head++ {
+		p := queue[head]
+		for _, d := range neighbours {
+			nx, ny := p.X+d.X, p.Y+d.Y
+			if inside(nx, ny) && result[ny][nx] < result[p.Y][p.X] && result[ny][nx] != mask[ny][nx] {
+				result[ny][nx] = min(result[p.Y][p.X], mask[ny][nx])
+				queue = append(queue, Point{X: nx, Y: ny})
+			}
+		}
+	}
+
+	return result
+}
+
+// ReconstructByErosion repeats the geodesic erosion of the marker above the mask until stability.
+func ReconstructByErosion(marker, mask GrayImage, connectivity Connectivity) GrayImage {
+	return invert(ReconstructByDilation(invert(marker), invert(mask), connectivity))
+}
+
+// Reconstruct reconstructs the mask from the marker with the given method, a positive number of steps
+// stops after that many geodesic dilations or erosions instead of running until stability.
+func Reconstruct(marker, mask GrayImage, method ReconstructionMethod, connectivity Connectivity, steps int) GrayImage {
+	switch {
+	case method == ReconstructionByErosion && steps > 0:
+		return GeodesicErosion(marker, mask, connectivity, steps)
+	case method == ReconstructionByErosion:
+		return ReconstructByErosion(marker, mask, connectivity)
+	case steps > 0:
+		return GeodesicDilation(marker, mask, connectivity, steps)
+	default:
+		return ReconstructByDilation(marker, mask, connectivity)
+	}
+}
+
+// seedsOrFrame returns the non-zero pixels of seeds, or the pixels on the image frame when seeds is nil.
+func seedsOrFrame(seeds GrayImage, rows, cols int) [][]bool {
+	isSeed := make([][]bool, rows)
+	for y := range isSeed {
+		isSeed[y] = make([]bool, cols)
+		for x := range isSeed[y] {
+			if seeds == nil {
+				isSeed[y][x] = y == 0 || x == 0 || y == rows-1 || x == cols-1
+			} else {
+				isSeed[y][x] = seeds[y][x] > 0
+			}
+		}
+	}
+	return isSeed
+}
+
+// FillHoles fills the dark regions that cannot be reached from the seeds without crossing brighter pixels.
+// With nil seeds the image frame is used, which fills every hole not touching the border.
+func FillHoles(img, seeds GrayImage, connectivity Connectivity) GrayImage {
+	rows := len(img)
+	cols := len(img[0])
+	isSeed := seedsOrFrame(seeds, rows, cols)
+
+	peak := 0
+	for _, row := range img {
+		for _, value := range row {
+			peak = max(peak, value)
+		}
+	}
+
+	marker := newGrayImage(rows, cols)
+	for y := range marker {
+		for x := range marker[y] {
+			if isSeed[y][x] {
+				marker[y][x] = img[y][x]
+			} else {
+				marker[y][x] = peak
+			}
+		}
+	}
+
+	return ReconstructByErosion(marker, img, connectivity)
+}
+
+// ClearBorder removes the bright objects connected to the seeds.
+// With nil seeds the image frame is used, which clears every object touching the border.
+func ClearBorder(img, seeds GrayImage, connectivity Connectivity) GrayImage {
+	rows := len(img)
+	cols := len(img[0])
+	isSeed := seedsOrFrame(seeds, rows, cols)
+
+	marker := newGrayImage(rows, cols)
+	for y := range marker {
+		for x := range marker[y] {
+			if isSeed[y][x] {
+				marker[y][x] = img[y][x]
+			}
+		}
+	}
+
+	return subtractGray(img, ReconstructByDilation(marker, img, connectivity))
+}
+
+// HMaxima suppresses every regional maximum whose height above its surroundings is at most h.
+func HMaxima(img GrayImage, h int, connectivity Connectivity) GrayImage {
+	marker := newGrayImage(len(img), len(img[0]))
+	for y := range marker {
+		for x := range marker[y] {
+			marker[y][x] = clampIntensity(img[y][x] - h)
+		}
+	}
+	return ReconstructByDilation(marker, img, connectivity)
+}
+
+// HMinima suppresses every regional minimum whose depth below its surroundings is at most h.
+func HMinima(img GrayImage, h int, connectivity Connectivity) GrayImage {
+	marker := newGrayImage(len(img), len(img[0]))
+	for y := range marker {
+		for x := range marker[y] {
+			marker[y][x] = clampIntensity(img[y][x] + h)
+		}
+	}
+	return ReconstructByErosion(marker, img, connectivity)
+}
+
+// RegionalMaxima marks the plateaus surrounded only by strictly darker pixels.
+func RegionalMaxima(img GrayImage, connectivity Connectivity) BinaryImage {
+	marker := newGrayImage(len(img), len(img[0]))
+	for y := range marker {
+		for x := range marker[y] {
+			marker[y][x] = img[y][x] - 1
+		}
+	}
+
+	reconstructed := ReconstructByDilation(marker, img, connectivity)
+
+	maxima := make(BinaryImage, len(img))
+	for y := range maxima {
+		maxima[y] = make([]int, len(img[y]))
+		for x := range maxima[y] {
+			if img[y][x] > reconstructed[y][x] {
+				maxima[y][x] = 1
+			}
+		}
+	}
+	return maxima
+}
+
+// ApplyInMode runs fn on the binarized image or, in gray mode, on every RGB channel separately.
+// The marker is converted the same way and passed as nil when it is nil itself.
+func ApplyInMode(img, marker image.Image, mode Mode, fn func(img, marker GrayImage) GrayImage) (*image.RGBA, error) {
+	if marker != nil && marker.Bounds().Size() != img.Bounds().Size() {
+		return nil, fmt.Errorf("marker image size %v does not match image size %v", marker.Bounds().Size(), img.Bounds().Size())
+	}
+
+	switch mode {
+	case ModeBinary:
+		var binaryMarker GrayImage
+		if marker != nil {
+			binaryMarker = GrayImage(ConvertIntoBinaryImage(marker))
+		}
+		return ConvertIntoImage(BinaryImage(fn(GrayImage(ConvertIntoBinaryImage(img)), binaryMarker))), nil
+	case ModeGray:
+		channels := SplitChannels(img)
+		var markerChannels [3]GrayImage
+		if marker != nil {
+			markerChannels = SplitChannels(marker)
+		}
+		for c := range channels {
+			channels[c] = fn(channels[c], markerChannels[c])
+		}
+		return MergeChannels(channels), nil
+	default:
+		return nil, fmt.Errorf("unknown morphology mode %q", mode)
+	}
+}
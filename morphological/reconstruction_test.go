@@ -0,0 +1,110 @@
+package morphological
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReconstructByDilationKeepsMarkedComponents(t *testing.T) {
+	mask := binaryFromRows(
+		"##...#",
+		"##...#",
+		"....##",
+		"#.....",
+	)
+	marker := binaryFromRows(
+		"......",
+		"......",
+		".....#",
+		"......",
+	)
+
+	want := binaryFromRows(
+		".....#",
+		".....#",
+		"....##",
+		"......",
+	)
+	if got := BinaryImage(ReconstructByDilation(GrayImage(marker), GrayImage(mask), Connectivity8)); !reflect.DeepEqual(got, want) {
+		t.Errorf("ReconstructByDilation() = %v, want %v", got, want)
+	}
+}
+
+func TestReconstructByDilationMatchesIteratedGeodesicDilation(t *testing.T) {
+	mask := GrayImage{
+		{10, 40, 40, 10, 90},
+		{10, 60, 20, 10, 90},
+		{30, 80, 70, 50, 10},
+		{30, 30, 10, 50, 50},
+	}
+	marker := newGrayImage(4, 5)
+	marker[3][4] = 200
+
+	for _, connectivity := range []Connectivity{Connectivity4, Connectivity8} {
+		want := GeodesicDilation(marker, mask, connectivity, 20)
+		if got := ReconstructByDilation(marker, mask, connectivity); !reflect.DeepEqual(got, want) {
+			t.Errorf("%d-connectivity: ReconstructByDilation() = %v, want %v", connectivity, got, want)
+		}
+	}
+}
+
+func TestFillHolesAndClearBorder(t *testing.T) {
+	img := binaryFromRows(
+		"#.......",
+		"..####..",
+		"..#..#..",
+		"..####..",
+		"........",
+	)
+
+	filled := binaryFromRows(
+		"#.......",
+		"..####..",
+		"..####..",
+		"..####..",
+		"........",
+	)
+	if got := BinaryImage(FillHoles(GrayImage(img), nil, Connectivity4)); !reflect.DeepEqual(got, filled) {
+		t.Errorf("FillHoles() = %v, want %v", got, filled)
+	}
+
+	cleared := binaryFromRows(
+		"........",
+		"..####..",
+		"..#..#..",
+		"..####..",
+		"........",
+	)
+	if got := BinaryImage(ClearBorder(GrayImage(img), nil, Connectivity8)); !reflect.DeepEqual(got, cleared) {
+		t.Errorf("ClearBorder() = %v, want %v", got, cleared)
+	}
+}
+
+func TestHExtremaAndRegionalMaxima(t *testing.T) {
+	img := GrayImage{
+		{10, 10, 10, 10, 10, 10, 10},
+		{10, 15, 10, 10, 50, 50, 10},
+		{10, 10, 10, 10, 50, 10, 10},
+	}
+
+	maxima := BinaryImage{
+		{0, 0, 0, 0, 0, 0, 0},
+		{0, 1, 0, 0, 1, 1, 0},
+		{0, 0, 0, 0, 1, 0, 0},
+	}
+	if got := RegionalMaxima(img, Connectivity8); !reflect.DeepEqual(got, maxima) {
+		t.Errorf("RegionalMaxima() = %v, want %v", got, maxima)
+	}
+
+	// the low peak vanishes and the high one is lowered by h
+	suppressed := HMaxima(img, 10, Connectivity8)
+	if suppressed[1][1] != 10 || suppressed[1][4] != 40 || suppressed[0][0] != 10 {
+		t.Errorf("HMaxima() = %v", suppressed)
+	}
+
+	pits := invert(img)
+	filled := HMinima(pits, 10, Connectivity8)
+	if filled[1][1] != 245 || filled[1][4] != 215 {
+		t.Errorf("HMinima() = %v", filled)
+	}
+}
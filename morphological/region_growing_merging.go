@@ -1,22 +1,27 @@
 package morphological
 
 import (
+	"container/heap"
 	"fmt"
 	"image"
 	"image/color"
 	"math"
 	"math/rand"
 	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 type Point struct {
-	X, Y int
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 type Region struct {
 	Pixels []Point
-	Label  int // ID of the region
+	Label  int       // ID of the region
+	Mean   []float64 // mean pixel value, one channel for gray images and three for RGB
 }
 
 type DistanceCriterion int
@@ -27,11 +32,12 @@ const (
 	Chebyshev
 )
 
+var seedPointPattern = regexp.MustCompile(`\[\s*(\d+)\s*,\s*(\d+)\s*\]`)
+
 func ParseSeedPoints(seedInput string) ([]Point, error) {
 	var points []Point
 
-	re := regexp.MustCompile(`\[(\d+),(\d+)\]`)
-	matches := re.FindAllStringSubmatch(seedInput, -1)
+	matches := seedPointPattern.FindAllStringSubmatch(seedInput, -1)
 
 	if len(matches) == 0 {
 		return nil, fmt.Errorf("no seed points found or invalid format %s", seedInput)
@@ -60,23 +66,6 @@ func randomColor() color.Color {
 	}
 }
 
-func getNeighbors(p Point, rows, cols int) []Point {
-	neighbors := []Point{}
-	directions := []Point{
-		{-1, 0}, {1, 0}, {0, -1}, {0, 1},
-		{-1, -1}, {-1, 1}, {1, -1}, {1, 1},
-	}
-	for _, d := range directions {
-		neighbor := Point{X: p.X + d.X, Y: p.Y + d.Y}
-
-		if neighbor.X >= 0 && neighbor.X < rows && neighbor.Y >= 0 && neighbor.Y < cols {
-			neighbors = append(neighbors, neighbor)
-		}
-	}
-
-	return neighbors
-}
-
 func getPixelValue(c color.Color) []float64 {
 	r, g, b, _ := c.RGBA()
 
@@ -125,15 +114,185 @@ func calculateDistance(criterion DistanceCriterion, p1, p2 []float64) float64 {
 	}
 }
 
+// GrowthReference selects what a candidate pixel is compared to while a region grows.
+type GrowthReference string
+
+const (
+	// ReferenceSeed compares candidates to the value of the seed pixel.
+	ReferenceSeed GrowthReference = "seed"
+	// ReferenceMean compares candidates to the running mean of the region.
+	ReferenceMean GrowthReference = "mean"
+	// ReferenceStatistics compares candidates to the running mean of the region and widens the threshold
+	// by the standard deviation of the region times RegionGrowingOptions.Deviations.
+	ReferenceStatistics GrowthReference = "stats"
+)
+
+// ParseGrowthReference parses seed, mean or stats, an empty string selects seed.
+func ParseGrowthReference(value string) (GrowthReference, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", string(ReferenceSeed):
+		return ReferenceSeed, nil
+	case string(ReferenceMean):
+		return ReferenceMean, nil
+	case string(ReferenceStatistics), "statistics":
+		return ReferenceStatistics, nil
+	default:
+		return "", fmt.Errorf("unknown region growing reference %q, expected seed, mean or stats", value)
+	}
+}
+
+// RegionGrowingOptions configures GrowRegions.
+type RegionGrowingOptions struct {
+	Criterion    DistanceCriterion
+	Threshold    float64
+	Reference    GrowthReference
+	Deviations   float64
+	Connectivity Connectivity
+}
+
+// regionStatistics accumulates the mean, standard deviation and range of the pixel values of a region.
+type regionStatistics struct {
+	count                             int
+	sum, sumSquares, minimum, maximum []float64
+}
+
+func newRegionStatistics(channels int) *regionStatistics {
+	s := &regionStatistics{
+		sum:        make([]float64, channels),
+		sumSquares: make([]float64, channels),
+		minimum:    make([]float64, channels),
+		maximum:    make([]float64, channels),
+	}
+	for c := range s.minimum {
+		s.minimum[c], s.maximum[c] = math.Inf(1), math.Inf(-1)
+	}
+	return s
+}
+
+func (s *regionStatistics) add(value []float64) {
+	s.count++
+	for c, v := range value {
+		s.sum[c] += v
+		s.sumSquares[c] += v * v
+		s.minimum[c] = math.Min(s.minimum[c], v)
+		s.maximum[c] = math.Max(s.maximum[c], v)
+	}
+}
+
+// join returns the statistics of the union of both regions.
+func (s *regionStatistics) join(other *regionStatistics) *regionStatistics {
+	joined := newRegionStatistics(len(s.sum))
+	joined.count = s.count + other.count
+	for c := range joined.sum {
+		joined.sum[c] = s.sum[c] + other.sum[c]
+		joined.sumSquares[c] = s.sumSquares[c] + other.sumSquares[c]
+		joined.minimum[c] = math.Min(s.minimum[c], other.minimum[c])
+		joined.maximum[c] = math.Max(s.maximum[c], other.maximum[c])
+	}
+	return joined
+}
+
+func (s *regionStatistics) mean() []float64 {
+	mean := make([]float64, len(s.sum))
+	for c := range mean {
+		mean[c] = s.sum[c] / float64(s.count)
+	}
+	return mean
+}
+
+func (s *regionStatistics) deviation() []float64 {
+	deviation := make([]float64, len(s.sum))
+	for c := range deviation {
+		m := s.sum[c] / float64(s.count)
+		deviation[c] = math.Sqrt(math.Max(0, s.sumSquares[c]/float64(s.count)-m*m))
+	}
+	return deviation
+}
+
+// pixelValues returns the values of every pixel as [row][col], with one channel when the whole image is gray
+// so that every pixel has the same number of channels.
+func pixelValues(img image.Image) [][][]float64 {
+	bounds := img.Bounds()
+	values := make([][][]float64, bounds.Dy())
+	gray := true
+	for y := range values {
+		values[y] = make([][]float64, bounds.Dx())
+		for x := range values[y] {
+			values[y][x] = getPixelValue(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			gray = gray && len(values[y][x]) == 1
+		}
+	}
+
+	if !gray {
+		for y := range values {
+			for x, v := range values[y] {
+				if len(v) == 1 {
+					values[y][x] = []float64{v[0], v[0], v[0]}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// RegionGrowing grows a region from every seed with 8-connectivity, comparing the neighbours to the seed value.
+// Seeds outside of the image are skipped.
 func RegionGrowing(img image.Image, seeds []Point, criterion DistanceCriterion, threshold float64) ([][]int, *image.RGBA) {
+	bounds := img.Bounds()
+	inside := make([]Point, 0, len(seeds))
+	for _, seed := range seeds {
+		if seed.X >= 0 && seed.X < bounds.Dx() && seed.Y >= 0 && seed.Y < bounds.Dy() {
+			inside = append(inside, seed)
+		}
+	}
+
+	segmented, outputImage, _ := GrowRegions(img, inside, RegionGrowingOptions{
+		Criterion:    criterion,
+		Threshold:    max(threshold, 0),
+		Reference:    ReferenceSeed,
+		Connectivity: Connectivity8,
+	})
+	return segmented, outputImage
+}
+
+// GrowRegions grows a region from every seed that is not already part of an earlier region, adding the
+// neighbours whose distance to the reference value of the region is at most the threshold. It returns the
+// region labels as [row][col], -1 for pixels outside of every region, and the regions drawn in random colors.
+//
+// Reference: R. Adams, L. Bischof - Seeded region growing (1994)
+func GrowRegions(img image.Image, seeds []Point, opts RegionGrowingOptions) ([][]int, *image.RGBA, error) {
 	bounds := img.Bounds()
 	width, height := bounds.Dx(), bounds.Dy()
 
+	if opts.Threshold < 0 {
+		return nil, nil, fmt.Errorf("threshold must not be negative, got %v", opts.Threshold)
+	}
+	if opts.Deviations < 0 {
+		return nil, nil, fmt.Errorf("deviations must not be negative, got %v", opts.Deviations)
+	}
+	if opts.Connectivity != Connectivity4 && opts.Connectivity != Connectivity8 {
+		return nil, nil, fmt.Errorf("connectivity must be 4 or 8, got %d", opts.Connectivity)
+	}
+	if _, err := ParseGrowthReference(string(opts.Reference)); err != nil {
+		return nil, nil, err
+	}
+	for _, seed := range seeds {
+		if seed.X < 0 || seed.X >= width || seed.Y < 0 || seed.Y >= height {
+			return nil, nil, fmt.Errorf("seed [%d,%d] is outside of the %dx%d image", seed.X, seed.Y, width, height)
+		}
+	}
+
+	values := pixelValues(img)
+	directions := opts.Connectivity.neighbours()
+
 	segmented := make([][]int, height)
+	queued := make([][]int, height)
 	for y := range segmented {
 		segmented[y] = make([]int, width)
+		queued[y] = make([]int, width)
 		for x := range segmented[y] {
 			segmented[y][x] = -1
+			queued[y][x] = -1
 		}
 	}
 
@@ -146,34 +305,365 @@ func RegionGrowing(img image.Image, seeds []Point, criterion DistanceCriterion,
 		}
 
 		queue := []Point{seed}
-		seedValue := getPixelValue(img.At(seed.X, seed.Y))
+		queued[seed.Y][seed.X] = label
+		seedValue := values[seed.Y][seed.X]
+		statistics := newRegionStatistics(len(seedValue))
 		regionColor := randomColor()
 
 		for len(queue) > 0 {
 			current := queue[0]
 			queue = queue[1:]
 
-			if segmented[current.Y][current.X] != -1 {
+			segmented[current.Y][current.X] = label
+			outputImage.Set(bounds.Min.X+current.X, bounds.Min.Y+current.Y, regionColor)
+			statistics.add(values[current.Y][current.X])
+
+			reference, threshold := seedValue, opts.Threshold
+			if opts.Reference != ReferenceSeed {
+				reference = statistics.mean()
+			}
+			if opts.Reference == ReferenceStatistics {
+				deviation := statistics.deviation()
+				threshold += opts.Deviations * calculateDistance(opts.Criterion, deviation, make([]float64, len(deviation)))
+			}
+
+			for _, d := range directions {
+				neighbor := Point{X: current.X + d.X, Y: current.Y + d.Y}
+				if neighbor.X < 0 || neighbor.X >= width || neighbor.Y < 0 || neighbor.Y >= height {
+					continue
+				}
+				// a pixel rejected now may still join the region through another neighbour later
+				if segmented[neighbor.Y][neighbor.X] != -1 || queued[neighbor.Y][neighbor.X] == label {
+					continue
+				}
+
+				if calculateDistance(opts.Criterion, values[neighbor.Y][neighbor.X], reference) <= threshold {
+					queued[neighbor.Y][neighbor.X] = label
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		label++
+	}
+
+	return segmented, outputImage, nil
+}
+
+// RegionCount returns the number of regions of a label matrix, whose labels run from 0.
+func RegionCount(labels [][]int) int {
+	count := 0
+	for y := range labels {
+		for _, label := range labels[y] {
+			count = max(count, label+1)
+		}
+	}
+	return count
+}
+
+// drawRegions paints every region of labels in a random color, pixels outside of every region stay black.
+func drawRegions(bounds image.Rectangle, labels [][]int) *image.RGBA {
+	outputImage := image.NewRGBA(bounds)
+	colors := make([]color.Color, RegionCount(labels))
+	for i := range colors {
+		colors[i] = randomColor()
+	}
+
+	for y := range labels {
+		for x, label := range labels[y] {
+			if label >= 0 {
+				outputImage.Set(bounds.Min.X+x, bounds.Min.Y+y, colors[label])
+			}
+		}
+	}
+	return outputImage
+}
+
+// RegionAdjacencyGraph holds the regions of a label matrix and the regions each of them touches.
+type RegionAdjacencyGraph struct {
+	// Labels is the label matrix with the regions renumbered from 0 in row-major order, -1 outside of every region.
+	Labels [][]int
+	// Regions are indexed by their label.
+	Regions []Region
+	// Neighbours lists the labels of the regions touching every region in increasing order.
+	Neighbours [][]int
+
+	statistics []*regionStatistics
+}
+
+// BuildRegionAdjacencyGraph collects the regions of labels with their mean value in img, negative labels belong to
+// no region. Two regions are adjacent when a pixel of one is a neighbour of a pixel of the other.
+func BuildRegionAdjacencyGraph(img image.Image, labels [][]int, connectivity Connectivity) (*RegionAdjacencyGraph, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if len(labels) != height || (height > 0 && len(labels[0]) != width) {
+		return nil, fmt.Errorf("label matrix size does not match the %dx%d image", width, height)
+	}
+	if connectivity != Connectivity4 && connectivity != Connectivity8 {
+		return nil, fmt.Errorf("connectivity must be 4 or 8, got %d", connectivity)
+	}
+
+	values := pixelValues(img)
+	graph := &RegionAdjacencyGraph{Labels: make([][]int, height)}
+	renumbered := map[int]int{}
+
+	for y := range labels {
+		if len(labels[y]) != width {
+			return nil, fmt.Errorf("label matrix row %d has %d columns, expected %d", y, len(labels[y]), width)
+		}
+
+		graph.Labels[y] = make([]int, width)
+		for x, label := range labels[y] {
+			if label < 0 {
+				graph.Labels[y][x] = -1
 				continue
 			}
 
-			segmented[current.Y][current.X] = label
-			outputImage.Set(current.X, current.Y, regionColor)
+			index, exists := renumbered[label]
+			if !exists {
+				index = len(graph.Regions)
+				renumbered[label] = index
+				graph.Regions = append(graph.Regions, Region{Label: index})
+				graph.statistics = append(graph.statistics, newRegionStatistics(len(values[y][x])))
+			}
+
+			graph.Labels[y][x] = index
+			graph.Regions[index].Pixels = append(graph.Regions[index].Pixels, Point{X: x, Y: y})
+			graph.statistics[index].add(values[y][x])
+		}
+	}
 
-			for _, neighbor := range getNeighbors(current, width, height) {
-				if segmented[neighbor.Y][neighbor.X] == -1 {
-					neighborValue := getPixelValue(img.At(neighbor.X, neighbor.Y))
-					distance := calculateDistance(criterion, neighborValue, seedValue)
+	adjacent := make([]map[int]bool, len(graph.Regions))
+	for i := range adjacent {
+		adjacent[i] = map[int]bool{}
+		graph.Regions[i].Mean = graph.statistics[i].mean()
+	}
 
-					if distance <= threshold {
-						queue = append(queue, neighbor)
-					}
+	for y := range graph.Labels {
+		for x, label := range graph.Labels[y] {
+			if label < 0 {
+				continue
+			}
+			for _, d := range connectivity.nextNeighbours() {
+				nx, ny := x+d.X, y+d.Y
+				if nx < 0 || nx >= width || ny >= height {
+					continue
+				}
+				if other := graph.Labels[ny][nx]; other >= 0 && other != label {
+					adjacent[label][other] = true
+					adjacent[other][label] = true
 				}
 			}
 		}
+	}
 
-		label++
+	graph.Neighbours = make([][]int, len(adjacent))
+	for i, neighbours := range adjacent {
+		for neighbour := range neighbours {
+			graph.Neighbours[i] = append(graph.Neighbours[i], neighbour)
+		}
+		sort.Ints(graph.Neighbours[i])
 	}
 
-	return segmented, outputImage
+	return graph, nil
+}
+
+// regionEdge is a pair of adjacent regions waiting to be merged, distance is the distance between their means
+// when the edge was queued.
+type regionEdge struct {
+	a, b     int
+	distance float64
+}
+
+type regionEdgeHeap []regionEdge
+
+func (h regionEdgeHeap) Len() int { return len(h) }
+func (h regionEdgeHeap) Less(i, j int) bool {
+	if h[i].distance != h[j].distance {
+		return h[i].distance < h[j].distance
+	}
+	if h[i].a != h[j].a {
+		return h[i].a < h[j].a
+	}
+	return h[i].b < h[j].b
+}
+func (h regionEdgeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *regionEdgeHeap) Push(x any)   { *h = append(*h, x.(regionEdge)) }
+func (h *regionEdgeHeap) Pop() any {
+	old := *h
+	edge := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return edge
+}
+
+// merge joins adjacent regions, the pair with the closest means first, as long as accept allows the union.
+// Queued distances are updated lazily, an edge whose regions drifted apart is queued again when it comes up,
+// and a pair accept rejected is not tried again. Regions smaller than minArea are then merged into the neighbour with the closest mean regardless of accept.
+// It returns the label matrix of the merged regions renumbered from 0, the graph is not modified.
+func (g *RegionAdjacencyGraph) merge(criterion DistanceCriterion, accept func(a, b *regionStatistics) bool, minArea int) [][]int {
+	parent := make([]int, len(g.Regions))
+	statistics := make([]*regionStatistics, len(g.Regions))
+	adjacent := make([]map[int]bool, len(g.Regions))
+	for i := range parent {
+		parent[i] = i
+		statistics[i] = g.statistics[i]
+		adjacent[i] = map[int]bool{}
+		for _, neighbour := range g.Neighbours[i] {
+			adjacent[i][neighbour] = true
+		}
+	}
+
+	distance := func(a, b int) float64 {
+		return calculateDistance(criterion, statistics[a].mean(), statistics[b].mean())
+	}
+
+	// union keeps the region with more neighbours as the root so that fewer adjacency entries move,
+	// it returns the root and the neighbours it gained
+	union := func(a, b int) (int, []int) {
+		if len(adjacent[a]) < len(adjacent[b]) {
+			a, b = b, a
+		}
+		parent[b] = a
+		statistics[a] = statistics[a].join(statistics[b])
+
+		var gained []int
+		for neighbour := range adjacent[b] {
+			delete(adjacent[neighbour], b)
+			if neighbour != a && !adjacent[a][neighbour] {
+				adjacent[a][neighbour] = true
+				adjacent[neighbour][a] = true
+				gained = append(gained, neighbour)
+			}
+		}
+		adjacent[b] = nil
+		return a, gained
+	}
+
+	edges := &regionEdgeHeap{}
+	push := func(a, b int) {
+		if a > b {
+			a, b = b, a
+		}
+		heap.Push(edges, regionEdge{a: a, b: b, distance: distance(a, b)})
+	}
+
+	for a := range adjacent {
+		for b := range adjacent[a] {
+			if a < b {
+				push(a, b)
+			}
+		}
+	}
+
+	for edges.Len() > 0 {
+		edge := heap.Pop(edges).(regionEdge)
+		if parent[edge.a] != edge.a || parent[edge.b] != edge.b {
+			continue
+		}
+		if distance(edge.a, edge.b) > edge.distance {
+			push(edge.a, edge.b)
+			continue
+		}
+		if !accept(statistics[edge.a], statistics[edge.b]) {
+			continue
+		}
+
+		// the other edges of the root are updated when they come up
+		root, gained := union(edge.a, edge.b)
+		for _, neighbour := range gained {
+			push(root, neighbour)
+		}
+	}
+
+	for minArea > 0 {
+		var small []int
+		for i := range parent {
+			if parent[i] == i && statistics[i].count < minArea && len(adjacent[i]) > 0 {
+				small = append(small, i)
+			}
+		}
+		if len(small) == 0 {
+			break
+		}
+		sort.SliceStable(small, func(i, j int) bool { return statistics[small[i]].count < statistics[small[j]].count })
+
+		for _, a := range small {
+			if parent[a] != a || statistics[a].count >= minArea || len(adjacent[a]) == 0 {
+				continue
+			}
+
+			best, bestDistance := -1, math.Inf(1)
+			for neighbour := range adjacent[a] {
+				if d := distance(a, neighbour); d < bestDistance || (d == bestDistance && neighbour < best) {
+					best, bestDistance = neighbour, d
+				}
+			}
+			union(a, best)
+		}
+	}
+
+	find := func(label int) int {
+		root := label
+		for parent[root] != root {
+			root = parent[root]
+		}
+		for parent[label] != root {
+			parent[label], label = root, parent[label]
+		}
+		return root
+	}
+
+	merged := make([][]int, len(g.Labels))
+	renumbered := map[int]int{}
+	for y := range g.Labels {
+		merged[y] = make([]int, len(g.Labels[y]))
+		for x, label := range g.Labels[y] {
+			if label < 0 {
+				merged[y][x] = -1
+				continue
+			}
+
+			root := find(label)
+			index, exists := renumbered[root]
+			if !exists {
+				index = len(renumbered)
+				renumbered[root] = index
+			}
+			merged[y][x] = index
+		}
+	}
+	return merged
+}
+
+// MergeOptions configures MergeRegions.
+type MergeOptions struct {
+	Criterion DistanceCriterion
+	// Threshold is the largest distance between the means of two adjacent regions that are merged.
+	Threshold float64
+	// MinArea merges every smaller region into its most similar neighbour at the end, 0 disables it.
+	MinArea      int
+	Connectivity Connectivity
+}
+
+// MergeRegions merges adjacent regions of labels whose mean values are at most the threshold apart, the most
+// similar pair first, updating the mean of a region after every merge. Negative labels belong to no region
+// and are kept. It returns the merged labels renumbered from 0 and the regions drawn in random colors.
+//
+// Reference: A. Tremeau, P. Colantoni - Regions adjacency graph applied to color image segmentation (2000)
+func MergeRegions(img image.Image, labels [][]int, opts MergeOptions) ([][]int, *image.RGBA, error) {
+	if opts.Threshold < 0 {
+		return nil, nil, fmt.Errorf("threshold must not be negative, got %v", opts.Threshold)
+	}
+
+	graph, err := BuildRegionAdjacencyGraph(img, labels, opts.Connectivity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := graph.merge(opts.Criterion, func(a, b *regionStatistics) bool {
+		return calculateDistance(opts.Criterion, a.mean(), b.mean()) <= opts.Threshold
+	}, opts.MinArea)
+
+	return merged, drawRegions(img.Bounds(), merged), nil
 }
@@ -0,0 +1,310 @@
+package morphological
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func regionSize(segmented [][]int, label int) int {
+	size := 0
+	for y := range segmented {
+		for x := range segmented[y] {
+			if segmented[y][x] == label {
+				size++
+			}
+		}
+	}
+	return size
+}
+
+func TestGrowRegionsMeanFollowsGradient(t *testing.T) {
+	// a ramp getting brighter by 1 per column with a sharp step after 30 columns
+	gray := newGrayImage(10, 40)
+	for y := range gray {
+		for x := range gray[y] {
+			gray[y][x] = x
+			if x >= 30 {
+				gray[y][x] += 100
+			}
+		}
+	}
+	img := ConvertGrayIntoImage(gray)
+	seeds := []Point{{X: 0, Y: 5}}
+
+	opts := RegionGrowingOptions{Criterion: Euclidean, Threshold: 10, Reference: ReferenceSeed, Connectivity: Connectivity8}
+	bySeed, _, err := GrowRegions(img, seeds, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := regionSize(bySeed, 0); got != 11*10 {
+		t.Errorf("comparing to the seed grew %d pixels, want the 11 columns within the threshold", got)
+	}
+
+	opts.Reference = ReferenceMean
+	byMean, _, err := GrowRegions(img, seeds, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := regionSize(byMean, 0); got <= 11*10 || got >= 30*10 {
+		t.Errorf("comparing to the mean grew %d pixels, want more than the seed but not the whole ramp", got)
+	}
+
+	opts.Reference = ReferenceStatistics
+	opts.Deviations = 2
+	byStatistics, _, err := GrowRegions(img, seeds, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := regionSize(byStatistics, 0); got != 30*10 {
+		t.Errorf("comparing to the statistics grew %d pixels, want the ramp up to the step", got)
+	}
+}
+
+func TestGrowRegionsConnectivity(t *testing.T) {
+	img := newBinaryImage(6, 6)
+	fillRect(img, 0, 0, 3, 3)
+	fillRect(img, 3, 3, 3, 3)
+	seeds := []Point{{X: 1, Y: 1}, {X: 4, Y: 4}}
+
+	opts := RegionGrowingOptions{Criterion: Euclidean, Threshold: 0, Reference: ReferenceMean, Connectivity: Connectivity8}
+	segmented, _, err := GrowRegions(ConvertIntoImage(img), seeds, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segmented[4][4] != 0 {
+		t.Error("the squares touching by a corner should be one region with 8-connectivity")
+	}
+
+	opts.Connectivity = Connectivity4
+	segmented, _, err = GrowRegions(ConvertIntoImage(img), seeds, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if segmented[1][1] != 0 || segmented[4][4] != 1 {
+		t.Error("the squares touching by a corner should be separate regions with 4-connectivity")
+	}
+	// the background has no seed
+	if segmented[0][5] != -1 {
+		t.Error("pixels outside of every region should be labeled -1")
+	}
+}
+
+func TestGrowRegionsValidatesSeeds(t *testing.T) {
+	img := ConvertIntoImage(newBinaryImage(5, 5))
+	opts := RegionGrowingOptions{Reference: ReferenceSeed, Connectivity: Connectivity8}
+	if _, _, err := GrowRegions(img, []Point{{X: 5, Y: 0}}, opts); err == nil {
+		t.Error("expected an error for a seed outside of the image")
+	}
+
+	segmented, _ := RegionGrowing(img, []Point{{X: 9, Y: 9}, {X: 2, Y: 2}}, Euclidean, 1)
+	if regionSize(segmented, 0) != 25 {
+		t.Error("RegionGrowing should skip seeds outside of the image")
+	}
+}
+
+func TestGenerateSeeds(t *testing.T) {
+	gray := newGrayImage(40, 60)
+	for y := range gray {
+		for x := range gray[y] {
+			gray[y][x] = 100
+		}
+	}
+	for y := 5; y < 15; y++ {
+		for x := 5; x < 20; x++ {
+			gray[y][x] = 220
+		}
+	}
+	for y := 25; y < 35; y++ {
+		for x := 40; x < 50; x++ {
+			gray[y][x] = 20
+		}
+	}
+	img := ConvertGrayIntoImage(gray)
+
+	grid, err := GenerateSeeds(img, SeedingOptions{Strategy: SeedGrid, Count: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Point{{10, 10}, {30, 10}, {50, 10}, {10, 30}, {30, 30}, {50, 30}}; !reflect.DeepEqual(grid, want) {
+		t.Errorf("grid seeds = %v, want %v", grid, want)
+	}
+
+	extrema, err := GenerateSeeds(img, SeedingOptions{Strategy: SeedExtrema})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extrema) != 2 || gray[extrema[0].Y][extrema[0].X] != 220 || gray[extrema[1].Y][extrema[1].X] != 20 {
+		t.Errorf("extrema seeds = %v, want one in the bright and one in the dark square", extrema)
+	}
+
+	peaks, err := GenerateSeeds(img, SeedingOptions{Strategy: SeedHistogram, Count: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peaks) != 2 || gray[peaks[0].Y][peaks[0].X] != 100 || gray[peaks[1].Y][peaks[1].X] != 220 {
+		t.Errorf("histogram seeds = %v, want the background and then the larger square", peaks)
+	}
+
+	random, err := GenerateSeeds(img, SeedingOptions{Strategy: SeedRandom, Count: 20, RandomSeed: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := GenerateSeeds(img, SeedingOptions{Strategy: SeedRandom, Count: 20, RandomSeed: 3})
+	if len(random) != 20 || !reflect.DeepEqual(random, again) {
+		t.Error("random seeding should give 20 seeds reproducible from the random seed")
+	}
+
+	if _, err := GenerateSeeds(img, SeedingOptions{Strategy: SeedGrid}); err == nil {
+		t.Error("expected an error for grid seeding without a count")
+	}
+	if _, err := ParseSeedStrategy("corners"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestLoadSeedPoints(t *testing.T) {
+	dir := t.TempDir()
+	want := []Point{{X: 1, Y: 2}, {X: 30, Y: 4}}
+
+	for name, content := range map[string]string{
+		"objects.json": `[{"x": 1, "y": 2}, {"x": 30, "y": 4}]`,
+		"pairs.json":   `[[1, 2], [30, 4]]`,
+		"seeds.txt":    "[1,2]\n[30, 4]\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := LoadSeedPoints(path)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", name, got, want)
+		}
+	}
+
+	if _, err := LoadSeedPoints(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestBuildRegionAdjacencyGraph(t *testing.T) {
+	gray := newGrayImage(2, 4)
+	for y := range gray {
+		for x := range gray[y] {
+			gray[y][x] = 10 * x
+		}
+	}
+	labels := [][]int{
+		{7, 7, 3, -1},
+		{7, 5, 3, 3},
+	}
+
+	graph, err := BuildRegionAdjacencyGraph(ConvertGrayIntoImage(gray), labels, Connectivity4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Regions) != 3 || graph.Labels[0][2] != 1 || graph.Labels[1][1] != 2 || graph.Labels[0][3] != -1 {
+		t.Fatalf("regions should be renumbered in row-major order, got %v", graph.Labels)
+	}
+	if want := [][]int{{1, 2}, {0, 2}, {0, 1}}; !reflect.DeepEqual(graph.Neighbours, want) {
+		t.Errorf("neighbours = %v, want %v", graph.Neighbours, want)
+	}
+	if mean := graph.Regions[1].Mean; len(mean) != 1 || mean[0] != 70.0/3 {
+		t.Errorf("mean of the region of label 3 = %v, want [%v]", mean, 70.0/3)
+	}
+	if len(graph.Regions[0].Pixels) != 3 {
+		t.Errorf("the region of label 7 has %d pixels, want 3", len(graph.Regions[0].Pixels))
+	}
+
+	if _, err := BuildRegionAdjacencyGraph(ConvertGrayIntoImage(gray), labels[:1], Connectivity4); err == nil {
+		t.Error("expected an error for a label matrix of another size")
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	// 4x4 blocks of 4x4 pixels, dark on the left half and bright on the right with a slightly different bottom
+	gray := newGrayImage(16, 16)
+	labels := make([][]int, 16)
+	for y := range gray {
+		labels[y] = make([]int, 16)
+		for x := range gray[y] {
+			gray[y][x] = 10
+			if x >= 8 {
+				gray[y][x] = 200
+			}
+			if y >= 8 {
+				gray[y][x] += 4
+			}
+			labels[y][x] = (y/4)*4 + x/4
+		}
+	}
+	// a single pixel region of its own
+	gray[5][5] = 100
+	labels[5][5] = 16
+	img := ConvertGrayIntoImage(gray)
+
+	opts := MergeOptions{Criterion: Euclidean, Threshold: 10, Connectivity: Connectivity8}
+	merged, _, err := MergeRegions(img, labels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if RegionCount(merged) != 3 {
+		t.Fatalf("merged into %d regions, want the two halves and the single pixel", RegionCount(merged))
+	}
+	if merged[0][0] != merged[15][7] || merged[0][8] != merged[15][15] || merged[0][0] == merged[0][8] {
+		t.Error("the halves should be merged into one region each")
+	}
+
+	opts.MinArea = 2
+	merged, _, err = MergeRegions(img, labels, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if RegionCount(merged) != 2 || merged[5][5] != merged[0][0] {
+		t.Error("the single pixel region should be merged into the dark half with a minimum area")
+	}
+}
+
+func TestSplitAndMerge(t *testing.T) {
+	gray := newGrayImage(24, 32)
+	for y := range gray {
+		for x := range gray[y] {
+			gray[y][x] = 50
+			if x >= 5 && x < 21 && y >= 3 && y < 18 {
+				gray[y][x] = 150
+			}
+		}
+	}
+	img := ConvertGrayIntoImage(gray)
+
+	for _, predicate := range []HomogeneityPredicate{HomogeneityDeviation, HomogeneityRange} {
+		opts := SplitMergeOptions{Criterion: Euclidean, Predicate: predicate, Threshold: 0, MinSize: 1, Connectivity: Connectivity4}
+		labels, _, err := SplitAndMerge(img, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if RegionCount(labels) != 2 {
+			t.Errorf("%s: split and merged into %d regions, want 2", predicate, RegionCount(labels))
+			continue
+		}
+		for y := range gray {
+			for x := range gray[y] {
+				if (labels[y][x] == labels[10][10]) != (gray[y][x] == 150) {
+					t.Fatalf("%s: pixel [%d,%d] is in the wrong region", predicate, x, y)
+				}
+			}
+		}
+	}
+
+	if _, _, err := SplitAndMerge(img, SplitMergeOptions{Predicate: HomogeneityRange, Connectivity: Connectivity4}); err == nil {
+		t.Error("expected an error for a minimum block size of 0")
+	}
+	if _, err := ParseHomogeneityPredicate("entropy"); err == nil {
+		t.Error("expected an error for an unknown predicate")
+	}
+}
@@ -0,0 +1,252 @@
+package morphological
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+)
+
+// SeedStrategy selects how GenerateSeeds places the seeds of region growing.
+type SeedStrategy string
+
+const (
+	// SeedGrid places the seeds at the centres of the cells of a regular grid.
+	SeedGrid SeedStrategy = "grid"
+	// SeedExtrema places a seed in every regional maximum and minimum of the luma, largest plateaus first.
+	SeedExtrema SeedStrategy = "extrema"
+	// SeedHistogram places a seed on the flattest pixel of every peak of the luma histogram, highest peaks first.
+	SeedHistogram SeedStrategy = "histogram"
+	// SeedRandom places the seeds on distinct pixels drawn from a seeded random generator.
+	SeedRandom SeedStrategy = "random"
+)
+
+// SeedStrategies lists the strategies accepted by ParseSeedStrategy.
+var SeedStrategies = []SeedStrategy{SeedGrid, SeedExtrema, SeedHistogram, SeedRandom}
+
+// ParseSeedStrategy parses grid, extrema, histogram or random, peaks is accepted for histogram.
+func ParseSeedStrategy(value string) (SeedStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case string(SeedGrid):
+		return SeedGrid, nil
+	case string(SeedExtrema):
+		return SeedExtrema, nil
+	case string(SeedHistogram), "peaks":
+		return SeedHistogram, nil
+	case string(SeedRandom):
+		return SeedRandom, nil
+	default:
+		return "", fmt.Errorf("unknown seeding strategy %q, expected grid, extrema, histogram or random", value)
+	}
+}
+
+// SeedingOptions configures GenerateSeeds. Count is the number of seeds, it is required for grid and random
+// and limits extrema and histogram when positive. RandomSeed makes random seeding reproducible.
+type SeedingOptions struct {
+	Strategy   SeedStrategy
+	Count      int
+	RandomSeed int64
+}
+
+// GenerateSeeds places region growing seeds on img.
+func GenerateSeeds(img image.Image, opts SeedingOptions) ([]Point, error) {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, nil
+	}
+	if opts.Count < 0 || (opts.Count == 0 && (opts.Strategy == SeedGrid || opts.Strategy == SeedRandom)) {
+		return nil, fmt.Errorf("%s seeding needs a positive number of seeds, got %d", opts.Strategy, opts.Count)
+	}
+
+	switch opts.Strategy {
+	case SeedGrid:
+		return gridSeeds(width, height, opts.Count), nil
+	case SeedExtrema:
+		return extremaSeeds(ConvertIntoGrayImage(img), opts.Count), nil
+	case SeedHistogram:
+		return histogramSeeds(ConvertIntoGrayImage(img), opts.Count), nil
+	case SeedRandom:
+		return randomSeeds(width, height, opts.Count, opts.RandomSeed), nil
+	default:
+		return nil, fmt.Errorf("unknown seeding strategy %q", opts.Strategy)
+	}
+}
+
+// gridSeeds splits the image into about count cells of roughly square shape.
+func gridSeeds(width, height, count int) []Point {
+	cols := max(1, min(width, int(math.Round(math.Sqrt(float64(count)*float64(width)/float64(height))))))
+	rows := max(1, min(height, (count+cols-1)/cols))
+
+	seeds := make([]Point, 0, rows*cols)
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			seeds = append(seeds, Point{X: (2*col + 1) * width / (2 * cols), Y: (2*row + 1) * height / (2 * rows)})
+		}
+	}
+	return seeds
+}
+
+func extremaSeeds(gray GrayImage, count int) []Point {
+	inverted := newGrayImage(len(gray), len(gray[0]))
+	for y := range gray {
+		for x := range gray[y] {
+			inverted[y][x] = 255 - gray[y][x]
+		}
+	}
+
+	var components []Component
+	for _, extrema := range []BinaryImage{RegionalMaxima(gray, Connectivity8), RegionalMaxima(inverted, Connectivity8)} {
+		components = append(components, LabelComponents(extrema, Connectivity8).Components...)
+	}
+	sort.SliceStable(components, func(i, j int) bool { return components[i].Area > components[j].Area })
+	if count > 0 && len(components) > count {
+		components = components[:count]
+	}
+
+	// the pixel of the plateau closest to its centroid, which may lie outside of a bent plateau
+	seeds := make([]Point, len(components))
+	for i, component := range components {
+		var cx, cy float64
+		for _, p := range component.Pixels {
+			cx += float64(p.X)
+			cy += float64(p.Y)
+		}
+		cx /= float64(component.Area)
+		cy /= float64(component.Area)
+
+		best := math.Inf(1)
+		for _, p := range component.Pixels {
+			if d := math.Hypot(float64(p.X)-cx, float64(p.Y)-cy); d < best {
+				best, seeds[i] = d, p
+			}
+		}
+	}
+	return seeds
+}
+
+func histogramSeeds(gray GrayImage, count int) []Point {
+	var histogram [256]int
+	for y := range gray {
+		for x := range gray[y] {
+			histogram[gray[y][x]]++
+		}
+	}
+
+	// a moving average over 5 bins keeps noise from making every bin a peak
+	var smoothed [256]float64
+	for i := range smoothed {
+		n := 0
+		for j := max(0, i-2); j <= min(255, i+2); j++ {
+			smoothed[i] += float64(histogram[j])
+			n++
+		}
+		smoothed[i] /= float64(n)
+	}
+
+	var peaks []int
+	for i := range smoothed {
+		left, right := -1.0, -1.0
+		if i > 0 {
+			left = smoothed[i-1]
+		}
+		if i < 255 {
+			right = smoothed[i+1]
+		}
+		if smoothed[i] > 0 && smoothed[i] > left && smoothed[i] >= right {
+			peaks = append(peaks, i)
+		}
+	}
+	sort.SliceStable(peaks, func(i, j int) bool { return smoothed[peaks[i]] > smoothed[peaks[j]] })
+	if count > 0 && len(peaks) > count {
+		peaks = peaks[:count]
+	}
+
+	activity := localActivity(gray)
+	seeds := make([]Point, 0, len(peaks))
+	for _, peak := range peaks {
+		bestDistance, bestActivity := math.MaxInt, math.MaxInt
+		var seed Point
+		for y := range gray {
+			for x := range gray[y] {
+				distance := abs(gray[y][x] - peak)
+				if distance < bestDistance || (distance == bestDistance && activity[y][x] < bestActivity) {
+					bestDistance, bestActivity, seed = distance, activity[y][x], Point{X: x, Y: y}
+				}
+			}
+		}
+		seeds = append(seeds, seed)
+	}
+	return seeds
+}
+
+// localActivity sums the absolute differences between every pixel and its 8 neighbours.
+func localActivity(gray GrayImage) [][]int {
+	activity := make([][]int, len(gray))
+	for y := range gray {
+		activity[y] = make([]int, len(gray[y]))
+		for x := range gray[y] {
+			for _, d := range neighbourOffsets {
+				nx, ny := x+d.X, y+d.Y
+				if ny >= 0 && ny < len(gray) && nx >= 0 && nx < len(gray[y]) {
+					activity[y][x] += abs(gray[y][x] - gray[ny][nx])
+				}
+			}
+		}
+	}
+	return activity
+}
+
+func randomSeeds(width, height, count int, seed int64) []Point {
+	random := rand.New(rand.NewSource(seed))
+	if count >= width*height {
+		count = width * height
+	}
+
+	seeds := make([]Point, 0, count)
+	taken := make(map[int]bool, count)
+	for len(seeds) < count {
+		index := random.Intn(width * height)
+		if taken[index] {
+			continue
+		}
+		taken[index] = true
+		seeds = append(seeds, Point{X: index % width, Y: index / width})
+	}
+	return seeds
+}
+
+// LoadSeedPoints reads seed points from a file holding a JSON array of {"x":..,"y":..} objects or [x,y] pairs,
+// or text with the [x,y] points accepted by ParseSeedPoints.
+func LoadSeedPoints(filenamePath string) ([]Point, error) {
+	bytes, err := os.ReadFile(filenamePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read seed file: %w", err)
+	}
+
+	var points []Point
+	if err := json.Unmarshal(bytes, &points); err == nil {
+		if len(points) == 0 {
+			return nil, fmt.Errorf("no seed points found in %s", filenamePath)
+		}
+		return points, nil
+	}
+
+	var pairs [][2]int
+	if err := json.Unmarshal(bytes, &pairs); err == nil {
+		if len(pairs) == 0 {
+			return nil, fmt.Errorf("no seed points found in %s", filenamePath)
+		}
+		points = make([]Point, len(pairs))
+		for i, pair := range pairs {
+			points[i] = Point{X: pair[0], Y: pair[1]}
+		}
+		return points, nil
+	}
+
+	return ParseSeedPoints(string(bytes))
+}
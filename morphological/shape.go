@@ -0,0 +1,314 @@
+package morphological
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"io"
+	"math"
+	"strconv"
+)
+
+// BoundingBox is the smallest axis aligned rectangle holding every pixel of a component.
+type BoundingBox struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Rectangle returns the box as an image.Rectangle.
+func (b BoundingBox) Rectangle() image.Rectangle {
+	return image.Rect(b.X, b.Y, b.X+b.Width, b.Y+b.Height)
+}
+
+// Centroid is the center of mass of a component.
+type Centroid struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Moments holds the image moments of a component up to the third order.
+// Raw[p][q] is the sum of x^p * y^q, Central[p][q] the same around the centroid.
+type Moments struct {
+	Raw     [4][4]float64 `json:"raw"`
+	Central [4][4]float64 `json:"central"`
+	// Hu are the seven moment invariants, unchanged by translation, scale and rotation.
+	Hu [7]float64 `json:"hu"`
+}
+
+// ShapeDescriptors measures a single connected component of a binary image.
+type ShapeDescriptors struct {
+	Label     int         `json:"label"`
+	Area      int         `json:"area"`
+	Perimeter float64     `json:"perimeter"`
+	Centroid  Centroid    `json:"centroid"`
+	Box       BoundingBox `json:"bounding_box"`
+	// Circularity is 4*pi*area/perimeter^2, close to 1 for discs and smaller for elongated or ragged shapes.
+	Circularity float64 `json:"circularity"`
+	// Eccentricity of the ellipse with the same second moments, 0 for a circle and approaching 1 for a line.
+	Eccentricity float64 `json:"eccentricity"`
+	// Orientation is the angle between the x-axis and the major axis in degrees, counter-clockwise as seen on screen.
+	Orientation float64 `json:"orientation"`
+	Moments     Moments `json:"moments"`
+}
+
+// perimeterWeights maps the neighbourhood code of a border pixel to its contribution to the perimeter.
+var perimeterWeights = map[int]float64{
+	5: 1, 7: 1, 15: 1, 17: 1, 25: 1, 27: 1,
+	21: math.Sqrt2, 33: math.Sqrt2,
+	13: (1 + math.Sqrt2) / 2, 23: (1 + math.Sqrt2) / 2,
+}
+
+// estimatePerimeter weights every border pixel of the mask by the configuration of its border neighbours,
+// which approximates the length of the contour much better than counting pixels or edges.
+//
+// Reference: Benkrid, Crookes, Benkrid - Design and FPGA implementation of a perimeter estimator (2000)
+func estimatePerimeter(mask [][]bool) float64 {
+	rows := len(mask)
+	cols := len(mask[0])
+
+	at := func(grid [][]bool, x, y int) bool {
+		return x >= 0 && x < cols && y >= 0 && y < rows && grid[y][x]
+	}
+
+	// border pixels are foreground pixels with a background 4-neighbour
+	border := make([][]bool, rows)
+	for y := range border {
+		border[y] = make([]bool, cols)
+		for x := range border[y] {
+			if mask[y][x] {
+				border[y][x] = !at(mask, x-1, y) || !at(mask, x+1, y) || !at(mask, x, y-1) || !at(mask, x, y+1)
+			}
+		}
+	}
+
+	kernel := [3][3]int{
+		{10, 2, 10},
+		{2, 1, 2},
+		{10, 2, 10},
+	}
+
+	var perimeter float64
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; x++ {
+			if !border[y][x] {
+				continue
+			}
+
+			code := 0
+			for ky := -1; ky <= 1; ky++ {
+				for kx := -1; kx <= 1; kx++ {
+					if at(border, x+kx, y+ky) {
+						code += kernel[ky+1][kx+1]
+					}
+				}
+			}
+			perimeter += perimeterWeights[code]
+		}
+	}
+
+	return perimeter
+}
+
+func huMoments(central [4][4]float64) [7]float64 {
+	m00 := central[0][0]
+
+	eta := func(p, q int) float64 {
+		return central[p][q] / math.Pow(m00, 1+float64(p+q)/2)
+	}
+
+	n20, n02, n11 := eta(2, 0), eta(0, 2), eta(1, 1)
+	n30, n03, n21, n12 := eta(3, 0), eta(0, 3), eta(2, 1), eta(1, 2)
+
+	a, b := n30+n12, n21+n03
+
+	return [7]float64{
+		n20 + n02,
+		(n20-n02)*(n20-n02) + 4*n11*n11,
+		(n30-3*n12)*(n30-3*n12) + (3*n21-n03)*(3*n21-n03),
+		a*a + b*b,
+		(n30-3*n12)*a*(a*a-3*b*b) + (3*n21-n03)*b*(3*a*a-b*b),
+		(n20-n02)*(a*a-b*b) + 4*n11*a*b,
+		(3*n21-n03)*a*(a*a-3*b*b) - (n30-3*n12)*b*(3*a*a-b*b),
+	}
+}
+
+// describeComponent measures the component made of the given pixels.
+func describeComponent(label int, pixels []Point) ShapeDescriptors {
+	minX, minY := pixels[0].X, pixels[0].Y
+	maxX, maxY := minX, minY
+	for _, p := range pixels {
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+
+	d := ShapeDescriptors{
+		Label: label,
+		Area:  len(pixels),
+		Box:   BoundingBox{X: minX, Y: minY, Width: maxX - minX + 1, Height: maxY - minY + 1},
+	}
+
+	mask := make([][]bool, d.Box.Height)
+	for y := range mask {
+		mask[y] = make([]bool, d.Box.Width)
+	}
+
+	var sumX, sumY float64
+	for _, p := range pixels {
+		mask[p.Y-minY][p.X-minX] = true
+		sumX += float64(p.X)
+		sumY += float64(p.Y)
+	}
+
+	d.Centroid = Centroid{X: sumX / float64(d.Area), Y: sumY / float64(d.Area)}
+
+	for _, p := range pixels {
+		x, y := float64(p.X), float64(p.Y)
+		cx, cy := x-d.Centroid.X, y-d.Centroid.Y
+
+		for i := 0; i < 4; i++ {
+			for j := 0; i+j < 4; j++ {
+				d.Moments.Raw[i][j] += math.Pow(x, float64(i)) * math.Pow(y, float64(j))
+				d.Moments.Central[i][j] += math.Pow(cx, float64(i)) * math.Pow(cy, float64(j))
+			}
+		}
+	}
+
+	d.Moments.Hu = huMoments(d.Moments.Central)
+
+	d.Perimeter = estimatePerimeter(mask)
+	if d.Perimeter > 0 {
+		d.Circularity = 4 * math.Pi * float64(d.Area) / (d.Perimeter * d.Perimeter)
+	}
+
+	mu20 := d.Moments.Central[2][0] / float64(d.Area)
+	mu02 := d.Moments.Central[0][2] / float64(d.Area)
+	mu11 := d.Moments.Central[1][1] / float64(d.Area)
+
+	common := math.Sqrt(4*mu11*mu11 + (mu20-mu02)*(mu20-mu02))
+	major := (mu20 + mu02 + common) / 2
+	minor := (mu20 + mu02 - common) / 2
+	if major > 0 {
+		d.Eccentricity = math.Sqrt(math.Max(0, 1-minor/major))
+	}
+
+	// y grows downwards, the sign flip makes the angle counter-clockwise on screen
+	d.Orientation = -0.5 * math.Atan2(2*mu11, mu20-mu02) * 180 / math.Pi
+
+	return d
+}
+
+// DescribeShapes measures every 8-connected component of the image, labels start at 1.
+func DescribeShapes(img BinaryImage) []ShapeDescriptors {
+	return DescribeComponents(LabelComponents(img, Connectivity8))
+}
+
+// DescribeComponents measures the components of a labeling, keeping their labels.
+func DescribeComponents(labeling Labeling) []ShapeDescriptors {
+	shapes := make([]ShapeDescriptors, 0, len(labeling.Components))
+	for _, c := range labeling.Components {
+		shapes = append(shapes, describeComponent(c.Label, c.Pixels))
+	}
+	return shapes
+}
+
+// WriteShapesCSV writes one row per component, moments are flattened into m_pq, mu_pq and hu1 to hu7 columns.
+func WriteShapesCSV(w io.Writer, shapes []ShapeDescriptors) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"label", "area", "perimeter", "centroid_x", "centroid_y", "bbox_x", "bbox_y", "bbox_width", "bbox_height", "circularity", "eccentricity", "orientation"}
+	for _, prefix := range []string{"m", "mu"} {
+		for p := 0; p < 4; p++ {
+			for q := 0; p+q < 4; q++ {
+				header = append(header, fmt.Sprintf("%s%d%d", prefix, p, q))
+			}
+		}
+	}
+	for i := 1; i <= 7; i++ {
+		header = append(header, fmt.Sprintf("hu%d", i))
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	format := func(value float64) string {
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+
+	for _, s := range shapes {
+		record := []string{
+			strconv.Itoa(s.Label), strconv.Itoa(s.Area), format(s.Perimeter),
+			format(s.Centroid.X), format(s.Centroid.Y),
+			strconv.Itoa(s.Box.X), strconv.Itoa(s.Box.Y), strconv.Itoa(s.Box.Width), strconv.Itoa(s.Box.Height),
+			format(s.Circularity), format(s.Eccentricity), format(s.Orientation),
+		}
+		for _, moments := range [][4][4]float64{s.Moments.Raw, s.Moments.Central} {
+			for p := 0; p < 4; p++ {
+				for q := 0; p+q < 4; q++ {
+					record = append(record, format(moments[p][q]))
+				}
+			}
+		}
+		for _, hu := range s.Moments.Hu {
+			record = append(record, format(hu))
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteShapesJSON writes the components as an indented JSON array.
+func WriteShapesJSON(w io.Writer, shapes []ShapeDescriptors) error {
+	if shapes == nil {
+		shapes = []ShapeDescriptors{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(shapes)
+}
+
+var (
+	overlayBoxColor    = color.RGBA{255, 64, 64, 255}
+	overlayAxisColor   = color.RGBA{64, 160, 255, 255}
+	overlayCenterColor = color.RGBA{255, 220, 0, 255}
+)
+
+// DrawShapesOverlay draws the bounding box, major axis, centroid and label of every component over the binary image.
+func DrawShapesOverlay(img BinaryImage, shapes []ShapeDescriptors) *image.RGBA {
+	overlay := ConvertIntoImage(img)
+	// dim the foreground so that the annotations stand out
+	for i := 0; i < len(overlay.Pix); i += 4 {
+		if overlay.Pix[i] == 255 {
+			overlay.Pix[i], overlay.Pix[i+1], overlay.Pix[i+2] = 110, 110, 110
+		}
+	}
+
+	for _, s := range shapes {
+		manipulations.DrawRectangle(overlay, s.Box.Rectangle(), overlayBoxColor)
+
+		cx, cy := int(math.Round(s.Centroid.X)), int(math.Round(s.Centroid.Y))
+
+		// major axis spanning half of the larger box side on each side of the centroid
+		length := float64(max(s.Box.Width, s.Box.Height)) / 2
+		angle := -s.Orientation * math.Pi / 180
+		dx, dy := int(math.Round(length*math.Cos(angle))), int(math.Round(length*math.Sin(angle)))
+		manipulations.DrawLine(overlay, cx-dx, cy-dy, cx+dx, cy+dy, overlayAxisColor)
+
+		manipulations.DrawLine(overlay, cx-2, cy, cx+2, cy, overlayCenterColor)
+		manipulations.DrawLine(overlay, cx, cy-2, cx, cy+2, overlayCenterColor)
+
+		manipulations.DrawText(overlay, s.Box.X+2, s.Box.Y+11, strconv.Itoa(s.Label), overlayBoxColor)
+	}
+
+	return overlay
+}
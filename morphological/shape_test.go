@@ -0,0 +1,131 @@
+package morphological
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func newBinaryImage(width, height int) BinaryImage {
+	img := make(BinaryImage, height)
+	for y := range img {
+		img[y] = make([]int, width)
+	}
+	return img
+}
+
+func fillRect(img BinaryImage, x0, y0, width, height int) {
+	for y := y0; y < y0+height; y++ {
+		for x := x0; x < x0+width; x++ {
+			img[y][x] = 1
+		}
+	}
+}
+
+func TestDescribeShapesRectangle(t *testing.T) {
+	img := newBinaryImage(60, 30)
+	fillRect(img, 5, 10, 40, 10)
+
+	shapes := DescribeShapes(img)
+	if len(shapes) != 1 {
+		t.Fatalf("expected 1 component, got %d", len(shapes))
+	}
+
+	s := shapes[0]
+	if s.Area != 400 || s.Box != (BoundingBox{X: 5, Y: 10, Width: 40, Height: 10}) {
+		t.Errorf("unexpected area %d or bounding box %+v", s.Area, s.Box)
+	}
+	if s.Centroid.X != 24.5 || s.Centroid.Y != 14.5 {
+		t.Errorf("centroid = %+v, expected (24.5, 14.5)", s.Centroid)
+	}
+	if math.Abs(s.Orientation) > 1e-9 {
+		t.Errorf("orientation = %v, expected 0", s.Orientation)
+	}
+
+	// the variances of a w x h rectangle are (w^2-1)/12 and (h^2-1)/12
+	expectedEccentricity := math.Sqrt(1 - 99.0/1599.0)
+	if math.Abs(s.Eccentricity-expectedEccentricity) > 1e-9 {
+		t.Errorf("eccentricity = %v, expected %v", s.Eccentricity, expectedEccentricity)
+	}
+	if math.Abs(s.Perimeter-96) > 1e-9 {
+		t.Errorf("perimeter = %v, expected 96", s.Perimeter)
+	}
+}
+
+func TestDescribeShapesHuInvariance(t *testing.T) {
+	horizontal := newBinaryImage(50, 50)
+	fillRect(horizontal, 2, 2, 30, 8)
+	fillRect(horizontal, 2, 10, 8, 12)
+
+	// the same L shape rotated by 90 degrees and moved
+	rotated := newBinaryImage(50, 50)
+	for y := range horizontal {
+		for x := range horizontal[y] {
+			if horizontal[y][x] == 1 {
+				rotated[x+5][49-y-10] = 1
+			}
+		}
+	}
+
+	a, b := DescribeShapes(horizontal), DescribeShapes(rotated)
+	if len(a) != 1 || len(b) != 1 {
+		t.Fatalf("expected a single component in both images, got %d and %d", len(a), len(b))
+	}
+
+	for i := range a[0].Moments.Hu {
+		if math.Abs(a[0].Moments.Hu[i]-b[0].Moments.Hu[i]) > 1e-9 {
+			t.Errorf("hu%d differs after rotation: %v and %v", i+1, a[0].Moments.Hu[i], b[0].Moments.Hu[i])
+		}
+	}
+	if math.Abs(math.Abs(a[0].Orientation-b[0].Orientation)-90) > 1e-6 {
+		t.Errorf("expected orientations 90 degrees apart, got %v and %v", a[0].Orientation, b[0].Orientation)
+	}
+}
+
+func TestDescribeShapesDisc(t *testing.T) {
+	img := newBinaryImage(61, 61)
+	for y := range img {
+		for x := range img[y] {
+			if (x-30)*(x-30)+(y-30)*(y-30) <= 25*25 {
+				img[y][x] = 1
+			}
+		}
+	}
+	fillRect(img, 0, 0, 3, 3)
+
+	shapes := DescribeShapes(img)
+	if len(shapes) != 2 {
+		t.Fatalf("expected 2 components, got %d", len(shapes))
+	}
+
+	disc := shapes[1]
+	if disc.Circularity < 0.9 || disc.Circularity > 1.1 {
+		t.Errorf("circularity of a disc = %v, expected close to 1", disc.Circularity)
+	}
+	if disc.Eccentricity > 0.05 {
+		t.Errorf("eccentricity of a disc = %v, expected close to 0", disc.Eccentricity)
+	}
+}
+
+func TestWriteShapesCSV(t *testing.T) {
+	img := newBinaryImage(10, 10)
+	fillRect(img, 1, 1, 2, 2)
+	fillRect(img, 6, 6, 3, 3)
+
+	var buf bytes.Buffer
+	if err := WriteShapesCSV(&buf, DescribeShapes(img)); err != nil {
+		t.Fatalf("WriteShapesCSV returned error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[2], "2,9,") {
+		t.Errorf("second row = %q, expected label 2 with area 9", lines[2])
+	}
+	if columns := len(strings.Split(lines[0], ",")); columns != len(strings.Split(lines[1], ",")) {
+		t.Errorf("header has %d columns, rows have %d", columns, len(strings.Split(lines[1], ",")))
+	}
+}
@@ -0,0 +1,431 @@
+package morphological
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"io"
+	"sort"
+)
+
+// SkeletonAlgorithm selects how a binary image is thinned to its skeleton.
+type SkeletonAlgorithm string
+
+const (
+	// SkeletonHitOrMiss repeats the hit-or-miss thinning with a series of structural elements, see Thinning.
+	SkeletonHitOrMiss SkeletonAlgorithm = "hmt"
+	// SkeletonZhangSuen removes border pixels in two alternating sub-iterations.
+	SkeletonZhangSuen SkeletonAlgorithm = "zhang-suen"
+	// SkeletonGuoHall is a parallel algorithm similar to Zhang-Suen that keeps diagonal lines thinner.
+	SkeletonGuoHall SkeletonAlgorithm = "guo-hall"
+	// SkeletonMedialAxis removes simple pixels in the order of their Euclidean distance to the background,
+	// which centers the skeleton and keeps the distance of every skeleton pixel.
+	SkeletonMedialAxis SkeletonAlgorithm = "medial-axis"
+)
+
+// ParseSkeletonAlgorithm parses the name of an algorithm, an empty string selects the hit-or-miss thinning.
+func ParseSkeletonAlgorithm(value string) (SkeletonAlgorithm, error) {
+	switch SkeletonAlgorithm(value) {
+	case "":
+		return SkeletonHitOrMiss, nil
+	case SkeletonHitOrMiss, SkeletonZhangSuen, SkeletonGuoHall, SkeletonMedialAxis:
+		return SkeletonAlgorithm(value), nil
+	default:
+		return "", fmt.Errorf("unknown skeleton algorithm %q, expected hmt, zhang-suen, guo-hall or medial-axis", value)
+	}
+}
+
+// SkeletonOptions configures Skeletonize.
+type SkeletonOptions struct {
+	Algorithm SkeletonAlgorithm
+	// Series and Border are used by the hit-or-miss thinning only.
+	Series []BinaryImage
+	Border manipulations.BorderPolicy
+	// MaxIterations stops the thinning early, 0 or less runs until no pixel changes.
+	MaxIterations int
+	// PruneLength removes spurs of at most this many pixels from the skeleton, 0 keeps them.
+	PruneLength int
+}
+
+// Skeleton is the result of Skeletonize.
+type Skeleton struct {
+	Image BinaryImage
+	// Iterations is the number of full passes over the image the thinning needed.
+	Iterations int
+	// Distance holds the distance to the background of every foreground pixel of the input,
+	// it is only set by the medial axis algorithm.
+	Distance [][]float64
+}
+
+// Skeletonize thins img with the selected algorithm and prunes the result.
+func Skeletonize(img BinaryImage, opts SkeletonOptions) (Skeleton, error) {
+	var skeleton Skeleton
+
+	switch opts.Algorithm {
+	case SkeletonHitOrMiss, "":
+		series := opts.Series
+		if series == nil {
+			series = SeriesXIISE
+		}
+		skeleton.Image, skeleton.Iterations = thinWithSeries(img, series, opts.Border, opts.MaxIterations)
+	case SkeletonZhangSuen:
+		skeleton.Image, skeleton.Iterations = ZhangSuen(img, opts.MaxIterations)
+	case SkeletonGuoHall:
+		skeleton.Image, skeleton.Iterations = GuoHall(img, opts.MaxIterations)
+	case SkeletonMedialAxis:
+		skeleton.Image, skeleton.Distance, skeleton.Iterations = MedialAxis(img, opts.MaxIterations)
+	default:
+		return Skeleton{}, fmt.Errorf("unknown skeleton algorithm %q", opts.Algorithm)
+	}
+
+	if opts.PruneLength > 0 {
+		skeleton.Image = Prune(skeleton.Image, opts.PruneLength)
+	}
+
+	return skeleton, nil
+}
+
+// neighbourhood holds the 8 neighbours of a pixel clockwise from the north one,
+// P2 to P9 in the notation of Zhang and Suen, pixels outside of the image are background.
+type neighbourhood [8]bool
+
+// neighbourOffsets lists the neighbours in the order of neighbourhood.
+var neighbourOffsets = [8]Point{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+func neighbourhoodAt(img BinaryImage, x, y int) neighbourhood {
+	var n neighbourhood
+	for i, d := range neighbourOffsets {
+		nx, ny := x+d.X, y+d.Y
+		n[i] = ny >= 0 && ny < len(img) && nx >= 0 && nx < len(img[ny]) && img[ny][nx] == 1
+	}
+	return n
+}
+
+// count is the number of foreground neighbours.
+func (n neighbourhood) count() int {
+	count := 0
+	for _, set := range n {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// transitions is the number of background to foreground changes going once around the pixel.
+func (n neighbourhood) transitions() int {
+	transitions := 0
+	for i := range n {
+		if !n[i] && n[(i+1)%8] {
+			transitions++
+		}
+	}
+	return transitions
+}
+
+// components is the number of 8-connected runs of foreground neighbours, the crossing number of Hilditch.
+// Removing a border pixel keeps the topology of the image when it is 1.
+func (n neighbourhood) components() int {
+	components := 0
+	for i := 0; i < 8; i += 2 {
+		if !n[i] && (n[i+1] || n[(i+2)%8]) {
+			components++
+		}
+	}
+	return components
+}
+
+func cloneBinaryImage(img BinaryImage) BinaryImage {
+	clone := make(BinaryImage, len(img))
+	for i := range img {
+		clone[i] = make([]int, len(img[i]))
+		copy(clone[i], img[i])
+	}
+	return clone
+}
+
+// thinInSubIterations runs parallel thinning, every iteration removes the pixels selected by each
+// of the sub-iterations in turn. It returns the result and the number of iterations that ran.
+func thinInSubIterations(img BinaryImage, maxIterations int, removable func(n neighbourhood, subIteration int) bool) (BinaryImage, int) {
+	result := cloneBinaryImage(img)
+	iterations := 0
+
+	for maxIterations <= 0 || iterations < maxIterations {
+		changed := false
+
+		for subIteration := 0; subIteration < 2; subIteration++ {
+			var removed []Point
+			for y := range result {
+				for x := range result[y] {
+					if result[y][x] == 1 && removable(neighbourhoodAt(result, x, y), subIteration) {
+						removed = append(removed, Point{X: x, Y: y})
+					}
+				}
+			}
+
+			for _, p := range removed {
+				result[p.Y][p.X] = 0
+			}
+			changed = changed || len(removed) > 0
+		}
+
+		if !changed {
+			break
+		}
+		iterations++
+	}
+
+	return result, iterations
+}
+
+func bit(set bool) int {
+	if set {
+		return 1
+	}
+	return 0
+}
+
+// ZhangSuen thins img to a one pixel wide, 8-connected skeleton.
+//
+// Reference: T. Y. Zhang, C. Y. Suen - A fast parallel algorithm for thinning digital patterns (1984)
+func ZhangSuen(img BinaryImage, maxIterations int) (BinaryImage, int) {
+	return thinInSubIterations(img, maxIterations, func(n neighbourhood, subIteration int) bool {
+		p2, p4, p6, p8 := n[0], n[2], n[4], n[6]
+
+		count := n.count()
+		if count < 2 || count > 6 || n.transitions() != 1 {
+			return false
+		}
+
+		if subIteration == 0 {
+			return !(p2 && p4 && p6) && !(p4 && p6 && p8)
+		}
+		return !(p2 && p4 && p8) && !(p2 && p6 && p8)
+	})
+}
+
+// GuoHall thins img to a one pixel wide, 8-connected skeleton.
+//
+// Reference: Z. Guo, R. W. Hall - Parallel thinning with two-subiteration algorithms (1989)
+func GuoHall(img BinaryImage, maxIterations int) (BinaryImage, int) {
+	return thinInSubIterations(img, maxIterations, func(n neighbourhood, subIteration int) bool {
+		p2, p3, p4, p5, p6, p7, p8, p9 := n[0], n[1], n[2], n[3], n[4], n[5], n[6], n[7]
+
+		n1 := bit(p9 || p2) + bit(p3 || p4) + bit(p5 || p6) + bit(p7 || p8)
+		n2 := bit(p2 || p3) + bit(p4 || p5) + bit(p6 || p7) + bit(p8 || p9)
+		neighbours := min(n1, n2)
+
+		var m bool
+		if subIteration == 0 {
+			m = (p6 || p7 || !p9) && p8
+		} else {
+			m = (p2 || p3 || !p5) && p4
+		}
+
+		return n.components() == 1 && neighbours >= 2 && neighbours <= 3 && !m
+	})
+}
+
+// MedialAxis thins img by removing simple pixels in the order of their distance to the background,
+// end points are kept so that every branch reaches as far as the shape does. The returned distance
+// of a skeleton pixel is the radius of the largest disc centered on it that fits into the shape.
+func MedialAxis(img BinaryImage, maxIterations int) (BinaryImage, [][]float64, int) {
+	distance := distanceToFrame(img)
+	result := cloneBinaryImage(img)
+
+	var pixels []Point
+	for y := range img {
+		for x := range img[y] {
+			if img[y][x] == 1 {
+				pixels = append(pixels, Point{X: x, Y: y})
+			}
+		}
+	}
+	sort.SliceStable(pixels, func(i, j int) bool {
+		return distance[pixels[i].Y][pixels[i].X] < distance[pixels[j].Y][pixels[j].X]
+	})
+
+	iterations := 0
+	for maxIterations <= 0 || iterations < maxIterations {
+		changed := false
+		remaining := pixels[:0]
+
+		for _, p := range pixels {
+			n := neighbourhoodAt(result, p.X, p.Y)
+			// a pixel is simple when it touches a single 8-connected run of neighbours and a 4-neighbour of the background
+			simple := n.components() == 1 && (!n[0] || !n[2] || !n[4] || !n[6])
+			if simple && n.count() > 1 {
+				result[p.Y][p.X] = 0
+				changed = true
+				continue
+			}
+			remaining = append(remaining, p)
+		}
+
+		pixels = remaining
+		if !changed {
+			break
+		}
+		iterations++
+	}
+
+	return result, distance, iterations
+}
+
+// SkeletonPoints finds the end points, with a single neighbour, and the branch points, where at least
+// three branches meet, of a one pixel wide skeleton.
+func SkeletonPoints(skeleton BinaryImage) (endpoints, branchPoints []Point) {
+	for y := range skeleton {
+		for x := range skeleton[y] {
+			if skeleton[y][x] != 1 {
+				continue
+			}
+
+			n := neighbourhoodAt(skeleton, x, y)
+			switch {
+			case n.count() == 1:
+				endpoints = append(endpoints, Point{X: x, Y: y})
+			case n.transitions() >= 3:
+				branchPoints = append(branchPoints, Point{X: x, Y: y})
+			}
+		}
+	}
+	return endpoints, branchPoints
+}
+
+// Prune removes the spurs of the skeleton, branches of at most length pixels leading from an end point
+// to a branch point. Lines that do not reach a branch point are kept whatever their length.
+func Prune(skeleton BinaryImage, length int) BinaryImage {
+	result := cloneBinaryImage(skeleton)
+	endpoints, _ := SkeletonPoints(skeleton)
+
+	for _, start := range endpoints {
+		path := []Point{start}
+		visited := map[Point]bool{start: true}
+		current := start
+		isSpur := false
+
+		for len(path) <= length {
+			var next []Point
+			for i, d := range neighbourOffsets {
+				p := Point{X: current.X + d.X, Y: current.Y + d.Y}
+				if p.Y < 0 || p.Y >= len(result) || p.X < 0 || p.X >= len(result[p.Y]) || result[p.Y][p.X] != 1 || visited[p] {
+					continue
+				}
+				// prefer the edge neighbour so that staircases are followed one pixel at a time
+				if i%2 == 0 {
+					next = append([]Point{p}, next...)
+				} else {
+					next = append(next, p)
+				}
+			}
+
+			if len(next) == 0 {
+				break
+			}
+
+			candidate := next[0]
+			if neighbourhoodAt(result, candidate.X, candidate.Y).transitions() >= 3 {
+				isSpur = true
+				break
+			}
+
+			visited[candidate] = true
+			path = append(path, candidate)
+			current = candidate
+		}
+
+		if isSpur {
+			for _, p := range path {
+				result[p.Y][p.X] = 0
+			}
+		}
+	}
+
+	return result
+}
+
+// SkeletonPointsReport lists the end and branch points of a skeleton.
+type SkeletonPointsReport struct {
+	Endpoints    []Point `json:"endpoints"`
+	BranchPoints []Point `json:"branch_points"`
+}
+
+// WriteSkeletonPointsJSON writes the end and branch points as an indented JSON document.
+func WriteSkeletonPointsJSON(w io.Writer, endpoints, branchPoints []Point) error {
+	report := SkeletonPointsReport{Endpoints: endpoints, BranchPoints: branchPoints}
+	if report.Endpoints == nil {
+		report.Endpoints = []Point{}
+	}
+	if report.BranchPoints == nil {
+		report.BranchPoints = []Point{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(report)
+}
+
+var (
+	overlayEndpointColor = color.RGBA{64, 220, 64, 255}
+	overlayBranchColor   = color.RGBA{255, 64, 64, 255}
+)
+
+// DrawSkeletonOverlay draws the skeleton over the dimmed shape, end points are marked green and branch points red.
+func DrawSkeletonOverlay(img, skeleton BinaryImage, endpoints, branchPoints []Point) *image.RGBA {
+	overlay := ConvertIntoImage(img)
+	for i := 0; i < len(overlay.Pix); i += 4 {
+		if overlay.Pix[i] == 255 {
+			overlay.Pix[i], overlay.Pix[i+1], overlay.Pix[i+2] = 70, 70, 70
+		}
+	}
+
+	for y := range skeleton {
+		for x := range skeleton[y] {
+			if skeleton[y][x] == 1 {
+				overlay.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			}
+		}
+	}
+
+	for _, p := range endpoints {
+		manipulations.DrawRectangle(overlay, image.Rect(p.X-2, p.Y-2, p.X+3, p.Y+3), overlayEndpointColor)
+	}
+	for _, p := range branchPoints {
+		manipulations.DrawRectangle(overlay, image.Rect(p.X-2, p.Y-2, p.X+3, p.Y+3), overlayBranchColor)
+	}
+
+	return overlay
+}
+
+// MedialAxisImage renders the medial axis transform, every skeleton pixel is as bright as its distance
+// to the background relative to the largest distance on the skeleton.
+func MedialAxisImage(skeleton BinaryImage, distance [][]float64) *image.RGBA {
+	peak := 0.0
+	for y := range skeleton {
+		for x := range skeleton[y] {
+			if skeleton[y][x] == 1 {
+				peak = max(peak, distance[y][x])
+			}
+		}
+	}
+
+	img := ConvertIntoImage(skeleton)
+	if peak == 0 {
+		return img
+	}
+
+	for y := range skeleton {
+		for x := range skeleton[y] {
+			if skeleton[y][x] == 1 {
+				// keep the thinnest parts visible on the black background
+				level := uint8(55 + 200*distance[y][x]/peak)
+				img.SetRGBA(x, y, color.RGBA{level, level, level, 255})
+			}
+		}
+	}
+
+	return img
+}
@@ -0,0 +1,116 @@
+package morphological
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func foregroundCount(img BinaryImage) int {
+	count := 0
+	for _, row := range img {
+		for _, value := range row {
+			count += value
+		}
+	}
+	return count
+}
+
+func TestThinningAlgorithmsProduceThinLine(t *testing.T) {
+	img := newBinaryImage(20, 9)
+	fillRect(img, 2, 2, 16, 5)
+
+	for _, algorithm := range []SkeletonAlgorithm{SkeletonZhangSuen, SkeletonGuoHall, SkeletonMedialAxis} {
+		skeleton, err := Skeletonize(img, SkeletonOptions{Algorithm: algorithm})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := len(LabelComponents(skeleton.Image, Connectivity8).Components); got != 1 {
+			t.Errorf("%s: skeleton has %d components, want 1", algorithm, got)
+		}
+
+		// away from the ends the skeleton of a bar is a single pixel per column
+		for x := 6; x < 14; x++ {
+			column := 0
+			for y := range skeleton.Image {
+				column += skeleton.Image[y][x]
+			}
+			if column != 1 {
+				t.Errorf("%s: column %d holds %d skeleton pixels, want 1", algorithm, x, column)
+			}
+		}
+
+		if skeleton.Iterations == 0 {
+			t.Errorf("%s: expected at least one iteration", algorithm)
+		}
+	}
+}
+
+func TestThinningMaxIterations(t *testing.T) {
+	img := newBinaryImage(20, 11)
+	fillRect(img, 1, 1, 18, 9)
+
+	full, iterations := ZhangSuen(img, 0)
+	limited, limitedIterations := ZhangSuen(img, 1)
+
+	if limitedIterations != 1 || iterations <= 1 {
+		t.Fatalf("iterations = %d and %d, want 1 and more than 1", limitedIterations, iterations)
+	}
+	if foregroundCount(limited) <= foregroundCount(full) {
+		t.Errorf("a single iteration left %d pixels, the full thinning %d", foregroundCount(limited), foregroundCount(full))
+	}
+	if foregroundCount(img) != 18*9 {
+		t.Error("ZhangSuen() modified its input")
+	}
+}
+
+func TestSkeletonPointsAndPrune(t *testing.T) {
+	skeleton := binaryFromRows(
+		"...............",
+		".#############.",
+		".........#.....",
+		".........#.....",
+		".........#.....",
+		".........#.....",
+		"...............",
+	)
+	// a spur of two pixels hanging off the horizontal line
+	skeleton[2][5] = 1
+	skeleton[3][5] = 1
+
+	endpoints, branchPoints := SkeletonPoints(skeleton)
+	if len(endpoints) != 4 || len(branchPoints) != 2 {
+		t.Fatalf("found %d end points and %d branch points, want 4 and 2", len(endpoints), len(branchPoints))
+	}
+
+	pruned := Prune(skeleton, 2)
+	if pruned[2][5] != 0 || pruned[3][5] != 0 {
+		t.Error("Prune() kept the short spur")
+	}
+	if foregroundCount(pruned) != foregroundCount(skeleton)-2 {
+		t.Errorf("Prune() removed %d pixels, want 2", foregroundCount(skeleton)-foregroundCount(pruned))
+	}
+
+	var data bytes.Buffer
+	if err := WriteSkeletonPointsJSON(&data, endpoints, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(data.String(), `"branch_points": []`) || !strings.Contains(data.String(), `"x": 1`) {
+		t.Errorf("unexpected JSON:\n%s", data.String())
+	}
+}
+
+func TestMedialAxisDistance(t *testing.T) {
+	img := newBinaryImage(15, 9)
+	fillRect(img, 2, 2, 11, 5)
+
+	_, distance, _ := MedialAxis(img, 0)
+
+	if distance[4][7] != 3 {
+		t.Errorf("distance at the center = %v, want 3", distance[4][7])
+	}
+	if distance[0][0] != 0 || distance[2][2] != 1 {
+		t.Errorf("distances at the background and the corner = %v and %v, want 0 and 1", distance[0][0], distance[2][2])
+	}
+}
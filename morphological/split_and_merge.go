@@ -0,0 +1,118 @@
+package morphological
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// HomogeneityPredicate decides whether a block or region is uniform enough to be kept whole by SplitAndMerge.
+type HomogeneityPredicate string
+
+const (
+	// HomogeneityDeviation accepts regions whose standard deviation, measured with the distance criterion, is at most the threshold.
+	HomogeneityDeviation HomogeneityPredicate = "deviation"
+	// HomogeneityRange accepts regions whose lowest and highest values are at most the threshold apart.
+	HomogeneityRange HomogeneityPredicate = "range"
+)
+
+// ParseHomogeneityPredicate parses deviation or range, an empty string selects deviation.
+func ParseHomogeneityPredicate(value string) (HomogeneityPredicate, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", string(HomogeneityDeviation):
+		return HomogeneityDeviation, nil
+	case string(HomogeneityRange):
+		return HomogeneityRange, nil
+	default:
+		return "", fmt.Errorf("unknown homogeneity predicate %q, expected deviation or range", value)
+	}
+}
+
+func (p HomogeneityPredicate) holds(s *regionStatistics, criterion DistanceCriterion, threshold float64) bool {
+	if p == HomogeneityRange {
+		return calculateDistance(criterion, s.maximum, s.minimum) <= threshold
+	}
+	deviation := s.deviation()
+	return calculateDistance(criterion, deviation, make([]float64, len(deviation))) <= threshold
+}
+
+// SplitMergeOptions configures SplitAndMerge.
+type SplitMergeOptions struct {
+	Criterion DistanceCriterion
+	Predicate HomogeneityPredicate
+	Threshold float64
+	// MinSize is the block side below which blocks are no longer split, at least 1.
+	MinSize int
+	// MinArea merges every smaller region into its most similar neighbour at the end, 0 disables it.
+	MinArea      int
+	Connectivity Connectivity
+}
+
+// SplitAndMerge splits the image into quadrants, and those into quadrants again, until every block satisfies the
+// homogeneity predicate or is no larger than MinSize, and then merges adjacent regions as long as their union
+// still satisfies the predicate, the pair with the closest means first. It returns the region labels as
+// [row][col] and the regions drawn in random colors.
+//
+// Reference: S. Horowitz, T. Pavlidis - Picture segmentation by a tree traversal algorithm (1976)
+func SplitAndMerge(img image.Image, opts SplitMergeOptions) ([][]int, *image.RGBA, error) {
+	if opts.Threshold < 0 {
+		return nil, nil, fmt.Errorf("threshold must not be negative, got %v", opts.Threshold)
+	}
+	if opts.MinSize < 1 {
+		return nil, nil, fmt.Errorf("minimum block size must be positive, got %d", opts.MinSize)
+	}
+	if _, err := ParseHomogeneityPredicate(string(opts.Predicate)); err != nil {
+		return nil, nil, err
+	}
+
+	bounds := img.Bounds()
+	values := pixelValues(img)
+	labels := make([][]int, bounds.Dy())
+	for y := range labels {
+		labels[y] = make([]int, bounds.Dx())
+	}
+
+	blocks := 0
+	var split func(x, y, width, height int)
+	split = func(x, y, width, height int) {
+		if width == 0 || height == 0 {
+			return
+		}
+
+		statistics := newRegionStatistics(len(values[y][x]))
+		for row := y; row < y+height; row++ {
+			for col := x; col < x+width; col++ {
+				statistics.add(values[row][col])
+			}
+		}
+
+		if max(width, height) <= opts.MinSize || opts.Predicate.holds(statistics, opts.Criterion, opts.Threshold) {
+			for row := y; row < y+height; row++ {
+				for col := x; col < x+width; col++ {
+					labels[row][col] = blocks
+				}
+			}
+			blocks++
+			return
+		}
+
+		// a side of 1 is not split, the missing quadrants are empty
+		halfWidth, halfHeight := (width+1)/2, (height+1)/2
+		split(x, y, halfWidth, halfHeight)
+		split(x+halfWidth, y, width-halfWidth, halfHeight)
+		split(x, y+halfHeight, halfWidth, height-halfHeight)
+		split(x+halfWidth, y+halfHeight, width-halfWidth, height-halfHeight)
+	}
+	split(0, 0, bounds.Dx(), bounds.Dy())
+
+	graph, err := BuildRegionAdjacencyGraph(img, labels, opts.Connectivity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := graph.merge(opts.Criterion, func(a, b *regionStatistics) bool {
+		return opts.Predicate.holds(a.join(b), opts.Criterion, opts.Threshold)
+	}, opts.MinArea)
+
+	return merged, drawRegions(bounds, merged), nil
+}
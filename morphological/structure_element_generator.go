@@ -0,0 +1,227 @@
+package morphological
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NewDisk returns a flat disk of the given radius centered on the origin.
+func NewDisk(radius int) (StructuringElement, error) {
+	if radius < 0 {
+		return StructuringElement{}, fmt.Errorf("disk radius must not be negative, got %d", radius)
+	}
+
+	return newCenteredElement(radius, func(i, j int) bool {
+		return i*i+j*j <= radius*radius
+	}), nil
+}
+
+// NewDiamond returns a flat diamond, every pixel within the given city-block distance of the origin.
+func NewDiamond(radius int) (StructuringElement, error) {
+	if radius < 0 {
+		return StructuringElement{}, fmt.Errorf("diamond radius must not be negative, got %d", radius)
+	}
+
+	return newCenteredElement(radius, func(i, j int) bool {
+		return abs(i)+abs(j) <= radius
+	}), nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// newCenteredElement builds a square element of side 2*radius+1 holding the offsets accepted by inside.
+func newCenteredElement(radius int, inside func(i, j int) bool) StructuringElement {
+	size := 2*radius + 1
+	se := StructuringElement{Data: make([][]int, size), OriginX: radius, OriginY: radius}
+	for i := range se.Data {
+		se.Data[i] = make([]int, size)
+		for j := range se.Data[i] {
+			if inside(i-radius, j-radius) {
+				se.Data[i][j] = 1
+			}
+		}
+	}
+	return se
+}
+
+// NewRectangle returns a flat rectangle of the given width and height with the origin in its center.
+func NewRectangle(width, height int) (StructuringElement, error) {
+	if width < 1 || height < 1 {
+		return StructuringElement{}, fmt.Errorf("rectangle sides must be positive, got %dx%d", width, height)
+	}
+
+	se := StructuringElement{Data: make([][]int, height), OriginX: (height - 1) / 2, OriginY: (width - 1) / 2}
+	for i := range se.Data {
+		se.Data[i] = make([]int, width)
+		for j := range se.Data[i] {
+			se.Data[i][j] = 1
+		}
+	}
+	return se, nil
+}
+
+// NewLine returns a flat line segment of the given length in pixels through the origin,
+// the angle is in degrees counter-clockwise from the x-axis as seen on screen. Even lengths have
+// one pixel more behind the origin than ahead of it.
+func NewLine(length int, angle float64) (StructuringElement, error) {
+	if length < 1 {
+		return StructuringElement{}, fmt.Errorf("line length must be positive, got %d", length)
+	}
+
+	radians := angle * math.Pi / 180
+	cos, sin := math.Cos(radians), math.Sin(radians)
+	// walk along the dominant axis so that neighbouring samples never skip a pixel
+	step := 1 / math.Max(math.Abs(cos), math.Abs(sin))
+
+	var points []Point
+	minX, minY, maxX, maxY := 0, 0, 0, 0
+	for k := -(length / 2); k <= length-1-length/2; k++ {
+		t := float64(k) * step
+		p := Point{X: int(math.Round(t * cos)), Y: int(math.Round(-t * sin))}
+		points = append(points, p)
+		minX, maxX = min(minX, p.X), max(maxX, p.X)
+		minY, maxY = min(minY, p.Y), max(maxY, p.Y)
+	}
+
+	se := StructuringElement{Data: make([][]int, maxY-minY+1), OriginX: -minY, OriginY: -minX}
+	for i := range se.Data {
+		se.Data[i] = make([]int, maxX-minX+1)
+	}
+	for _, p := range points {
+		se.Data[p.Y-minY][p.X-minX] = 1
+	}
+	return se, nil
+}
+
+// ParseStructureElement builds an element from a shape specification:
+// disk:<radius>, diamond:<radius>, rect:<width>x<height> or line:<length>:<angle>.
+func ParseStructureElement(spec string) (StructuringElement, error) {
+	shape, params, _ := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), ":")
+	parts := strings.Split(params, ":")
+
+	atoi := func(value string) (int, error) {
+		number, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return 0, fmt.Errorf("invalid number %q in structure element %q", value, spec)
+		}
+		return number, nil
+	}
+
+	switch shape {
+	case "disk", "diamond":
+		if len(parts) != 1 {
+			return StructuringElement{}, fmt.Errorf("expected %s:<radius>, got %q", shape, spec)
+		}
+		radius, err := atoi(parts[0])
+		if err != nil {
+			return StructuringElement{}, err
+		}
+		if shape == "disk" {
+			return NewDisk(radius)
+		}
+		return NewDiamond(radius)
+
+	case "rect":
+		width, height, found := strings.Cut(params, "x")
+		if !found || len(parts) != 1 {
+			return StructuringElement{}, fmt.Errorf("expected rect:<width>x<height>, got %q", spec)
+		}
+		w, err := atoi(width)
+		if err != nil {
+			return StructuringElement{}, err
+		}
+		h, err := atoi(height)
+		if err != nil {
+			return StructuringElement{}, err
+		}
+		return NewRectangle(w, h)
+
+	case "line":
+		if len(parts) != 2 {
+			return StructuringElement{}, fmt.Errorf("expected line:<length>:<angle>, got %q", spec)
+		}
+		length, err := atoi(parts[0])
+		if err != nil {
+			return StructuringElement{}, err
+		}
+		angle, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			return StructuringElement{}, fmt.Errorf("invalid angle %q in structure element %q", parts[1], spec)
+		}
+		return NewLine(length, angle)
+
+	default:
+		return StructuringElement{}, fmt.Errorf("unknown structure element shape %q, expected disk, diamond, rect or line", shape)
+	}
+}
+
+// IsStructureElementSpec reports whether name is a shape specification rather than the name of a stored element.
+func IsStructureElementSpec(name string) bool {
+	shape, _, found := strings.Cut(strings.ToLower(strings.TrimSpace(name)), ":")
+	if !found {
+		return false
+	}
+	switch shape {
+	case "disk", "diamond", "rect", "line":
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks that the element is a non-empty rectangle of zeros and ones with the origin inside of it
+// and, when present, heights of the same size.
+func (se StructuringElement) Validate() error {
+	if len(se.Data) == 0 || len(se.Data[0]) == 0 {
+		return errors.New("structure element has no data")
+	}
+
+	cols := len(se.Data[0])
+	for _, row := range se.Data {
+		if len(row) != cols {
+			return errors.New("structure element rows must have the same length")
+		}
+		for _, value := range row {
+			if value != 0 && value != 1 {
+				return fmt.Errorf("structure element values must be 0 or 1, got %d", value)
+			}
+		}
+	}
+
+	if se.OriginX < 0 || se.OriginX >= len(se.Data) || se.OriginY < 0 || se.OriginY >= cols {
+		return fmt.Errorf("structure element origin (%d, %d) lies outside of its %dx%d data", se.OriginX, se.OriginY, len(se.Data), cols)
+	}
+
+	if se.Heights != nil {
+		if len(se.Heights) != len(se.Data) {
+			return errors.New("structure element heights must have the same size as its data")
+		}
+		for _, row := range se.Heights {
+			if len(row) != cols {
+				return errors.New("structure element heights must have the same size as its data")
+			}
+		}
+	}
+
+	return nil
+}
+
+// StructureElementLabel turns an element name, specification, inline matrix or file path into a string usable in file names.
+func StructureElementLabel(name string) string {
+	if IsInlineStructureElement(name) {
+		return "inline"
+	}
+	if isStructureElementFile(name) {
+		return strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+	return strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(name)
+}
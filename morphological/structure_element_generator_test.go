@@ -0,0 +1,176 @@
+package morphological
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func elementRows(se StructuringElement) []string {
+	rows := make([]string, len(se.Data))
+	for i, row := range se.Data {
+		for j, value := range row {
+			switch {
+			case i == se.OriginX && j == se.OriginY:
+				rows[i] += "o"
+			case value == 1:
+				rows[i] += "#"
+			default:
+				rows[i] += "."
+			}
+		}
+	}
+	return rows
+}
+
+func TestParseStructureElement(t *testing.T) {
+	cases := map[string][]string{
+		"disk:2": {
+			"..#..",
+			".###.",
+			"##o##",
+			".###.",
+			"..#..",
+		},
+		"diamond:1": {
+			".#.",
+			"#o#",
+			".#.",
+		},
+		"rect:3x2": {
+			"#o#",
+			"###",
+		},
+		"line:5:0": {
+			"##o##",
+		},
+		"line:3:90": {
+			"#",
+			"o",
+			"#",
+		},
+		"line:3:45": {
+			"..#",
+			".o.",
+			"#..",
+		},
+		"line:5:135": {
+			"#....",
+			".#...",
+			"..o..",
+			"...#.",
+			"....#",
+		},
+	}
+
+	for spec, want := range cases {
+		se, err := ParseStructureElement(spec)
+		if err != nil {
+			t.Fatalf("ParseStructureElement(%q): %v", spec, err)
+		}
+		if err := se.Validate(); err != nil {
+			t.Errorf("ParseStructureElement(%q) is invalid: %v", spec, err)
+		}
+
+		got := elementRows(se)
+		if len(got) != len(want) {
+			t.Fatalf("ParseStructureElement(%q) = %v, want %v", spec, got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("ParseStructureElement(%q) = %v, want %v", spec, got, want)
+			}
+		}
+	}
+}
+
+func TestParseStructureElementErrors(t *testing.T) {
+	for _, spec := range []string{"disk", "disk:-1", "disk:a", "rect:3", "rect:0x2", "line:5", "line:0:45", "line:5:up", "star:3"} {
+		if _, err := ParseStructureElement(spec); err == nil {
+			t.Errorf("ParseStructureElement(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestLineLengthAtAnyAngle(t *testing.T) {
+	for angle := 0.0; angle < 180; angle += 15 {
+		se, err := NewLine(9, angle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := foregroundCount(se.Data); got != 9 {
+			t.Errorf("line at %v degrees holds %d pixels, want 9", angle, got)
+		}
+		if len(LabelComponents(se.Data, Connectivity8).Components) != 1 {
+			t.Errorf("line at %v degrees is not connected", angle)
+		}
+	}
+}
+
+func TestLineEvenLengths(t *testing.T) {
+	for _, length := range []int{2, 4, 6} {
+		for _, angle := range []float64{0, 45, 90} {
+			se, err := NewLine(length, angle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := foregroundCount(se.Data); got != length {
+				t.Errorf("line:%d:%v holds %d pixels, want %d", length, angle, got, length)
+			}
+			if len(LabelComponents(se.Data, Connectivity8).Components) != 1 {
+				t.Errorf("line:%d:%v is not contiguous: %v", length, angle, se.Data)
+			}
+			if se.Data[se.OriginX][se.OriginY] != 1 {
+				t.Errorf("line:%d:%v does not hold its origin", length, angle)
+			}
+		}
+	}
+
+	se, _ := NewLine(4, 0)
+	if want := [][]int{{1, 1, 1, 1}}; !reflect.DeepEqual(se.Data, want) || se.OriginY != 2 {
+		t.Errorf("line:4:0 = %v with origin column %d, want %v with origin column 2", se.Data, se.OriginY, want)
+	}
+}
+
+func TestGetStructureElementAcceptsSpecs(t *testing.T) {
+	se, err := GetStructureElement("rect:5x3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(se.Data) != 3 || len(se.Data[0]) != 5 {
+		t.Errorf("rect:5x3 is %dx%d, want 3 rows of 5", len(se.Data), len(se.Data[0]))
+	}
+}
+
+func TestLoadStructureElementsFile(t *testing.T) {
+	defer ReloadStructureElements()
+
+	path := filepath.Join(t.TempDir(), "elements.json")
+	content := `{"cross": {"data": [[0,1,0],[1,1,1],[0,1,0]], "originX": 1, "originY": 1}}`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadStructureElementsFile(path); err != nil {
+		t.Fatal(err)
+	}
+	se, err := GetStructureElement("cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if se.Data[0][1] != 1 || se.Data[0][0] != 0 {
+		t.Errorf("unexpected element %v", se.Data)
+	}
+	if _, err := GetStructureElement("iv"); err != nil {
+		t.Errorf("embedded elements are no longer available: %v", err)
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(invalid, []byte(`{"bad": {"data": [[1,1],[1]], "originX": 0, "originY": 0}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadStructureElementsFile(invalid); err == nil {
+		t.Error("expected an error for an element with ragged rows")
+	}
+}
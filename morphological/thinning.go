@@ -1,5 +1,7 @@
 package morphological
 
+import "imagio/manipulations"
+
 var SeriesXIISE = []BinaryImage{
 	{
 		{1, 1, 1},
@@ -22,9 +24,9 @@ var SeriesXIISE = []BinaryImage{
 		{-1, 0, 0},
 	},
 	{
-		{1, 1, 1},
-		{-1, 1, -1},
 		{0, 0, 0},
+		{-1, 1, -1},
+		{1, 1, 1},
 	},
 	{
 		{-1, 1, 1},
@@ -66,14 +68,31 @@ var SeriesXISE = []BinaryImage{
 	},
 }
 
-func matchesStructuralElement(img BinaryImage, x, y int, se BinaryImage) bool {
+// binaryPixelAt returns the value of the binary image at (x, y) resolving coordinates outside of the image with the border policy.
+// For BorderConstant any non-zero padding value is treated as foreground, BorderSkip reads out-of-range pixels as background.
+func binaryPixelAt(img BinaryImage, x, y int, border manipulations.BorderPolicy) int {
+	ry, okY := border.ResolveCoordinate(y, 0, len(img))
+	rx, okX := border.ResolveCoordinate(x, 0, len(img[0]))
+
+	if okX && okY {
+		return img[ry][rx]
+	}
+
+	if border.Mode == manipulations.BorderConstant && border.Value > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+func matchesStructuralElement(img BinaryImage, x, y int, se BinaryImage, border manipulations.BorderPolicy) bool {
 	for dy := -1; dy <= 1; dy++ {
 		for dx := -1; dx <= 1; dx++ {
 			seVal := se[dy+1][dx+1]
 			if seVal == -1 {
 				continue // Don't care condition
 			}
-			if img[y+dy][x+dx] != seVal {
+			if binaryPixelAt(img, x+dx, y+dy, border) != seVal {
 				return false
 			}
 		}
@@ -83,10 +102,25 @@ func matchesStructuralElement(img BinaryImage, x, y int, se BinaryImage) bool {
 
 // Reference: https://www.ee.nthu.edu.tw/clhuang/09420EE368000DIP/chapter09.pdf
 // Reference: https://homepages.inf.ed.ac.uk/rbf/HIPR2/thin.htm
-func Thinning(image BinaryImage, structElems []BinaryImage) BinaryImage {
+//
+// With BorderSkip the outermost rows and columns are never thinned, other border policies
+// pad the image so that the structural elements are matched against every pixel.
+func Thinning(image BinaryImage, structElems []BinaryImage, border manipulations.BorderPolicy) BinaryImage {
+	thinned, _ := thinWithSeries(image, structElems, border, 0)
+	return thinned
+}
+
+// thinWithSeries applies every structural element of the series in turn until no pixel changes or
+// maxIterations passes over the series ran, 0 or less is unlimited. It returns the number of passes.
+func thinWithSeries(image BinaryImage, structElems []BinaryImage, border manipulations.BorderPolicy, maxIterations int) (BinaryImage, int) {
 	height := len(image)
 	width := len(image[0])
 
+	margin := 1
+	if border.HandlesFullWindow() {
+		margin = 0
+	}
+
 	// Helper function to apply a thinning operation
 	applyThinning := func(img BinaryImage, se BinaryImage) (BinaryImage, bool) {
 		changed := false
@@ -96,9 +130,9 @@ func Thinning(image BinaryImage, structElems []BinaryImage) BinaryImage {
 			copy(result[i], img[i])
 		}
 
-		for y := 1; y < height-1; y++ {
-			for x := 1; x < width-1; x++ {
-				if img[y][x] == 1 && matchesStructuralElement(img, x, y, se) {
+		for y := margin; y < height-margin; y++ {
+			for x := margin; x < width-margin; x++ {
+				if img[y][x] == 1 && matchesStructuralElement(img, x, y, se, border) {
 					result[y][x] = 0
 					changed = true
 				}
@@ -108,15 +142,166 @@ func Thinning(image BinaryImage, structElems []BinaryImage) BinaryImage {
 		return result, changed
 	}
 
+	iterations := 0
 	changed := true
-	for changed {
+	for changed && (maxIterations <= 0 || iterations < maxIterations) {
 		changed = false
 		for _, se := range structElems {
 			var seChanged bool
 			image, seChanged = applyThinning(image, se)
 			changed = changed || seChanged
 		}
+		if changed {
+			iterations++
+		}
+	}
+
+	return image, iterations
+}
+
+// SeriesPruningSE matches the end points of thin lines, the first four elements in steps of 90 degrees
+// and the last four for lines ending diagonally.
+var SeriesPruningSE = []BinaryImage{
+	{
+		{-1, 0, 0},
+		{1, 1, 0},
+		{-1, 0, 0},
+	},
+	{
+		{-1, 1, -1},
+		{0, 1, 0},
+		{0, 0, 0},
+	},
+	{
+		{0, 0, -1},
+		{0, 1, 1},
+		{0, 0, -1},
+	},
+	{
+		{0, 0, 0},
+		{0, 1, 0},
+		{-1, 1, -1},
+	},
+	{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	},
+	{
+		{0, 0, 1},
+		{0, 1, 0},
+		{0, 0, 0},
+	},
+	{
+		{0, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	},
+	{
+		{0, 0, 0},
+		{0, 1, 0},
+		{1, 0, 0},
+	},
+}
+
+// SeriesThickeningSE is SeriesXIISE with foreground and background swapped, thickening with it adds
+// the background pixels the thinning series would remove from the background.
+var SeriesThickeningSE = swapSeries(SeriesXIISE)
+
+func swapSeries(series []BinaryImage) []BinaryImage {
+	swapped := make([]BinaryImage, len(series))
+	for i, se := range series {
+		swapped[i] = make(BinaryImage, len(se))
+		for y, row := range se {
+			swapped[i][y] = make([]int, len(row))
+			for x, value := range row {
+				switch value {
+				case 0:
+					swapped[i][y][x] = 1
+				case 1:
+					swapped[i][y][x] = 0
+				default:
+					swapped[i][y][x] = value
+				}
+			}
+		}
+	}
+	return swapped
+}
+
+// seriesPairs splits every element of a series, 1 has to be foreground, 0 background and -1 does not matter.
+func seriesPairs(series []BinaryImage) []HitOrMissPair {
+	pairs := make([]HitOrMissPair, len(series))
+	for i, se := range series {
+		pairs[i] = HitOrMissPair{Foreground: elementFromMatrix(se, 1), Background: elementFromMatrix(se, 0)}
+	}
+	return pairs
+}
+
+// applySeries matches every pair in turn and removes (thinning) or adds (thickening) the matched pixels, until
+// no pixel changes or maxIterations passes over the series ran, 0 or less is unlimited. It returns the number of passes.
+// Unlike thinWithSeries the elements never match where they reach outside of the image.
+func applySeries(img *PackedImage, pairs []HitOrMissPair, thicken bool, maxIterations int) (*PackedImage, int) {
+	iterations := 0
+	changed := true
+	for changed && (maxIterations <= 0 || iterations < maxIterations) {
+		changed = false
+		for _, pair := range pairs {
+			matched := img.HitOrMiss(pair.Foreground, pair.Background, manipulations.DefaultBorderPolicy)
+			if matched.Count() == 0 {
+				continue
+			}
+			changed = true
+			if thicken {
+				img = img.Union(matched)
+			} else {
+				img = img.Difference(matched)
+			}
+		}
+		if changed {
+			iterations++
+		}
+	}
+	return img, iterations
+}
+
+// Thickening adds the background pixels matched by the elements of the series, SeriesThickeningSE when nil, until
+// no pixel changes or maxIterations passes over the series ran, 0 or less is unlimited. It returns the number of passes.
+//
+// Reference: R. Gonzalez, R. Woods - Digital Image Processing, 9.5.6 Thickening
+func Thickening(img BinaryImage, series []BinaryImage, maxIterations int) (BinaryImage, int) {
+	if series == nil {
+		series = SeriesThickeningSE
+	}
+	thickened, iterations := applySeries(Pack(img), seriesPairs(series), true, maxIterations)
+	return thickened.Unpack(), iterations
+}
+
+// PruneHitOrMiss removes the spurs of at most length pixels from a thin image: the end points are thinned away
+// length times with SeriesPruningSE, then the end points left are grown back length times along the original
+// lines so that the main branches get their ends back.
+//
+// Reference: R. Gonzalez, R. Woods - Digital Image Processing, 9.5.8 Pruning
+func PruneHitOrMiss(img BinaryImage, length int) BinaryImage {
+	if length <= 0 {
+		return cloneBinaryImage(img)
+	}
+
+	original := Pack(img)
+	pairs := seriesPairs(SeriesPruningSE)
+
+	thinned, _ := applySeries(original, pairs, false, length)
+
+	endpoints := NewPackedImage(original.Width(), original.Height())
+	for _, pair := range pairs {
+		endpoints = endpoints.Union(thinned.HitOrMiss(pair.Foreground, pair.Background, manipulations.DefaultBorderPolicy))
+	}
+
+	square, _ := NewRectangle(3, 3)
+	grown := endpoints
+	for i := 0; i < length; i++ {
+		grown = grown.Dilate(square, manipulations.DefaultBorderPolicy).Intersection(original)
 	}
 
-	return image
+	return thinned.Union(grown).Unpack()
 }
@@ -0,0 +1,76 @@
+package morphological
+
+import (
+	"testing"
+
+	"imagio/manipulations"
+)
+
+func TestThinningSeriesXIIThinsFromBothSides(t *testing.T) {
+	img := binaryFromRows(
+		"............",
+		".##########.",
+		".##########.",
+		".##########.",
+		"............",
+	)
+
+	// the bottom edge element used to repeat the top edge one, which ate the bar from one side only
+	thinned := Thinning(img, SeriesXIISE, manipulations.DefaultBorderPolicy)
+	for x := 2; x < 10; x++ {
+		if thinned[2][x] != 1 || thinned[1][x] != 0 || thinned[3][x] != 0 {
+			t.Fatalf("Thinning() = %v, want the bar thinned to its middle row", thinned)
+		}
+	}
+}
+
+func TestThickening(t *testing.T) {
+	img := binaryFromRows(
+		"........",
+		"........",
+		"..###...",
+		"........",
+		"........",
+	)
+
+	once, iterations := Thickening(img, nil, 1)
+	if iterations != 1 {
+		t.Errorf("iterations = %d, want 1", iterations)
+	}
+	if !sameBinaryImage(Intersection(once, img), img) || foregroundCount(once) <= foregroundCount(img) {
+		t.Error("thickening did not grow the line or lost some of its pixels")
+	}
+
+	thickened, _ := Thickening(img, nil, 0)
+	if foregroundCount(thickened) < foregroundCount(once) {
+		t.Error("thickening until stability grew less than a single pass")
+	}
+	if again, iterations := Thickening(thickened, nil, 0); iterations != 0 || !sameBinaryImage(again, thickened) {
+		t.Error("thickening a stable image changed it")
+	}
+}
+
+func TestPruneHitOrMissRemovesSpurs(t *testing.T) {
+	img := binaryFromRows(
+		"............................",
+		".............#..............",
+		".............#..............",
+		"..########################..",
+		"............................",
+	)
+	want := binaryFromRows(
+		"............................",
+		"............................",
+		"............................",
+		"..########################..",
+		"............................",
+	)
+
+	if pruned := PruneHitOrMiss(img, 3); !sameBinaryImage(pruned, want) {
+		t.Errorf("PruneHitOrMiss() = %v, want %v", pruned, want)
+	}
+	// the end of a longer spur is an end point after thinning and grows back
+	if kept := PruneHitOrMiss(img, 1); !sameBinaryImage(kept, img) {
+		t.Errorf("PruneHitOrMiss() with a spur longer than the length = %v, want the image unchanged", kept)
+	}
+}
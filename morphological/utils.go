@@ -21,6 +21,8 @@ type StructuringElement struct {
 	Data    [][]int `json:"data"`
 	OriginX int     `json:"originX"`
 	OriginY int     `json:"originY"`
+	// Heights makes the element non-flat for grayscale operations, it is ignored by the binary ones.
+	Heights [][]int `json:"heights,omitempty"`
 }
 
 func loadEmbeddedStructureElements() (map[string]StructuringElement, error) {
@@ -38,7 +40,13 @@ func getStructureElements() (map[string]StructuringElement, error) {
 	return cachedStructureElements, cachedStructureElementsErr
 }
 
+// GetStructureElement returns the stored element of the given name or, for a shape specification
+// such as disk:5 or line:7:45, a newly generated one.
 func GetStructureElement(structureElementName string) (StructuringElement, error) {
+	if IsStructureElementSpec(structureElementName) {
+		return ParseStructureElement(structureElementName)
+	}
+
 	structureElements, err := getStructureElements()
 	if err != nil {
 		return StructuringElement{}, err
@@ -62,9 +70,34 @@ func LoadStructureElementsFromJSON(filenamePath string) (map[string]StructuringE
 		return nil, fmt.Errorf("could not parse JSON structure elements content: %w", err)
 	}
 
+	for name, structureElement := range structureElements {
+		if err := structureElement.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid structure element %s: %w", name, err)
+		}
+	}
+
 	return structureElements, nil
 }
 
+// LoadStructureElementsFile adds the elements of a JSON file to the available ones,
+// elements with the name of an already available one replace it.
+func LoadStructureElementsFile(filenamePath string) error {
+	loaded, err := LoadStructureElementsFromJSON(filenamePath)
+	if err != nil {
+		return err
+	}
+
+	structureElements, err := getStructureElements()
+	if err != nil {
+		return err
+	}
+
+	for name, structureElement := range loaded {
+		structureElements[name] = structureElement
+	}
+	return nil
+}
+
 func ReloadStructureElements() error {
 	once = sync.Once{}
 	_, err := getStructureElements()
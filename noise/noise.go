@@ -7,9 +7,9 @@ import (
 	"slices"
 )
 
-func getWindowPixelsRGB(img image.Image, x, y, windowSize int) ([]int, []int, []int) {
-	bounds := img.Bounds()
-
+// getWindowPixelsRGB collects the channel values of the window centered at (x, y).
+// Pixels outside of the image are resolved by the border policy, with BorderSkip the window shrinks near the edges.
+func getWindowPixelsRGB(img image.Image, x, y, windowSize int, border manipulations.BorderPolicy) ([]int, []int, []int) {
 	halfSize := windowSize / 2
 
 	size := (2*halfSize + 1) * (2*halfSize + 1)
@@ -17,12 +17,15 @@ func getWindowPixelsRGB(img image.Image, x, y, windowSize int) ([]int, []int, []
 
 	for j := y - halfSize; j <= y+halfSize; j++ {
 		for i := x - halfSize; i <= x+halfSize; i++ {
-			if i >= bounds.Min.X && i < bounds.Max.X && j >= bounds.Min.Y && j < bounds.Max.Y {
-				r, g, b, _ := img.At(i, j).RGBA()
-				reds = append(reds, int(r>>8))
-				greens = append(greens, int(g>>8))
-				blues = append(blues, int(b>>8))
+			pixel, ok := border.At(img, i, j)
+			if !ok {
+				continue
 			}
+
+			r, g, b, _ := pixel.RGBA()
+			reds = append(reds, int(r>>8))
+			greens = append(greens, int(g>>8))
+			blues = append(blues, int(b>>8))
 		}
 	}
 
@@ -59,15 +62,24 @@ func adaptiveChannel(newVal, xyVal, min, max, med int) int {
 	return newVal
 }
 
-func AdaptiveMedianFilter(img image.Image, sMin, sMax int) *image.RGBA {
+func AdaptiveMedianFilter(img image.Image, sMin, sMax int, border manipulations.BorderPolicy) *image.RGBA {
+	newImg, _ := AdaptiveMedianFilterWithReport(img, sMin, sMax, border)
+	return newImg
+}
+
+// AdaptiveMedianFilterWithReport applies the adaptive median filter and additionally
+// collects per-pixel diagnostics about which pixels were replaced and what window size they needed.
+func AdaptiveMedianFilterWithReport(img image.Image, sMin, sMax int, border manipulations.BorderPolicy) (*image.RGBA, AdaptiveMedianReport) {
 	// https://www.irjet.net/archives/V6/i10/IRJET-V6I10148.pdf
 	bounds := img.Bounds()
 	newImg := image.NewRGBA(bounds)
+	report := newAdaptiveMedianReport(bounds.Dx(), bounds.Dy(), sMin, sMax)
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
 
 			windowSize := sMin
+			finalWindowSize := sMin
 			var newR, newG, newB = 0, 0, 0
 
 			urxy, ugxy, ubxy, _ := img.At(x, y).RGBA()
@@ -75,7 +87,8 @@ func AdaptiveMedianFilter(img image.Image, sMin, sMax int) *image.RGBA {
 			newR, newG, newB = rxy, gxy, bxy
 
 			for windowSize <= sMax {
-				reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize)
+				finalWindowSize = windowSize
+				reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize, border)
 
 				rMin, rMax, rMed := minMaxMedian(reds)
 				gMin, gMax, gMed := minMaxMedian(greens)
@@ -92,20 +105,22 @@ func AdaptiveMedianFilter(img image.Image, sMin, sMax int) *image.RGBA {
 				windowSize += 2
 			}
 
+			report.record(x-bounds.Min.X, y-bounds.Min.Y, finalWindowSize, newR != rxy, newG != gxy, newB != bxy)
+
 			newImg.Set(x, y, color.RGBA{manipulations.ClampUint8(newR), manipulations.ClampUint8(newG), manipulations.ClampUint8(newB), 255})
 		}
 	}
 
-	return newImg
+	return newImg, report
 }
 
-func MinFilter(img image.Image, windowSize int) *image.RGBA {
+func MinFilter(img image.Image, windowSize int, border manipulations.BorderPolicy) *image.RGBA {
 	bounds := img.Bounds()
 	newImg := image.NewRGBA(bounds)
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize)
+			reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize, border)
 			rMin, _, _ := minMaxMedian(reds)
 			gMin, _, _ := minMaxMedian(greens)
 			bMin, _, _ := minMaxMedian(blues)
@@ -117,13 +132,13 @@ func MinFilter(img image.Image, windowSize int) *image.RGBA {
 	return newImg
 }
 
-func MaxFilter(img image.Image, windowSize int) *image.RGBA {
+func MaxFilter(img image.Image, windowSize int, border manipulations.BorderPolicy) *image.RGBA {
 	bounds := img.Bounds()
 	newImg := image.NewRGBA(bounds)
 
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
 		for x := bounds.Min.X; x < bounds.Max.X; x++ {
-			reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize)
+			reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize, border)
 
 			_, rMax, _ := minMaxMedian(reds)
 			_, gMax, _ := minMaxMedian(greens)
@@ -3,18 +3,22 @@ package noise
 import (
 	"image"
 	"image/color"
+	"imagio/manipulations"
 	"runtime"
 	"sync"
 )
 
-func processPixel(img image.Image, x, y, sMin, sMax int) (int, int, int) {
+// processPixel returns the filtered channels of the pixel and the last window size it tried.
+func processPixel(img image.Image, x, y, sMin, sMax int, border manipulations.BorderPolicy) (int, int, int, int) {
 	windowSize := sMin
+	finalWindowSize := sMin
 	urxy, ugxy, ubxy, _ := img.At(x, y).RGBA()
 	rxy, gxy, bxy := int(urxy>>8), int(ugxy>>8), int(ubxy>>8)
 	newR, newG, newB := rxy, gxy, bxy
 
 	for windowSize <= sMax {
-		reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize)
+		finalWindowSize = windowSize
+		reds, greens, blues := getWindowPixelsRGB(img, x, y, windowSize, border)
 		rMin, rMax, rMed := minMaxMedian(reds)
 		gMin, gMax, gMed := minMaxMedian(greens)
 		bMin, bMax, bMed := minMaxMedian(blues)
@@ -29,12 +33,21 @@ func processPixel(img image.Image, x, y, sMin, sMax int) (int, int, int) {
 		windowSize += 2
 	}
 
-	return newR, newG, newB
+	return newR, newG, newB, finalWindowSize
 }
 
-func AdaptiveMedianFilterParallel(img image.Image, sMin, sMax int) *image.RGBA {
+func AdaptiveMedianFilterParallel(img image.Image, sMin, sMax int, border manipulations.BorderPolicy) *image.RGBA {
+	newImg, _ := AdaptiveMedianFilterParallelWithReport(img, sMin, sMax, border)
+	return newImg
+}
+
+// AdaptiveMedianFilterParallelWithReport is the parallel counterpart of AdaptiveMedianFilterWithReport.
+// Workers only fill in their own pixels, the report is assembled once they are done.
+func AdaptiveMedianFilterParallelWithReport(img image.Image, sMin, sMax int, border manipulations.BorderPolicy) (*image.RGBA, AdaptiveMedianReport) {
 	bounds := img.Bounds()
 	newImg := image.NewRGBA(bounds)
+	report := newAdaptiveMedianReport(bounds.Dx(), bounds.Dy(), sMin, sMax)
+	changed := make([][3]bool, bounds.Dx()*bounds.Dy())
 
 	workersNumber := runtime.NumCPU()
 
@@ -49,7 +62,10 @@ func AdaptiveMedianFilterParallel(img image.Image, sMin, sMax int) *image.RGBA {
 
 			for pixel := range pixelChan {
 				x, y := pixel.x, pixel.y
-				newR, newG, newB := processPixel(img, x, y, sMin, sMax)
+				newR, newG, newB, windowSize := processPixel(img, x, y, sMin, sMax, border)
+				rxy, gxy, bxy, _ := img.At(x, y).RGBA()
+				report.WindowSizes[y-bounds.Min.Y][x-bounds.Min.X] = windowSize
+				changed[(y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X] = [3]bool{newR != int(rxy>>8), newG != int(gxy>>8), newB != int(bxy>>8)}
 				newImg.Set(x, y, color.RGBA{
 					uint8(newR),
 					uint8(newG),
@@ -69,6 +85,12 @@ func AdaptiveMedianFilterParallel(img image.Image, sMin, sMax int) *image.RGBA {
 
 	wg.Wait()
 
-	return newImg
+	for y := 0; y < report.Height; y++ {
+		for x := 0; x < report.Width; x++ {
+			c := changed[y*report.Width+x]
+			report.record(x, y, report.WindowSizes[y][x], c[0], c[1], c[2])
+		}
+	}
 
+	return newImg, report
 }
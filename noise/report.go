@@ -0,0 +1,134 @@
+package noise
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"slices"
+	"strings"
+)
+
+// AdaptiveMedianReport describes what the adaptive median filter did to every pixel.
+type AdaptiveMedianReport struct {
+	Width, Height int
+	MinWindowSize int
+	MaxWindowSize int
+
+	// ReplacedPixels counts pixels where at least one channel was changed.
+	ReplacedPixels int
+	// ReplacedR, ReplacedG and ReplacedB count changed values per channel.
+	ReplacedR, ReplacedG, ReplacedB int
+
+	// WindowSizeHistogram maps the final window size to the number of pixels that needed it.
+	WindowSizeHistogram map[int]int
+
+	// WindowSizes holds the final window size of every pixel, indexed as [y][x].
+	WindowSizes [][]int
+	// Modified marks pixels where at least one channel was changed, indexed as [y][x].
+	Modified [][]bool
+}
+
+func newAdaptiveMedianReport(width, height, sMin, sMax int) AdaptiveMedianReport {
+	windowSizes := make([][]int, height)
+	modified := make([][]bool, height)
+	for y := 0; y < height; y++ {
+		windowSizes[y] = make([]int, width)
+		modified[y] = make([]bool, width)
+	}
+
+	return AdaptiveMedianReport{
+		Width:               width,
+		Height:              height,
+		MinWindowSize:       sMin,
+		MaxWindowSize:       sMax,
+		WindowSizeHistogram: make(map[int]int),
+		WindowSizes:         windowSizes,
+		Modified:            modified,
+	}
+}
+
+func (r *AdaptiveMedianReport) record(x, y, windowSize int, changedR, changedG, changedB bool) {
+	r.WindowSizes[y][x] = windowSize
+	r.WindowSizeHistogram[windowSize]++
+
+	if changedR {
+		r.ReplacedR++
+	}
+	if changedG {
+		r.ReplacedG++
+	}
+	if changedB {
+		r.ReplacedB++
+	}
+
+	if changedR || changedG || changedB {
+		r.Modified[y][x] = true
+		r.ReplacedPixels++
+	}
+}
+
+// TotalPixels returns the number of pixels processed by the filter.
+func (r AdaptiveMedianReport) TotalPixels() int {
+	return r.Width * r.Height
+}
+
+// Summary returns a single line description of the report suitable for the execution report.
+func (r AdaptiveMedianReport) Summary() string {
+	total := r.TotalPixels()
+	percentage := 0.0
+	if total > 0 {
+		percentage = float64(r.ReplacedPixels) / float64(total) * 100
+	}
+
+	windowSizes := make([]int, 0, len(r.WindowSizeHistogram))
+	for size := range r.WindowSizeHistogram {
+		windowSizes = append(windowSizes, size)
+	}
+	slices.Sort(windowSizes)
+
+	histogramParts := make([]string, 0, len(windowSizes))
+	for _, size := range windowSizes {
+		histogramParts = append(histogramParts, fmt.Sprintf("%dx%d=%d", size, size, r.WindowSizeHistogram[size]))
+	}
+
+	return fmt.Sprintf("Replaced pixels: %d/%d (%.2f%%), per channel R=%d G=%d B=%d, final window sizes %s",
+		r.ReplacedPixels, total, percentage, r.ReplacedR, r.ReplacedG, r.ReplacedB, strings.Join(histogramParts, " "))
+}
+
+// WindowSizeHeatMap renders the final window size of every pixel,
+// the smallest window is drawn blue and the largest red.
+func (r AdaptiveMedianReport) WindowSizeHeatMap() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, r.Width, r.Height))
+
+	span := float64(r.MaxWindowSize - r.MinWindowSize)
+
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			value := 0.0
+			if span > 0 {
+				value = float64(r.WindowSizes[y][x]-r.MinWindowSize) / span
+			}
+			img.SetRGBA(x, y, manipulations.HeatMapColor(value))
+		}
+	}
+
+	return img
+}
+
+// ModifiedMask renders modified pixels in white and untouched pixels in black.
+func (r AdaptiveMedianReport) ModifiedMask() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, r.Width, r.Height))
+
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			if r.Modified[y][x] {
+				img.SetRGBA(x, y, color.RGBA{255, 255, 255, 255})
+			} else {
+				img.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+			}
+		}
+	}
+
+	return img
+}
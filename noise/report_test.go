@@ -0,0 +1,99 @@
+package noise
+
+import (
+	"image"
+	"image/color"
+	"imagio/manipulations"
+	"reflect"
+	"testing"
+)
+
+// impulseImage returns a 9x9 image of gray 100 with the given pixels set to the given gray values.
+func impulseImage(impulses map[image.Point]uint8) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 9, 9))
+	for y := 0; y < 9; y++ {
+		for x := 0; x < 9; x++ {
+			img.Set(x, y, color.Gray{Y: 100})
+		}
+	}
+	for p, value := range impulses {
+		img.Set(p.X, p.Y, color.Gray{Y: value})
+	}
+	return img
+}
+
+func TestAdaptiveMedianReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		impulses map[image.Point]uint8
+		modified []image.Point
+		summary  string
+	}{
+		{
+			name:    "no impulses",
+			summary: "Replaced pixels: 0/81 (0.00%), per channel R=0 G=0 B=0, final window sizes 7x7=81",
+		},
+		{
+			name:     "salt and pepper pair",
+			impulses: map[image.Point]uint8{{4, 4}: 255, {5, 4}: 0},
+			modified: []image.Point{{4, 4}, {5, 4}},
+			summary:  "Replaced pixels: 2/81 (2.47%), per channel R=2 G=2 B=2, final window sizes 3x3=2 7x7=79",
+		},
+		{
+			name:     "separated salt and pepper",
+			impulses: map[image.Point]uint8{{1, 1}: 255, {7, 7}: 0},
+			modified: []image.Point{},
+			summary:  "Replaced pixels: 0/81 (0.00%), per channel R=0 G=0 B=0, final window sizes 7x7=81",
+		},
+	}
+
+	for _, tt := range tests {
+		img := impulseImage(tt.impulses)
+		filtered, report := AdaptiveMedianFilterWithReport(img, 3, 7, manipulations.DefaultBorderPolicy)
+		_, parallelReport := AdaptiveMedianFilterParallelWithReport(img, 3, 7, manipulations.DefaultBorderPolicy)
+
+		if !reflect.DeepEqual(report, parallelReport) {
+			t.Errorf("%s: parallel report differs from the sequential one", tt.name)
+		}
+		if got := report.Summary(); got != tt.summary {
+			t.Errorf("%s: Summary() = %q, expected %q", tt.name, got, tt.summary)
+		}
+
+		mask := report.ModifiedMask()
+		heatMap := report.WindowSizeHeatMap()
+		for y := 0; y < 9; y++ {
+			for x := 0; x < 9; x++ {
+				p := image.Point{X: x, Y: y}
+				impulse := false
+				for _, m := range tt.modified {
+					impulse = impulse || m == p
+				}
+
+				expectedMask := color.RGBA{0, 0, 0, 255}
+				if impulse {
+					expectedMask = color.RGBA{255, 255, 255, 255}
+					if got := filtered.RGBAAt(x, y); got != (color.RGBA{100, 100, 100, 255}) {
+						t.Errorf("%s: impulse at %v filtered to %v, expected the background", tt.name, p, got)
+					}
+				}
+				if got := mask.RGBAAt(x, y); got != expectedMask {
+					t.Errorf("%s: ModifiedMask() at %v = %v, expected %v", tt.name, p, got, expectedMask)
+				}
+
+				expectedHeat := manipulations.HeatMapColor(float64(report.WindowSizes[y][x]-3) / 4)
+				if got := heatMap.RGBAAt(x, y); got != expectedHeat {
+					t.Errorf("%s: WindowSizeHeatMap() at %v = %v, expected %v", tt.name, p, got, expectedHeat)
+				}
+			}
+		}
+
+		for _, p := range tt.modified {
+			if report.WindowSizes[p.Y][p.X] != 3 {
+				t.Errorf("%s: impulse at %v needed a %dx%d window, expected 3x3", tt.name, p, report.WindowSizes[p.Y][p.X], report.WindowSizes[p.Y][p.X])
+			}
+			if got := heatMap.RGBAAt(p.X, p.Y); got != (color.RGBA{0, 0, 255, 255}) {
+				t.Errorf("%s: WindowSizeHeatMap() of the smallest window = %v, expected blue", tt.name, got)
+			}
+		}
+	}
+}